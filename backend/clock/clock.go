@@ -0,0 +1,71 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of "now" for stored timestamps (CreatedAt/UpdatedAt, LastActive,
+// webhook OccurredAt). Production uses the real clock; tests install a Fixed one so
+// time-dependent behaviour is deterministic.
+type Clock interface {
+	Now() time.Time
+}
+
+// Real reads the system clock
+type Real struct{}
+
+// Now returns the current system time in UTC
+func (Real) Now() time.Time { return time.Now().UTC() }
+
+var (
+	mu      sync.RWMutex
+	current Clock = Real{}
+)
+
+// Now returns the current time from the installed clock
+func Now() time.Time {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current.Now()
+}
+
+// Set installs c as the clock and returns a func that restores the previous one:
+//
+//	defer clock.Set(clock.NewFixed(t0))()
+func Set(c Clock) (restore func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	previous := current
+	current = c
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		current = previous
+	}
+}
+
+// Fixed is a clock that only moves when told to
+type Fixed struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFixed returns a clock frozen at t
+func NewFixed(t time.Time) *Fixed {
+	return &Fixed{now: t.UTC()}
+}
+
+// Now returns the frozen time
+func (f *Fixed) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Advance moves the clock forward by d
+func (f *Fixed) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
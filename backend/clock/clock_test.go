@@ -0,0 +1,42 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"hub-control-plane/backend/clock"
+	"hub-control-plane/backend/models"
+)
+
+func TestFixedClockDrivesTimestamps(t *testing.T) {
+	t0 := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	fixed := clock.NewFixed(t0)
+	defer clock.Set(fixed)()
+
+	user := models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")
+	user.SetTimestamps()
+	if !user.CreatedAt.Equal(t0) || !user.UpdatedAt.Equal(t0) {
+		t.Fatalf("timestamps = %s / %s, want %s", user.CreatedAt, user.UpdatedAt, t0)
+	}
+
+	fixed.Advance(time.Hour)
+	user.SetTimestamps()
+	if !user.CreatedAt.Equal(t0) {
+		t.Errorf("CreatedAt moved to %s on update", user.CreatedAt)
+	}
+	if want := t0.Add(time.Hour); !user.UpdatedAt.Equal(want) {
+		t.Errorf("UpdatedAt = %s, want %s", user.UpdatedAt, want)
+	}
+}
+
+func TestSetRestoresPreviousClock(t *testing.T) {
+	restore := clock.Set(clock.NewFixed(time.Unix(0, 0)))
+	if got := clock.Now(); !got.Equal(time.Unix(0, 0)) {
+		t.Fatalf("Now() = %s with fixed clock installed", got)
+	}
+	restore()
+
+	if got := clock.Now(); time.Since(got) > time.Minute {
+		t.Errorf("Now() = %s after restore, want the real time", got)
+	}
+}
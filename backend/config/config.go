@@ -2,42 +2,253 @@ package config
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 )
 
 type Config struct {
-	Port               string
-	AWSRegion          string
-	DynamoDBTableName  string
-	ContactTableName   string
-	RedisAddress       string
-	RedisPassword      string
-	CacheTTL           int
+	Port                 string
+	AWSRegion            string
+	DynamoDBTableName    string
+	ContactTableName     string
+	RedisAddress         string
+	RedisPassword        string
+	RedisMode            string        // "single" (default), "cluster" or "sentinel"
+	RedisAddresses       []string      // Cluster seed nodes or sentinels (empty = RedisAddress)
+	RedisMasterName      string        // Sentinel master name (sentinel mode)
+	RedisStartupAttempts int           // Pings to wait for Redis before serving (0 = don't wait)
+	RedisStartupBackoff  time.Duration // Delay after the first failed ping; doubles per retry up to 30s
+	RedisStartupDegraded bool          // Serve without Redis if it never answers, instead of exiting
+	UserCacheTTL         time.Duration // Individual user and contact entries
+	ListCacheTTL         time.Duration // users:list and per-user contact lists
+	DashboardCacheTTL    time.Duration // Aggregated dashboards
+	CacheStaleWindow     time.Duration // Serve user/contact-list entries this long past TTL while refreshing (0 = off)
+	CacheMaxListItems    int           // Lists longer than this are not cached (0 = no limit)
+	CacheMaxListBytes    int           // Lists larger than this once marshalled are not cached (0 = no limit)
+	CacheStrategyUser    string        // "cache-aside" (default) or "write-through"
+	CacheStrategyContact string        // "cache-aside" (default) or "write-through"
+	CacheReadRepair      bool          // Invalidate users:list when GetUser sees a newer user
+	CacheSlidingExpiry   bool          // Reset user/contact entries to their full TTL on every cache hit
+	CacheCodec           string        // "json" (default) or "msgpack"; the format new entries are written in
+
+	// Pagination
+	CursorSecret string // HMAC key for signing pagination cursors (shared by all instances; empty = random per process)
+	MaxPageSize  int    // Largest page any list returns; bigger limits are clamped
+
+	// Soft delete
+	TrashRetention     time.Duration // How long deleted contacts stay restorable
+	TrashPurgeInterval time.Duration // How often expired trash is hard-deleted (0 = purge job off)
+
+	// Transactions
+	TxMaxItems int // Writes per DynamoDB transaction when bulk writes are chunked (max 100)
+
+	// Contact validation
+	ContactEmailDomains []string // Allowed contact email domains for users without their own list (empty = any)
+	CloneKeepsFavorite  bool     // Cloned contacts keep the source's favorite flag
+
+	// Contact lists
+	ContactListIndex string // GSI keyed PK/SK like the table that contact lists read, for a narrower projection (empty = the table)
+
+	// Contact avatars
+	AvatarBucket    string        // S3 bucket clients upload avatars to (empty = avatar uploads off)
+	AvatarMaxBytes  int64         // Largest avatar a presigned upload accepts
+	AvatarUploadTTL time.Duration // How long a presigned upload URL stays valid
+
+	// Feature flags
+	FeatureFlags        []string      // Flag defaults, e.g. "write_through=off,favorites_index=on"
+	FeatureFlagsRefresh time.Duration // How often the feature_flags Redis overrides are re-read (0 = read once at startup)
+
+	// Webhooks
+	WebhookURLs   []string // Endpoints notified of contact lifecycle events
+	WebhookSecret string   // HMAC key for the X-Hub-Signature-256 header
+
+	// Contact enrichment
+	EnrichmentURL     string // Provider lookup endpoint, called as <url>?email=<email> (empty = enrichment off)
+	EnrichmentAPIKey  string // Bearer token for the provider
+	EnrichmentWorkers int    // Concurrent enrichment lookups
+	EnrichmentRate    int    // Provider calls per second, across all workers
+
+	// Diagnostics
+	DynamoDBConsumedCapacity bool // Log RCU/WCU per DynamoDB call
+	DynamoDBSlowQueryMs      int  // Warn about DynamoDB calls slower than this (0 = off)
+	DynamoDBValidateAttrs    bool // Log fetched items missing attributes their model expects (development)
+	DynamoDBLogConflicts     bool // Log updates that overwrote a write made since the request read the item (debug)
+	DynamoDBItemSizeMetrics  bool // Record written item sizes and warn about items nearing the 400KB limit
+
+	// Multi-tenancy
+	TenantTables bool // Route tenant sessions to <tenant>-<DynamoDBTableName> (see package tenant)
+
+	// Encryption
+	KMSKeyID        string   // KMS key for envelope-encrypting designated attributes (empty = off)
+	EncryptedFields []string // DynamoDB attribute names to encrypt
+
+	// Tracing
+	OTLPEndpoint string // OTLP/HTTP collector URL, e.g. http://otel-collector:4318 (empty = tracing off)
+	ServiceName  string // service.name reported on every span
+
+	// GraphQL
+	GraphQLAPQTTL time.Duration // How long automatic persisted queries stay registered in Redis
+
+	// Admin
+	AdminToken string // Bearer token for /api/v1/admin routes (empty = admin routes disabled)
+
+	// Auth
+	SessionTTL int // Session lifetime in seconds
 }
 
 func LoadConfig() *Config {
 	return &Config{
-		Port:               getEnv("PORT", "8081"),
-		AWSRegion:          getEnv("AWS_REGION", "us-east-1"),
-		DynamoDBTableName:  getEnv("DYNAMODB_TABLE_NAME", "application-table"),
-		RedisAddress:       getEnv("REDIS_ADDRESS", "localhost:6379"),
-		RedisPassword:      getEnv("REDIS_PASSWORD", ""),
-		CacheTTL:           300, // 5 minutes default
+		Port:                 getEnv("PORT", "8081"),
+		AWSRegion:            getEnv("AWS_REGION", ""), // empty = defer to the SDK region chain
+		DynamoDBTableName:    getEnv("DYNAMODB_TABLE_NAME", "application-table"),
+		RedisAddress:         getEnv("REDIS_ADDRESS", "localhost:6379"),
+		RedisPassword:        getEnv("REDIS_PASSWORD", ""),
+		RedisMode:            getEnv("REDIS_MODE", "single"),
+		RedisAddresses:       getEnvList("REDIS_ADDRESSES"),
+		RedisMasterName:      getEnv("REDIS_MASTER_NAME", ""),
+		RedisStartupAttempts: getEnvInt("REDIS_STARTUP_ATTEMPTS", 0),
+		RedisStartupBackoff:  getEnvDuration("REDIS_STARTUP_BACKOFF", time.Second),
+		RedisStartupDegraded: getEnvBool("REDIS_STARTUP_DEGRADED", false),
+		UserCacheTTL:         getEnvDuration("USER_CACHE_TTL", 5*time.Minute),
+		ListCacheTTL:         getEnvDuration("LIST_CACHE_TTL", 5*time.Minute),
+		DashboardCacheTTL:    getEnvDuration("DASHBOARD_CACHE_TTL", 2*time.Minute),
+		CacheStaleWindow:     getEnvDuration("CACHE_STALE_WHILE_REVALIDATE", 0),
+		CacheMaxListItems:    getEnvInt("CACHE_MAX_LIST_ITEMS", 1000),
+		CacheMaxListBytes:    getEnvInt("CACHE_MAX_LIST_BYTES", 1<<20), // 1 MB
+		CacheStrategyUser:    getEnv("CACHE_STRATEGY_USER", "cache-aside"),
+		CacheStrategyContact: getEnv("CACHE_STRATEGY_CONTACT", "cache-aside"),
+		CacheReadRepair:      getEnvBool("CACHE_READ_REPAIR", false),
+		CacheSlidingExpiry:   getEnvBool("CACHE_SLIDING_EXPIRY", false),
+		CacheCodec:           getEnv("CACHE_CODEC", "json"),
+
+		CursorSecret: getEnv("PAGINATION_CURSOR_SECRET", ""),
+		MaxPageSize:  getEnvInt("MAX_PAGE_SIZE", 1000),
+
+		TrashRetention:     getEnvDuration("CONTACT_TRASH_RETENTION", 30*24*time.Hour),
+		TrashPurgeInterval: getEnvDuration("TRASH_PURGE_INTERVAL", 0),
+
+		TxMaxItems: getEnvInt("TX_MAX_ITEMS", 100),
+
+		ContactEmailDomains: getEnvList("CONTACT_EMAIL_DOMAINS"),
+		CloneKeepsFavorite:  getEnvBool("CLONE_KEEPS_FAVORITE", false),
+
+		ContactListIndex: getEnv("CONTACT_LIST_INDEX", ""),
+
+		AvatarBucket:    getEnv("AVATAR_BUCKET", ""),
+		AvatarMaxBytes:  int64(getEnvInt("AVATAR_MAX_BYTES", 5<<20)), // 5 MB
+		AvatarUploadTTL: getEnvDuration("AVATAR_UPLOAD_URL_TTL", 15*time.Minute),
+
+		FeatureFlags:        getEnvList("FEATURE_FLAGS"),
+		FeatureFlagsRefresh: getEnvDuration("FEATURE_FLAGS_REFRESH", 30*time.Second),
+
+		WebhookURLs:   getEnvList("WEBHOOK_URLS"),
+		WebhookSecret: getEnv("WEBHOOK_SECRET", ""),
+
+		EnrichmentURL:     getEnv("ENRICHMENT_URL", ""),
+		EnrichmentAPIKey:  getEnv("ENRICHMENT_API_KEY", ""),
+		EnrichmentWorkers: getEnvInt("ENRICHMENT_WORKERS", 2),
+		EnrichmentRate:    getEnvInt("ENRICHMENT_RATE_PER_SEC", 5),
+
+		DynamoDBConsumedCapacity: getEnvBool("DYNAMODB_CONSUMED_CAPACITY", false),
+		DynamoDBSlowQueryMs:      getEnvInt("DYNAMODB_SLOW_QUERY_MS", 200),
+		DynamoDBValidateAttrs:    getEnvBool("DYNAMODB_VALIDATE_ATTRIBUTES", false),
+		DynamoDBLogConflicts:     getEnvBool("DYNAMODB_LOG_CONFLICTS", false),
+		DynamoDBItemSizeMetrics:  getEnvBool("DYNAMODB_ITEM_SIZE_METRICS", false),
+
+		TenantTables: getEnvBool("TENANT_TABLES", false),
+
+		KMSKeyID:        getEnv("KMS_KEY_ID", ""),
+		EncryptedFields: getEnvListDefault("ENCRYPTED_FIELDS", []string{"Notes"}),
+
+		OTLPEndpoint: getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		ServiceName:  getEnv("OTEL_SERVICE_NAME", "hub-control-plane"),
+
+		GraphQLAPQTTL: getEnvDuration("GRAPHQL_APQ_TTL", 24*time.Hour),
+
+		AdminToken: getEnv("ADMIN_API_TOKEN", ""),
+
+		SessionTTL: getEnvInt("SESSION_TTL_SECONDS", 24*60*60), // 1 day
 	}
 }
 
-func NewAWSConfig(region string) aws.Config {
-	cfg, err := config.LoadDefaultConfig(context.Background(),
-		config.WithRegion(region),
-	)
+// Validate reports the first setting the server can't start with. It only checks
+// values that are required or must parse; optional features left empty are fine.
+func (c *Config) Validate() error {
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("PORT must be a port number, got %q", c.Port)
+	}
+	if c.DynamoDBTableName == "" {
+		return errors.New("DYNAMODB_TABLE_NAME is required")
+	}
+	if c.RedisAddress == "" && len(c.RedisAddresses) == 0 {
+		return errors.New("REDIS_ADDRESS or REDIS_ADDRESSES is required")
+	}
+	switch c.RedisMode {
+	case "single", "cluster":
+	case "sentinel":
+		if c.RedisMasterName == "" {
+			return errors.New("REDIS_MASTER_NAME is required when REDIS_MODE is sentinel")
+		}
+	default:
+		return fmt.Errorf("REDIS_MODE must be single, cluster or sentinel, got %q", c.RedisMode)
+	}
+	if c.TxMaxItems < 1 || c.TxMaxItems > 100 {
+		return fmt.Errorf("TX_MAX_ITEMS must be between 1 and 100, got %d", c.TxMaxItems)
+	}
+	if c.MaxPageSize < 1 {
+		return fmt.Errorf("MAX_PAGE_SIZE must be positive, got %d", c.MaxPageSize)
+	}
+	if c.SessionTTL < 1 {
+		return fmt.Errorf("SESSION_TTL_SECONDS must be positive, got %d", c.SessionTTL)
+	}
+	if c.KMSKeyID != "" && len(c.EncryptedFields) == 0 {
+		return errors.New("KMS_KEY_ID is set but ENCRYPTED_FIELDS is empty")
+	}
+	return nil
+}
+
+// RedisNodes returns the addresses the Redis client connects to: REDIS_ADDRESSES when
+// set, else REDIS_ADDRESS alone
+func (c *Config) RedisNodes() []string {
+	if len(c.RedisAddresses) > 0 {
+		return c.RedisAddresses
+	}
+	return []string{c.RedisAddress}
+}
+
+// NewAWSConfig loads the AWS SDK configuration using the standard resolution chain
+// (env vars, shared config/credentials files, EC2/ECS metadata). The region comes from
+// AWS_REGION, then AWS_DEFAULT_REGION, then the SDK chain (shared config profile,
+// instance metadata); an empty region argument skips straight to the SDK chain.
+func NewAWSConfig(region string) (aws.Config, error) {
+	var opts []func(*config.LoadOptions) error
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region != "" {
+		opts = append(opts, config.WithRegion(region))
+	} else {
+		// Last resort before giving up: ask instance metadata
+		opts = append(opts, config.WithEC2IMDSRegion())
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), opts...)
 	if err != nil {
-		log.Fatalf("Unable to load AWS SDK config: %v", err)
+		return aws.Config{}, fmt.Errorf("unable to load AWS SDK config: %w", err)
+	}
+	if cfg.Region == "" {
+		return aws.Config{}, errors.New("no AWS region configured (set AWS_REGION or AWS_DEFAULT_REGION)")
 	}
-	return cfg
+
+	return cfg, nil
 }
 
 func getEnv(key, defaultValue string) string {
@@ -45,4 +256,54 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+		log.Printf("Warning: invalid boolean for %s: %q, using default %t", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+		log.Printf("Warning: invalid integer for %s: %q, using default %d", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+// getEnvDuration reads a Go duration string such as "90s" or "5m"
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			return parsed
+		}
+		log.Printf("Warning: invalid duration for %s: %q, using default %s", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+// getEnvListDefault is getEnvList with a fallback for an unset or empty variable
+func getEnvListDefault(key string, defaultValue []string) []string {
+	if values := getEnvList(key); len(values) > 0 {
+		return values
+	}
+	return defaultValue
+}
+
+// getEnvList reads a comma-separated list, dropping blank entries
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,884 @@
+// Package docs holds the hand-maintained OpenAPI 3 description of the REST API.
+// It is served at GET /openapi.json; main_test.go checks it against the routes
+// registered in setupRouter so the two can't drift apart.
+package docs
+
+// ============================================================================
+// OpenAPI document types (the subset we use)
+// ============================================================================
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps a lowercase HTTP method to its operation
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Summary     string                `json:"summary"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+// ============================================================================
+// Builders
+// ============================================================================
+
+func ref(name string) *Schema { return &Schema{Ref: "#/components/schemas/" + name} }
+
+func str() *Schema { return &Schema{Type: "string"} }
+
+func strFormat(format string) *Schema { return &Schema{Type: "string", Format: format} }
+
+func integer() *Schema { return &Schema{Type: "integer"} }
+
+func boolean() *Schema { return &Schema{Type: "boolean"} }
+
+func arrayOf(items *Schema) *Schema { return &Schema{Type: "array", Items: items} }
+
+func object(required []string, props map[string]*Schema) *Schema {
+	return &Schema{Type: "object", Required: required, Properties: props}
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+func jsonBody(schema *Schema) *RequestBody {
+	return &RequestBody{Required: true, Content: jsonContent(schema)}
+}
+
+func ok(description string, schema *Schema) Response {
+	return Response{Description: description, Content: jsonContent(schema)}
+}
+
+// errorResponse is the { "error": "..." } envelope every handler uses for failures
+func errorResponse(description string) Response {
+	return Response{Description: description, Content: jsonContent(ref("Error"))}
+}
+
+func pathParam(name, description string) Parameter {
+	return Parameter{Name: name, In: "path", Required: true, Description: description, Schema: str()}
+}
+
+func queryParam(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+var (
+	ifUnmodifiedSinceParam = Parameter{Name: "If-Unmodified-Since", In: "header", Description: "HTTP date; the update is refused with 412 if the item changed after it", Schema: str()}
+
+	userIDParam    = pathParam("id", "User ID")
+	contactIDParam = pathParam("contactId", "Contact ID")
+
+	limitParam  = queryParam("limit", "Page size; values above the server's max page size are clamped", &Schema{Type: "integer", Minimum: intPtr(1)})
+	cursorParam = queryParam("cursor", "Opaque cursor from a previous page's next_cursor", str())
+	formatParam = queryParam("format", "ndjson streams every item as newline-delimited JSON instead of a page", &Schema{Type: "string", Enum: []string{"ndjson"}})
+	fieldsParam = queryParam("fields", "Comma-separated JSON fields to return for each user or contact (default all); an unknown name is a 400", str())
+)
+
+func intPtr(v int) *int { return &v }
+
+// listPage describes the paginated envelope {<key>, count, total, limit, next_cursor}
+func listPage(key, item string) *Schema {
+	return object([]string{key, "count", "total", "limit", "next_cursor"}, map[string]*Schema{
+		key:           arrayOf(ref(item)),
+		"count":       integer(),
+		"total":       integer(),
+		"limit":       integer(),
+		"next_cursor": &Schema{Type: "string", Description: "Empty on the last page"},
+	})
+}
+
+// ============================================================================
+// Spec
+// ============================================================================
+
+// OpenAPI returns the OpenAPI 3 document for the REST API
+func OpenAPI() *Document {
+	users := []string{"users"}
+	contacts := []string{"contacts"}
+	admin := []string{"admin"}
+	adminAuth := []map[string][]string{{"adminToken": {}}}
+	sessionAuth := []map[string][]string{{"sessionToken": {}}}
+
+	return &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "hub-control-plane REST API",
+			Version:     "2.0.0",
+			Description: "Users and their contacts. Failures return {\"error\": \"...\"} with a 4xx/5xx status.",
+		},
+		Paths: map[string]PathItem{
+			"/health": {
+				"get": {
+					Summary:   "Service health",
+					Responses: map[string]Response{"200": ok("Service is up", object(nil, map[string]*Schema{"status": str(), "version": str(), "commit": str(), "build_date": str()}))},
+				},
+			},
+
+			// Users
+			"/api/v1/users": {
+				"post": {
+					Summary:     "Create a user",
+					Tags:        users,
+					RequestBody: jsonBody(ref("CreateUserRequest")),
+					Responses: map[string]Response{
+						"201": ok("Created user", ref("User")),
+						"400": errorResponse("Invalid request"),
+						"409": errorResponse("Another user has this email (case-insensitive)"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+				"get": {
+					Summary: "List users",
+					Tags:    users,
+					Parameters: []Parameter{
+						limitParam,
+						cursorParam,
+						formatParam,
+						fieldsParam,
+						{Name: "sort", In: "query", Description: "Sort the whole list before paging (default GSI1 order); not supported with format=ndjson", Schema: &Schema{Type: "string", Enum: []string{"email", "created", "name"}}},
+						{Name: "order", In: "query", Description: "Sort direction (default asc)", Schema: &Schema{Type: "string", Enum: []string{"asc", "desc"}}},
+					},
+					Responses: map[string]Response{
+						"200": ok("A page of users", listPage("users", "User")),
+						"400": errorResponse("Invalid limit, cursor, fields, sort or order"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/bulk": {
+				"post": {
+					Summary:     "Create many users; emails are normalized and must be unique",
+					Tags:        users,
+					RequestBody: jsonBody(&Schema{Type: "array", Items: ref("CreateUserRequest"), Description: "1 to 1000 users"}),
+					Responses: map[string]Response{
+						"201": ok("One result per input, in order", object([]string{"results"}, map[string]*Schema{
+							"results": arrayOf(object([]string{"index", "status"}, map[string]*Schema{
+								"index":  integer(),
+								"status": &Schema{Type: "string", Enum: []string{"created", "conflict", "error"}},
+								"user":   ref("User"),
+								"error":  str(),
+							})),
+						})),
+						"400": errorResponse("Empty or oversized batch"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/by-created": {
+				"get": {
+					Summary: "List users created in a date range, oldest first",
+					Tags:    users,
+					Parameters: []Parameter{
+						{Name: "from", In: "query", Required: true, Description: "Start of the range (RFC3339, inclusive)", Schema: strFormat("date-time")},
+						{Name: "to", In: "query", Required: true, Description: "End of the range (RFC3339, inclusive)", Schema: strFormat("date-time")},
+						fieldsParam,
+					},
+					Responses: map[string]Response{
+						"200": ok("Users created in the range", object([]string{"users", "count"}, map[string]*Schema{
+							"users": arrayOf(ref("User")),
+							"count": integer(),
+						})),
+						"400": errorResponse("Missing or invalid from/to, or invalid fields"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/by-email": {
+				"get": {
+					Summary:    "Find a user by email (case-insensitive)",
+					Tags:       users,
+					Parameters: []Parameter{{Name: "email", In: "query", Required: true, Schema: strFormat("email")}, fieldsParam},
+					Responses: map[string]Response{
+						"200": ok("User", ref("User")),
+						"400": errorResponse("Missing email or invalid fields"),
+						"404": errorResponse("User not found"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/{id}": {
+				"get": {
+					Summary:    "Get a user",
+					Tags:       users,
+					Parameters: []Parameter{userIDParam, fieldsParam},
+					Responses: map[string]Response{
+						"200": ok("User", ref("User")),
+						"400": errorResponse("Invalid fields"),
+						"404": errorResponse("User not found"),
+					},
+				},
+				"put": {
+					Summary:     "Update a user",
+					Tags:        users,
+					Parameters:  []Parameter{userIDParam, ifUnmodifiedSinceParam},
+					RequestBody: jsonBody(&Schema{Type: "object", Description: "Attributes to change", AdditionalProperties: &Schema{}}),
+					Responses: map[string]Response{
+						"200": ok("Updated user", ref("User")),
+						"400": errorResponse("Invalid request"),
+						"409": errorResponse("Another user has the new email (case-insensitive)"),
+						"412": errorResponse("User was modified after If-Unmodified-Since"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+				"delete": {
+					Summary:    "Delete a user",
+					Tags:       users,
+					Parameters: []Parameter{userIDParam, queryParam("cascade", "true also deletes every contact the user owns", boolean())},
+					Responses: map[string]Response{
+						"200": ok("User deleted", ref("Message")),
+						"404": errorResponse("User not found"),
+						"409": errorResponse("User still has contacts (response includes contact_count)"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/{id}/export": {
+				"get": {
+					Summary:    "Download everything stored about the user (data export); streamed, only for the user themselves",
+					Tags:       users,
+					Security:   sessionAuth,
+					Parameters: []Parameter{userIDParam},
+					Responses: map[string]Response{
+						"200": ok("Data package, sent as an attachment. A failure mid-stream truncates it", object([]string{"exported_at", "user", "contacts", "deleted_contacts", "audit"}, map[string]*Schema{
+							"exported_at":      strFormat("date-time"),
+							"user":             ref("User"),
+							"contacts":         arrayOf(ref("Contact")),
+							"deleted_contacts": arrayOf(ref("Contact")),
+							"audit":            arrayOf(ref("AuditEntry")),
+						})),
+						"401": errorResponse("Not authenticated"),
+						"403": errorResponse("Not the user's own data"),
+						"404": errorResponse("User not found"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+
+			// Contacts
+			"/api/v1/users/{id}/contacts": {
+				"post": {
+					Summary:     "Create a contact",
+					Tags:        contacts,
+					Parameters:  []Parameter{userIDParam, queryParam("dedupe", "true rejects a contact whose email the user already has", boolean())},
+					RequestBody: jsonBody(ref("CreateContactRequest")),
+					Responses: map[string]Response{
+						"200": ok("A contact with the supplied id already existed and is returned unchanged", ref("Contact")),
+						"201": ok("Created contact", ref("Contact")),
+						"400": errorResponse("Invalid request, or email domain not allowed"),
+						"404": errorResponse("User not found"),
+						"409": errorResponse("Duplicate email (with dedupe=true)"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+				"get": {
+					Summary:    "List a user's contacts",
+					Tags:       contacts,
+					Parameters: []Parameter{userIDParam, limitParam, cursorParam, formatParam, fieldsParam},
+					Responses: map[string]Response{
+						"200": ok("A page of contacts", listPage("contacts", "Contact")),
+						"400": errorResponse("Invalid limit, cursor or fields"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/{id}/contacts/import": {
+				"post": {
+					Summary: "Import many contacts",
+					Tags:    contacts,
+					Parameters: []Parameter{
+						userIDParam,
+						queryParam("dry_run", "true validates and dedupes without writing", boolean()),
+						queryParam("on_conflict", "What to do with rows matching an existing contact's email (default skip)",
+							&Schema{Type: "string", Enum: []string{"skip", "overwrite", "merge"}}),
+					},
+					RequestBody: jsonBody(object([]string{"contacts"}, map[string]*Schema{
+						"contacts": arrayOf(ref("ContactInput")),
+					})),
+					Responses: map[string]Response{
+						"200": ok("Dry run result", ref("ImportResult")),
+						"201": ok("Import result", ref("ImportResult")),
+						"400": errorResponse("Invalid request"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/{id}/contacts/bulk-update": {
+				"post": {
+					Summary:    "Apply the same update to many contacts; each transaction-sized chunk applies all-or-nothing, but chunks apply independently",
+					Tags:       contacts,
+					Parameters: []Parameter{userIDParam},
+					RequestBody: jsonBody(object([]string{"ids", "updates"}, map[string]*Schema{
+						"ids":     arrayOf(str()),
+						"updates": {Type: "object", AdditionalProperties: &Schema{}},
+						"atomic":  &Schema{Type: "boolean", Description: "Apply all or nothing; batches larger than one transaction (TX_MAX_ITEMS, default 100) are rejected"},
+					})),
+					Responses: map[string]Response{
+						"200": ok("Per-contact results", object([]string{"results"}, map[string]*Schema{
+							"results": arrayOf(ref("BulkUpdateResult")),
+						})),
+						"400": errorResponse("Invalid request, or an atomic batch larger than one transaction"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/{id}/tags/rename": {
+				"post": {
+					Summary:    "Rename a tag on every contact of the user that has it; a contact that already has the new tag just loses the old one",
+					Tags:       contacts,
+					Parameters: []Parameter{userIDParam},
+					RequestBody: jsonBody(object([]string{"from", "to"}, map[string]*Schema{
+						"from": str(),
+						"to":   str(),
+					})),
+					Responses: map[string]Response{
+						"200": ok("Contacts changed", ref("TagChangeResult")),
+						"400": errorResponse("Missing tag, or from equals to"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/{id}/tags/remove": {
+				"post": {
+					Summary:    "Remove a tag from every contact of the user",
+					Tags:       contacts,
+					Parameters: []Parameter{userIDParam},
+					RequestBody: jsonBody(object([]string{"tag"}, map[string]*Schema{
+						"tag": str(),
+					})),
+					Responses: map[string]Response{
+						"200": ok("Contacts changed", ref("TagChangeResult")),
+						"400": errorResponse("Missing tag"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/{id}/contacts/batch-get": {
+				"post": {
+					Summary:    "Fetch specific contacts by ID",
+					Tags:       contacts,
+					Parameters: []Parameter{userIDParam},
+					RequestBody: jsonBody(object([]string{"ids"}, map[string]*Schema{
+						"ids": {Type: "array", Items: str(), Description: "At most 100"},
+					})),
+					Responses: map[string]Response{
+						"200": ok("Contacts in the order of ids; null where the user has no such contact", object([]string{"contacts"}, map[string]*Schema{
+							"contacts": arrayOf(ref("Contact")),
+						})),
+						"400": errorResponse("Invalid request or more than 100 ids"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/{id}/contacts/favorites": {
+				"get": {
+					Summary:    "List a user's favorite contacts",
+					Tags:       contacts,
+					Parameters: []Parameter{userIDParam, limitParam, cursorParam, fieldsParam},
+					Responses: map[string]Response{
+						"200": ok("A page of favorites", listPage("favorites", "Contact")),
+						"400": errorResponse("Invalid limit, cursor or fields"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/{id}/contacts/trash": {
+				"get": {
+					Summary:    "List a user's soft-deleted contacts",
+					Tags:       contacts,
+					Parameters: []Parameter{userIDParam, fieldsParam},
+					Responses: map[string]Response{
+						"200": ok("Deleted contacts (deleted_at set)", object([]string{"contacts", "count"}, map[string]*Schema{
+							"contacts": arrayOf(ref("Contact")),
+							"count":    integer(),
+						})),
+						"400": errorResponse("Invalid fields"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/{id}/contacts/changes": {
+				"get": {
+					Summary: "Contacts changed or soft-deleted since a sync point, oldest change first",
+					Tags:    contacts,
+					Parameters: []Parameter{
+						userIDParam,
+						{Name: "since", In: "query", Required: true, Description: "Sync point (RFC3339, inclusive); the previous response's next_since", Schema: strFormat("date-time")},
+					},
+					Responses: map[string]Response{
+						"200": ok("Changed contacts and the next sync point", object([]string{"contacts", "count", "next_since"}, map[string]*Schema{
+							"contacts":   arrayOf(ref("ContactChange")),
+							"count":      integer(),
+							"next_since": &Schema{Type: "string", Format: "date-time", Description: "Server time the reads started at"},
+						})),
+						"400": errorResponse("Missing or invalid since"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/{id}/contacts/by-email": {
+				"get": {
+					Summary:    "Find a contact by email (case-insensitive)",
+					Tags:       contacts,
+					Parameters: []Parameter{userIDParam, {Name: "email", In: "query", Required: true, Schema: strFormat("email")}, fieldsParam},
+					Responses: map[string]Response{
+						"200": ok("Contact", ref("Contact")),
+						"400": errorResponse("Missing email or invalid fields"),
+						"404": errorResponse("Contact not found"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/{id}/contacts/search": {
+				"get": {
+					Summary: "Search a user's contacts; set filters are ANDed. Only favorite=true is index-backed (FAV# items), other combinations filter every contact",
+					Tags:    contacts,
+					Parameters: []Parameter{
+						userIDParam,
+						queryParam("company", "Company equals (case-sensitive)", str()),
+						queryParam("tag", "Tags contains", str()),
+						queryParam("favorite", "IsFavorite equals", boolean()),
+						queryParam("q", "Name, email or company contains (case-sensitive)", str()),
+					},
+					Responses: map[string]Response{
+						"200": ok("Matching contacts, the filters applied, and how they were read", object([]string{"contacts", "count", "filters", "access", "scanned", "consumed_capacity"}, map[string]*Schema{
+							"contacts": arrayOf(ref("Contact")),
+							"count":    integer(),
+							"filters": object(nil, map[string]*Schema{
+								"company":  str(),
+								"tag":      str(),
+								"favorite": boolean(),
+								"q":        str(),
+							}),
+							"access":            &Schema{Type: "string", Enum: []string{"favorites_index", "filter"}},
+							"scanned":           &Schema{Type: "integer", Description: "Items DynamoDB read to find the matches; far above count means the filter did the selecting"},
+							"consumed_capacity": &Schema{Type: "number", Description: "Read capacity units the query consumed"},
+						})),
+						"400": errorResponse("Invalid favorite"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/{id}/contacts/{contactId}": {
+				"get": {
+					Summary:    "Get a contact",
+					Tags:       contacts,
+					Parameters: []Parameter{userIDParam, contactIDParam, fieldsParam},
+					Responses: map[string]Response{
+						"200": ok("Contact", ref("Contact")),
+						"400": errorResponse("Invalid fields"),
+						"404": errorResponse("Contact not found"),
+					},
+				},
+				"put": {
+					Summary:     "Update a contact",
+					Tags:        contacts,
+					Parameters:  []Parameter{userIDParam, contactIDParam, ifUnmodifiedSinceParam},
+					RequestBody: jsonBody(&Schema{Type: "object", Description: "Attributes to change", AdditionalProperties: &Schema{}}),
+					Responses: map[string]Response{
+						"200": ok("Updated contact", ref("Contact")),
+						"400": errorResponse("Invalid request"),
+						"412": errorResponse("Contact was modified after If-Unmodified-Since"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+				"delete": {
+					Summary:    "Delete a contact (moves it to the trash)",
+					Tags:       contacts,
+					Parameters: []Parameter{userIDParam, contactIDParam},
+					Responses: map[string]Response{
+						"200": ok("Contact deleted", ref("Message")),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/{id}/contacts/{contactId}/transfer": {
+				"post": {
+					Summary:    "Move a contact to another user",
+					Tags:       contacts,
+					Parameters: []Parameter{userIDParam, contactIDParam},
+					RequestBody: jsonBody(object([]string{"to_user_id"}, map[string]*Schema{
+						"to_user_id": strFormat("uuid"),
+					})),
+					Responses: map[string]Response{
+						"200": ok("Contact under its new owner", ref("Contact")),
+						"400": errorResponse("Invalid request"),
+						"404": errorResponse("Contact or target user not found"),
+						"409": errorResponse("Target user already owns the contact"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+
+			"/api/v1/users/{id}/contacts/{contactId}/restore": {
+				"post": {
+					Summary:     "Restore a soft-deleted contact",
+					Tags:        contacts,
+					Parameters:  []Parameter{userIDParam, contactIDParam},
+					RequestBody: jsonBody(&Schema{Type: "object", Description: "Empty object"}),
+					Responses: map[string]Response{
+						"200": ok("Restored contact", ref("Contact")),
+						"404": errorResponse("No deleted contact with this ID"),
+						"410": errorResponse("Deleted longer ago than the trash retention"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/{id}/contacts/{contactId}/clone": {
+				"post": {
+					Summary:     "Create a copy of a contact",
+					Tags:        contacts,
+					Parameters:  []Parameter{userIDParam, contactIDParam},
+					RequestBody: jsonBody(&Schema{Type: "object", Description: "Empty object"}),
+					Responses: map[string]Response{
+						"201": ok("The copy: new ID, \" (copy)\" appended to the name, not a favorite unless CLONE_KEEPS_FAVORITE is set", ref("Contact")),
+						"400": errorResponse("Email domain not allowed"),
+						"404": errorResponse("Contact not found"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/users/{id}/contacts/{contactId}/avatar": {
+				"post": {
+					Summary:    "Get a presigned URL to upload the contact's avatar to",
+					Tags:       contacts,
+					Parameters: []Parameter{userIDParam, contactIDParam},
+					RequestBody: jsonBody(object([]string{"content_type", "size"}, map[string]*Schema{
+						"content_type": {Type: "string", Enum: []string{"image/jpeg", "image/png", "image/webp", "image/gif"}},
+						"size":         {Type: "integer", Minimum: intPtr(1), Description: "Exact upload size in bytes, at most AVATAR_MAX_BYTES"},
+					})),
+					Responses: map[string]Response{
+						"200": ok("PUT the image to upload_url with headers; the contact's avatar_url is already object_url", object([]string{"upload_url", "method", "headers", "object_url", "expires_at"}, map[string]*Schema{
+							"upload_url": str(),
+							"method":     str(),
+							"headers":    {Type: "object", AdditionalProperties: str()},
+							"object_url": str(),
+							"expires_at": strFormat("date-time"),
+						})),
+						"400": errorResponse("Unsupported content type or size"),
+						"404": errorResponse("Contact not found"),
+						"500": errorResponse("Internal error"),
+						"501": errorResponse("Avatar uploads are not configured"),
+					},
+				},
+			},
+
+			// Sessions
+			"/api/v1/auth/session": {
+				"delete": {
+					Summary:  "Log out (revoke the current session)",
+					Tags:     []string{"auth"},
+					Security: sessionAuth,
+					Responses: map[string]Response{
+						"200": ok("Session revoked", ref("Message")),
+						"401": errorResponse("Not authenticated, or the session is already invalid"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/auth/sessions": {
+				"delete": {
+					Summary:  "Log out everywhere (revoke all of the user's sessions)",
+					Tags:     []string{"auth"},
+					Security: sessionAuth,
+					Responses: map[string]Response{
+						"200": ok("Sessions revoked", object([]string{"message", "sessions_revoked"}, map[string]*Schema{
+							"message":          str(),
+							"sessions_revoked": integer(),
+						})),
+						"401": errorResponse("Not authenticated, or the session is already invalid"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+
+			// Admin
+			"/api/v1/admin/users/{id}/sessions": {
+				"post": {
+					Summary:    "Issue a session token for a user",
+					Tags:       admin,
+					Security:   adminAuth,
+					Parameters: []Parameter{userIDParam},
+					RequestBody: jsonBody(object(nil, map[string]*Schema{
+						"tenant_id": {Type: "string", Description: "Tenant the session acts for; its requests use the <tenant>-<table> table"},
+					})),
+					Responses: map[string]Response{
+						"201": ok("Session token", object([]string{"token", "user_id"}, map[string]*Schema{
+							"token":     str(),
+							"user_id":   str(),
+							"tenant_id": str(),
+						})),
+						"400": errorResponse("Invalid tenant_id"),
+						"401": errorResponse("Missing or wrong admin token"),
+						"403": errorResponse("Admin API disabled"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/admin/cache/flush": {
+				"post": {
+					Summary:  "Force-evict cache entries",
+					Tags:     admin,
+					Security: adminAuth,
+					RequestBody: jsonBody(object([]string{"scope"}, map[string]*Schema{
+						"scope": {Type: "string", Enum: []string{"user", "contacts", "all"}},
+						"id":    {Type: "string", Description: "Limit the flush to one user; omit to flush the whole scope"},
+					})),
+					Responses: map[string]Response{
+						"200": ok("Keys deleted", object(nil, map[string]*Schema{"scope": str(), "id": str(), "deleted": integer()})),
+						"400": errorResponse("Invalid scope"),
+						"401": errorResponse("Missing or wrong admin token"),
+						"403": errorResponse("Admin API disabled"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/admin/reindex": {
+				"post": {
+					Summary:  "Backfill missing GSI1PK/GSI1SK/EntityType from item keys, and user data older items lack",
+					Tags:     admin,
+					Security: adminAuth,
+					Responses: map[string]Response{
+						"200": ok("Reindex counts", ref("ReindexResult")),
+						"401": errorResponse("Missing or wrong admin token"),
+						"403": errorResponse("Admin API disabled"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/admin/contacts": {
+				"get": {
+					Summary:  "Page through every user's contacts in contact ID order; filters apply after the limit, so pages can be short",
+					Tags:     admin,
+					Security: adminAuth,
+					Parameters: []Parameter{
+						limitParam,
+						cursorParam,
+						{Name: "entity", In: "query", Description: "Contacts to list (default CONTACT)", Schema: &Schema{Type: "string", Enum: []string{"CONTACT", "CONTACT_TRASH"}}},
+						queryParam("company", "Company equals (case-sensitive)", str()),
+						queryParam("tag", "Tags contains", str()),
+						queryParam("favorite", "IsFavorite equals", boolean()),
+						queryParam("q", "Name, email or company contains (case-sensitive)", str()),
+						fieldsParam,
+					},
+					Responses: map[string]Response{
+						"200": ok("A page of contacts", object([]string{"contacts", "count", "next_cursor"}, map[string]*Schema{
+							"contacts":    arrayOf(ref("Contact")),
+							"count":       integer(),
+							"next_cursor": &Schema{Type: "string", Description: "Empty on the last page"},
+						})),
+						"400": errorResponse("Invalid limit, cursor, entity, favorite or fields"),
+						"401": errorResponse("Missing or wrong admin token"),
+						"403": errorResponse("Admin API disabled"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+			"/api/v1/admin/audit": {
+				"get": {
+					Summary:    "An entity's audit history",
+					Tags:       admin,
+					Security:   adminAuth,
+					Parameters: []Parameter{{Name: "entity_id", In: "query", Required: true, Schema: str()}},
+					Responses: map[string]Response{
+						"200": ok("Audit entries, oldest first", object([]string{"entries", "count"}, map[string]*Schema{
+							"entries": arrayOf(ref("AuditEntry")),
+							"count":   integer(),
+						})),
+						"400": errorResponse("Missing entity_id"),
+						"401": errorResponse("Missing or wrong admin token"),
+						"403": errorResponse("Admin API disabled"),
+						"500": errorResponse("Internal error"),
+					},
+				},
+			},
+		},
+		Components: Components{
+			Schemas: map[string]*Schema{
+				"Error":   object([]string{"error"}, map[string]*Schema{"error": str()}),
+				"Message": object([]string{"message"}, map[string]*Schema{"message": str()}),
+				"User": object([]string{"id", "email", "first_name", "last_name"}, map[string]*Schema{
+					"id":                    str(),
+					"email":                 strFormat("email"),
+					"first_name":            str(),
+					"last_name":             str(),
+					"last_active_at":        strFormat("date-time"),
+					"contact_count":         &Schema{Type: "integer", Description: "Live (non-deleted) contacts"},
+					"allowed_email_domains": &Schema{Type: "array", Items: str(), Description: "Contact email domains this user may store; empty uses the server default"},
+					"entity_type":           str(),
+					"created_at":            strFormat("date-time"),
+					"updated_at":            strFormat("date-time"),
+				}),
+				"CreateUserRequest": object([]string{"email", "first_name", "last_name"}, map[string]*Schema{
+					"email":      strFormat("email"),
+					"first_name": str(),
+					"last_name":  str(),
+				}),
+				"Contact": object([]string{"id", "user_id", "name"}, map[string]*Schema{
+					"id":          str(),
+					"user_id":     str(),
+					"name":        str(),
+					"email":       strFormat("email"),
+					"phone":       str(),
+					"company":     str(),
+					"job_title":   str(),
+					"address":     str(),
+					"notes":       str(),
+					"avatar_url":  str(),
+					"tags":        arrayOf(str()),
+					"is_favorite": boolean(),
+					"entity_type": str(),
+					"created_at":  strFormat("date-time"),
+					"updated_at":  strFormat("date-time"),
+					"deleted_at":  strFormat("date-time"),
+				}),
+				"ContactChange": object([]string{"id", "user_id", "name", "deleted"}, map[string]*Schema{
+					"id":          str(),
+					"user_id":     str(),
+					"name":        str(),
+					"email":       strFormat("email"),
+					"phone":       str(),
+					"company":     str(),
+					"job_title":   str(),
+					"address":     str(),
+					"notes":       str(),
+					"avatar_url":  str(),
+					"tags":        arrayOf(str()),
+					"is_favorite": boolean(),
+					"entity_type": str(),
+					"created_at":  strFormat("date-time"),
+					"updated_at":  strFormat("date-time"),
+					"deleted_at":  strFormat("date-time"),
+					"deleted":     &Schema{Type: "boolean", Description: "The contact was soft-deleted; drop it"},
+				}),
+				"ContactInput": object([]string{"name"}, map[string]*Schema{
+					"name":        str(),
+					"email":       strFormat("email"),
+					"phone":       str(),
+					"company":     str(),
+					"job_title":   str(),
+					"address":     str(),
+					"notes":       str(),
+					"tags":        arrayOf(str()),
+					"is_favorite": boolean(),
+				}),
+				"CreateContactRequest": object([]string{"name"}, map[string]*Schema{
+					"id":          strFormat("uuid"),
+					"name":        str(),
+					"email":       strFormat("email"),
+					"phone":       str(),
+					"company":     str(),
+					"job_title":   str(),
+					"address":     str(),
+					"notes":       str(),
+					"tags":        arrayOf(str()),
+					"is_favorite": boolean(),
+				}),
+				"ReindexResult": object([]string{"scanned", "fixed", "skipped", "backfilled"}, map[string]*Schema{
+					"scanned":    integer(),
+					"fixed":      integer(),
+					"skipped":    integer(),
+					"backfilled": integer(),
+				}),
+				"ImportResult": object([]string{"dry_run", "imported", "rejected", "items"}, map[string]*Schema{
+					"dry_run":  boolean(),
+					"imported": arrayOf(ref("Contact")),
+					"rejected": arrayOf(object([]string{"index", "error"}, map[string]*Schema{
+						"index": integer(),
+						"email": str(),
+						"error": str(),
+					})),
+					"items": arrayOf(object([]string{"index", "action"}, map[string]*Schema{
+						"index":      integer(),
+						"email":      str(),
+						"action":     {Type: "string", Enum: []string{"created", "skipped", "overwritten", "merged", "rejected"}},
+						"contact_id": str(),
+					})),
+				}),
+				"BulkUpdateResult": object([]string{"id", "success"}, map[string]*Schema{
+					"id":      str(),
+					"success": boolean(),
+					"error":   str(),
+				}),
+				"TagChangeResult": object([]string{"modified", "failed"}, map[string]*Schema{
+					"modified": integer(),
+					"failed":   &Schema{Type: "integer", Description: "Contacts edited during the change and left as they were; repeating the request picks them up"},
+				}),
+				"AuditEntry": object([]string{"id", "target_type", "target_id", "action", "actor"}, map[string]*Schema{
+					"id":           str(),
+					"target_type":  str(),
+					"target_id":    str(),
+					"action":       str(),
+					"actor":        str(),
+					"old_owner_id": str(),
+					"new_owner_id": str(),
+					"request_id":   str(),
+					"created_at":   strFormat("date-time"),
+				}),
+			},
+			SecuritySchemes: map[string]SecurityScheme{
+				"adminToken":   {Type: "http", Scheme: "bearer"},
+				"sessionToken": {Type: "http", Scheme: "bearer"},
+			},
+		},
+	}
+}
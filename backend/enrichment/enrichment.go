@@ -0,0 +1,76 @@
+package enrichment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Result is what a provider knows about the person behind an email address.
+// Empty fields are unknown.
+type Result struct {
+	Company  string `json:"company"`
+	JobTitle string `json:"job_title"`
+}
+
+// Empty reports whether r adds nothing to a contact
+func (r *Result) Empty() bool {
+	return r == nil || (r.Company == "" && r.JobTitle == "")
+}
+
+// Enricher looks up a contact's details by email with an external provider. It returns
+// a nil Result when the provider knows nothing about the address.
+type Enricher interface {
+	Enrich(ctx context.Context, email string) (*Result, error)
+}
+
+// HTTPEnricher is an Enricher backed by a JSON lookup endpoint: it GETs
+// <endpoint>?email=<email> and decodes a Result. 404 means the address is unknown.
+type HTTPEnricher struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+// NewHTTPEnricher creates an enricher for endpoint, sending apiKey (if set) as a bearer token
+func NewHTTPEnricher(endpoint, apiKey string) *HTTPEnricher {
+	return &HTTPEnricher{
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Enrich looks email up
+func (e *HTTPEnricher) Enrich(ctx context.Context, email string) (*Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.endpoint+"?email="+url.QueryEscape(email), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var result Result
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
+}
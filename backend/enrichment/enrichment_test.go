@@ -0,0 +1,39 @@
+package enrichment
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPEnricher(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		if r.URL.Query().Get("email") != "ada@example.com" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"company":"Analytical Engines","job_title":"Programmer"}`))
+	}))
+	defer server.Close()
+
+	e := NewHTTPEnricher(server.URL, "secret")
+	result, err := e.Enrich(context.Background(), "ada@example.com")
+	if err != nil {
+		t.Fatalf("Enrich: %v", err)
+	}
+	if result.Company != "Analytical Engines" || result.JobTitle != "Programmer" {
+		t.Errorf("result = %+v", result)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("Authorization = %q, want the API key as a bearer token", gotAuth)
+	}
+
+	result, err = e.Enrich(context.Background(), "unknown@example.com")
+	if err != nil || !result.Empty() {
+		t.Errorf("unknown address = %+v, %v; want no result", result, err)
+	}
+}
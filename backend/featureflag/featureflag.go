@@ -0,0 +1,121 @@
+// Package featureflag decides whether optional behaviours are switched on.
+//
+// Each flag has a default from the environment (FEATURE_FLAGS, e.g.
+// "write_through=off,favorites_index=on"). Operators can override any flag at runtime by
+// setting a field of the feature_flags Redis hash to on or off; every instance re-reads
+// the hash periodically, so a risky behaviour can be shipped dark and turned on (or
+// killed) per environment without a redeploy. Deleting the field restores the default.
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"hub-control-plane/backend/requestid"
+)
+
+// Flags answers IsEnabled from runtime overrides, then defaults. A flag in neither is off.
+type Flags struct {
+	defaults map[string]bool
+
+	mu        sync.RWMutex
+	overrides map[string]bool
+}
+
+// New returns Flags with the given defaults and no overrides
+func New(defaults map[string]bool) *Flags {
+	return &Flags{defaults: defaults, overrides: map[string]bool{}}
+}
+
+// IsEnabled reports whether flag is on
+func (f *Flags) IsEnabled(flag string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if enabled, ok := f.overrides[flag]; ok {
+		return enabled
+	}
+	return f.defaults[flag]
+}
+
+// Overrides loads the current runtime overrides as flag -> raw value
+type Overrides func(ctx context.Context) (map[string]string, error)
+
+// Refresh replaces the overrides with what load returns. Values that aren't a boolean
+// (on/off, true/false, 1/0) are logged and ignored. On error the previous overrides stay.
+func (f *Flags) Refresh(ctx context.Context, load Overrides) error {
+	raw, err := load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load feature flag overrides: %w", err)
+	}
+
+	overrides := make(map[string]bool, len(raw))
+	for flag, value := range raw {
+		enabled, err := parseBool(value)
+		if err != nil {
+			requestid.Logf(ctx, "Warning: ignoring feature flag override %s=%q: %v", flag, value, err)
+			continue
+		}
+		overrides[flag] = enabled
+	}
+
+	f.mu.Lock()
+	f.overrides = overrides
+	f.mu.Unlock()
+	return nil
+}
+
+// Watch calls Refresh every interval until ctx is done. Failures keep the last overrides
+// and are only logged, so a Redis outage freezes flags rather than resetting them.
+func (f *Flags) Watch(ctx context.Context, load Overrides, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := f.Refresh(ctx, load); err != nil {
+				requestid.Logf(ctx, "Warning: %v", err)
+			}
+		}
+	}
+}
+
+// ParseDefaults parses "flag=on,other=off" (a bare flag means on) on top of base, which
+// is not modified
+func ParseDefaults(base map[string]bool, specs []string) (map[string]bool, error) {
+	defaults := make(map[string]bool, len(base)+len(specs))
+	for flag, enabled := range base {
+		defaults[flag] = enabled
+	}
+	for _, spec := range specs {
+		flag, value, hasValue := strings.Cut(spec, "=")
+		flag = strings.TrimSpace(flag)
+		enabled := true
+		if hasValue {
+			var err error
+			if enabled, err = parseBool(value); err != nil {
+				return nil, fmt.Errorf("invalid feature flag %q: %w", spec, err)
+			}
+		}
+		defaults[flag] = enabled
+	}
+	return defaults, nil
+}
+
+// parseBool accepts on/off besides strconv.ParseBool's forms
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	}
+	return strconv.ParseBool(strings.TrimSpace(value))
+}
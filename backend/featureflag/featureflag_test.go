@@ -0,0 +1,66 @@
+package featureflag_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"hub-control-plane/backend/featureflag"
+)
+
+func TestParseDefaults(t *testing.T) {
+	base := map[string]bool{"write_through": true, "favorites_index": true}
+	defaults, err := featureflag.ParseDefaults(base, []string{"write_through=off", "beta=on", "bare"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"write_through": false, "favorites_index": true, "beta": true, "bare": true}
+	for flag, enabled := range want {
+		if defaults[flag] != enabled {
+			t.Errorf("%s = %v, want %v", flag, defaults[flag], enabled)
+		}
+	}
+	if !base["write_through"] {
+		t.Error("ParseDefaults modified its base")
+	}
+
+	if _, err := featureflag.ParseDefaults(nil, []string{"write_through=maybe"}); err == nil {
+		t.Error("expected an error for a non-boolean value")
+	}
+}
+
+func TestFlagsOverrides(t *testing.T) {
+	ctx := context.Background()
+	flags := featureflag.New(map[string]bool{"write_through": true, "favorites_index": false})
+
+	if !flags.IsEnabled("write_through") || flags.IsEnabled("favorites_index") || flags.IsEnabled("unknown") {
+		t.Fatal("defaults not applied before the first refresh")
+	}
+
+	overrides := map[string]string{"write_through": "off", "favorites_index": "on", "broken": "sometimes"}
+	load := func(ctx context.Context) (map[string]string, error) { return overrides, nil }
+	if err := flags.Refresh(ctx, load); err != nil {
+		t.Fatal(err)
+	}
+	if flags.IsEnabled("write_through") || !flags.IsEnabled("favorites_index") || flags.IsEnabled("broken") {
+		t.Error("overrides don't take precedence over defaults")
+	}
+
+	// A failed load keeps the last overrides
+	failing := func(ctx context.Context) (map[string]string, error) { return nil, errors.New("redis down") }
+	if err := flags.Refresh(ctx, failing); err == nil {
+		t.Error("expected the load error")
+	}
+	if flags.IsEnabled("write_through") {
+		t.Error("a failed refresh dropped the overrides")
+	}
+
+	// Removing an override restores the default
+	delete(overrides, "write_through")
+	if err := flags.Refresh(ctx, load); err != nil {
+		t.Fatal(err)
+	}
+	if !flags.IsEnabled("write_through") {
+		t.Error("default not restored once its override was removed")
+	}
+}
@@ -6,12 +6,24 @@ import (
 	"hub-control-plane/backend/models"
 )
 
+type SearchResult interface {
+	IsSearchResult()
+}
+
+type ContactConnection struct {
+	Nodes    []*models.ContactEntity `json:"nodes"`
+	PageInfo *PageInfo               `json:"pageInfo"`
+}
+
 type CreateContactInput struct {
 	UserID     string   `json:"userId"`
 	Name       string   `json:"name"`
 	Email      *string  `json:"email,omitempty"`
 	Phone      *string  `json:"phone,omitempty"`
 	Company    *string  `json:"company,omitempty"`
+	JobTitle   *string  `json:"jobTitle,omitempty"`
+	Address    *string  `json:"address,omitempty"`
+	Notes      *string  `json:"notes,omitempty"`
 	IsFavorite *bool    `json:"isFavorite,omitempty"`
 	Tags       []string `json:"tags,omitempty"`
 }
@@ -25,9 +37,19 @@ type CreateUserInput struct {
 type Mutation struct {
 }
 
+type PageInfo struct {
+	EndCursor   *string `json:"endCursor,omitempty"`
+	HasNextPage bool    `json:"hasNextPage"`
+}
+
 type Query struct {
 }
 
+type SearchConnection struct {
+	Nodes    []SearchResult `json:"nodes"`
+	PageInfo *PageInfo      `json:"pageInfo"`
+}
+
 type SystemStats struct {
 	TotalUsers    int `json:"totalUsers"`
 	TotalContacts int `json:"totalContacts"`
@@ -38,6 +60,9 @@ type UpdateContactInput struct {
 	Email      *string  `json:"email,omitempty"`
 	Phone      *string  `json:"phone,omitempty"`
 	Company    *string  `json:"company,omitempty"`
+	JobTitle   *string  `json:"jobTitle,omitempty"`
+	Address    *string  `json:"address,omitempty"`
+	Notes      *string  `json:"notes,omitempty"`
 	IsFavorite *bool    `json:"isFavorite,omitempty"`
 	Tags       []string `json:"tags,omitempty"`
 }
@@ -0,0 +1,59 @@
+package resolvers
+
+import (
+	"context"
+
+	gqlgen "github.com/99designs/gqlgen/graphql"
+)
+
+// contactAttributes maps Contact fields that are stored attributes to their DynamoDB
+// names. Fields missing here (tags, user) are resolved rather than read, so selecting
+// any of them turns projection off.
+var contactAttributes = map[string]string{
+	"id":         "ID",
+	"userId":     "UserID",
+	"name":       "Name",
+	"email":      "Email",
+	"phone":      "Phone",
+	"company":    "Company",
+	"isFavorite": "IsFavorite",
+	"createdAt":  "CreatedAt",
+	"updatedAt":  "UpdatedAt",
+}
+
+// contactNodesProjection returns the attributes to read for the nodes of the
+// ContactConnection field being resolved, or nil to fetch whole items
+func contactNodesProjection(ctx context.Context) []string {
+	fc := gqlgen.GetFieldContext(ctx)
+	if fc == nil || !gqlgen.HasOperationContext(ctx) {
+		return nil
+	}
+	opCtx := gqlgen.GetOperationContext(ctx)
+
+	for _, field := range gqlgen.CollectFields(opCtx, fc.Field.Selections, []string{"ContactConnection"}) {
+		if field.Name == "nodes" {
+			return contactProjection(gqlgen.CollectFields(opCtx, field.Selections, []string{"Contact"}))
+		}
+	}
+	// Only pageInfo was asked for: read keys alone
+	return []string{"ID"}
+}
+
+// contactProjection maps selected Contact fields to attributes, nil if any is computed
+func contactProjection(fields []gqlgen.CollectedField) []string {
+	projection := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field.Name == "__typename" {
+			continue
+		}
+		attribute, ok := contactAttributes[field.Name]
+		if !ok {
+			return nil
+		}
+		projection = append(projection, attribute)
+	}
+	if len(projection) == 0 {
+		return []string{"ID"}
+	}
+	return projection
+}
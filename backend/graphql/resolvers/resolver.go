@@ -2,11 +2,19 @@ package resolvers
 
 import (
 	"context"
+	"errors"
+	"time"
+
+	gqlgen "github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 
 	// Local packages
 	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/pagination"
+	"hub-control-plane/backend/requestid"
 	"hub-control-plane/backend/service"
 	"hub-control-plane/backend/graphql"
+	"hub-control-plane/backend/validation"
 )
 
 // Resolver is the root resolver
@@ -27,13 +35,92 @@ func NewResolver(appService *service.AppServiceWithCache) *Resolver {
 
 // Users resolves the users list query
 func (r *Resolver) Users(ctx context.Context, limit *int, offset *int) ([]*models.UserEntity, error) {
-	// For now, return all users (you can add pagination later)
-	return r.appService.ListAllUsers(ctx)
+	users, err := r.appService.ListAllUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pageOf(users, limit, offset), nil
 }
 
-// Contacts resolves the contacts list query
+// Contacts resolves the contacts list query. ListAllContacts pages by cursor, so pages
+// are read only until the offset/limit window is covered.
 func (r *Resolver) Contacts(ctx context.Context, limit *int, offset *int) ([]*models.ContactEntity, error) {
-	return r.appService.ListAllContacts(ctx)
+	requested := 0
+	if limit != nil {
+		requested = *limit
+	}
+	want := pagination.ClampLimit(requested)
+	if offset != nil && *offset > 0 {
+		want += *offset
+	}
+
+	var contacts []*models.ContactEntity
+	opts := service.ContactListOptions{}
+	for {
+		page, err := r.appService.ListAllContacts(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, page.Contacts...)
+		if page.NextCursor == "" || len(contacts) >= want {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+	return pageOf(contacts, limit, offset), nil
+}
+
+// Search resolves the search query: one page of matching users, then matching contacts,
+// as SearchResult nodes. after is the endCursor of the previous page.
+func (r *Resolver) Search(ctx context.Context, q string, limit *int, after *string) (*graphql.SearchConnection, error) {
+	requested := 0
+	if limit != nil {
+		requested = *limit
+	}
+
+	page, err := r.appService.Search(ctx, q, requested, derefString(after))
+	if errors.Is(err, validation.ErrValidation) || errors.Is(err, pagination.ErrInvalidCursor) {
+		return nil, validationError(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]graphql.SearchResult, 0, len(page.Users)+len(page.Contacts))
+	for _, user := range page.Users {
+		nodes = append(nodes, user)
+	}
+	for _, contact := range page.Contacts {
+		nodes = append(nodes, contact)
+	}
+
+	pageInfo := &graphql.PageInfo{HasNextPage: page.NextCursor != ""}
+	if page.NextCursor != "" {
+		pageInfo.EndCursor = &page.NextCursor
+	}
+	return &graphql.SearchConnection{Nodes: nodes, PageInfo: pageInfo}, nil
+}
+
+// pageOf returns the limit/offset window of items. The limit is clamped to
+// pagination.MaxPageSize (a missing limit gets the cap), same as the REST lists.
+func pageOf[T any](items []T, limit, offset *int) []T {
+	start := 0
+	if offset != nil && *offset > 0 {
+		start = *offset
+	}
+	if start > len(items) {
+		start = len(items)
+	}
+
+	requested := 0
+	if limit != nil {
+		requested = *limit
+	}
+	end := start + pagination.ClampLimit(requested)
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end]
 }
 
 // UserContacts resolves contacts for a specific user
@@ -50,6 +137,10 @@ func (r *Resolver) UserContacts(ctx context.Context, userID string, favorites *b
 
 // CreateUser resolves the createUser mutation
 func (r *Resolver) CreateUser(ctx context.Context, input graphql.CreateUserInput) (*models.UserEntity, error) {
+	if err := validation.CreateUser(input.Email, input.FirstName, input.LastName); err != nil {
+		return nil, validationError(err)
+	}
+
 	return r.appService.CreateUser(ctx, input.Email, input.FirstName, input.LastName)
 }
 
@@ -81,27 +172,58 @@ func (r *Resolver) DeleteUser(ctx context.Context, id string) (bool, error) {
 
 // CreateContact resolves the createContact mutation
 func (r *Resolver) CreateContact(ctx context.Context, input graphql.CreateContactInput) (*models.ContactEntity, error) {
-	email := ""
-	if input.Email != nil {
-		email = *input.Email
+	in := service.CreateContactInput{
+		UserID:       input.UserID,
+		ContactInput: contactInput(&input),
 	}
-	
-	phone := ""
-	if input.Phone != nil {
-		phone = *input.Phone
+
+	if err := validation.CreateContact(in.Name, in.Email); err != nil {
+		return nil, validationError(err)
 	}
-	
-	company := ""
-	if input.Company != nil {
-		company = *input.Company
+
+	contact, err := r.appService.CreateContact(ctx, in)
+	if errors.Is(err, validation.ErrValidation) {
+		return nil, validationError(err)
 	}
-	
-	isFavorite := false
-	if input.IsFavorite != nil {
-		isFavorite = *input.IsFavorite
+	return contact, err
+}
+
+// CreateContacts resolves the createContacts mutation. Invalid or unwritten inputs
+// come back in the payload's errors (by index) rather than failing the mutation.
+func (r *Resolver) CreateContacts(ctx context.Context, inputs []*graphql.CreateContactInput) (*service.BatchCreateResult, error) {
+	batch := make([]service.BatchContactInput, len(inputs))
+	for i, input := range inputs {
+		batch[i] = service.BatchContactInput{
+			UserID:       input.UserID,
+			ContactInput: contactInput(input),
+		}
+	}
+
+	return r.appService.CreateContacts(ctx, batch)
+}
+
+// contactInput maps a GraphQL contact input onto the service's, with missing
+// optional fields left empty
+func contactInput(input *graphql.CreateContactInput) service.ContactInput {
+	return service.ContactInput{
+		Name:       input.Name,
+		Email:      derefString(input.Email),
+		Phone:      derefString(input.Phone),
+		Company:    derefString(input.Company),
+		JobTitle:   derefString(input.JobTitle),
+		Address:    derefString(input.Address),
+		Notes:      derefString(input.Notes),
+		Tags:       input.Tags,
+		IsFavorite: input.IsFavorite != nil && *input.IsFavorite,
 	}
-	
-	return r.appService.CreateContact(ctx, input.UserID, input.Name, email, phone, company, isFavorite)
+}
+
+// derefString returns the value of an optional string input, or ""
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
 }
 
 // UpdateContact resolves the updateContact mutation
@@ -120,14 +242,33 @@ func (r *Resolver) UpdateContact(ctx context.Context, id string, userID string,
 	if input.Company != nil {
 		updates["Company"] = *input.Company
 	}
+	if input.JobTitle != nil {
+		updates["JobTitle"] = *input.JobTitle
+	}
+	if input.Address != nil {
+		updates["Address"] = *input.Address
+	}
+	if input.Notes != nil {
+		updates["Notes"] = *input.Notes
+	}
 	if input.IsFavorite != nil {
 		updates["IsFavorite"] = *input.IsFavorite
 	}
+	// tags: [] arrives as an empty, non-nil slice and clears the tags; an omitted or
+	// null tags leaves them unchanged
 	if input.Tags != nil {
 		updates["Tags"] = input.Tags
 	}
-	
-	return r.appService.UpdateContact(ctx, userID, id, updates)
+
+	if err := validation.UpdateContact(updates); err != nil {
+		return nil, validationError(err)
+	}
+
+	contact, err := r.appService.UpdateContact(ctx, userID, id, updates)
+	if errors.Is(err, validation.ErrValidation) {
+		return nil, validationError(err)
+	}
+	return contact, err
 }
 
 // DeleteContact resolves the deleteContact mutation
@@ -139,6 +280,82 @@ func (r *Resolver) DeleteContact(ctx context.Context, id string, userID string)
 	return true, nil
 }
 
+// ============================================================================
+// ADMIN MUTATIONS - only for requests carrying the admin token (handlers.MarkAdmin)
+// ============================================================================
+
+// InvalidateUserCache resolves the invalidateUserCache mutation
+func (r *Resolver) InvalidateUserCache(ctx context.Context, userID string) (int, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return 0, err
+	}
+	requestid.Logf(ctx, "Admin cache invalidation requested via GraphQL: user=%q", userID)
+
+	deleted, err := r.appService.InvalidateUserCache(ctx, userID)
+	if errors.Is(err, service.ErrInvalidFlushScope) {
+		return deleted, validationError(err)
+	}
+	return deleted, err
+}
+
+// SetCacheTTL resolves the setCacheTTL mutation. The TTL applies to entries cached from
+// now on; existing entries expire on their old schedule (use invalidateUserCache to
+// force them out).
+func (r *Resolver) SetCacheTTL(ctx context.Context, seconds int) (int, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return 0, err
+	}
+	if seconds <= 0 {
+		return 0, validationError(&validation.FieldError{Field: "seconds", Message: "must be positive"})
+	}
+	requestid.Logf(ctx, "Admin cache TTL change requested via GraphQL: %ds", seconds)
+
+	ttl := time.Duration(seconds) * time.Second
+	r.appService.SetCacheTTLs(service.CacheTTLs{User: ttl, List: ttl, Dashboard: ttl})
+	return seconds, nil
+}
+
+// requireAdmin rejects callers without the admin token with extensions.code = "FORBIDDEN"
+func requireAdmin(ctx context.Context) error {
+	if service.IsAdmin(ctx) {
+		return nil
+	}
+	return &gqlerror.Error{
+		Message:    "admin token required",
+		Extensions: map[string]interface{}{"code": "FORBIDDEN"},
+	}
+}
+
+// validationError converts a validation failure into a GraphQL error carrying
+// extensions.code = "VALIDATION" so clients can tell it apart from server errors
+func validationError(err error) error {
+	gqlErr := &gqlerror.Error{
+		Message:    err.Error(),
+		Extensions: map[string]interface{}{"code": "VALIDATION"},
+	}
+
+	var fieldErr *validation.FieldError
+	if errors.As(err, &fieldErr) {
+		gqlErr.Message = fieldErr.Message
+		gqlErr.Extensions["field"] = fieldErr.Field
+	}
+
+	return gqlErr
+}
+
+// ErrorPresenter is gqlgen's default presenter plus extensions.code = "NOT_CACHED" for
+// cache-only reads that missed, so reporting clients can back off instead of failing
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := gqlgen.DefaultErrorPresenter(ctx, err)
+	if errors.Is(err, service.ErrNotCached) {
+		if gqlErr.Extensions == nil {
+			gqlErr.Extensions = map[string]interface{}{}
+		}
+		gqlErr.Extensions["code"] = "NOT_CACHED"
+	}
+	return gqlErr
+}
+
 // ============================================================================
 // FIELD RESOLVERS (for nested queries)
 // ============================================================================
@@ -148,10 +365,26 @@ type UserResolver struct {
 	*Resolver
 }
 
-// Contacts resolves the contacts field on User
-func (r *UserResolver) Contacts(ctx context.Context, obj *models.UserEntity, limit *int, favorites *bool) ([]*models.ContactEntity, error) {
-	if favorites != nil && *favorites {
-		return r.appService.ListFavoriteContacts(ctx, obj.ID)
+// Contacts resolves the contacts field on User: one page read straight from the
+// repository, limit clamped to pagination.MaxPageSize, resumed from the after cursor
+func (r *UserResolver) Contacts(ctx context.Context, obj *models.UserEntity, limit *int, after *string, favorites *bool) (*graphql.ContactConnection, error) {
+	requested := 0
+	if limit != nil {
+		requested = *limit
+	}
+
+	contacts, next, err := r.appService.ListUserContactsPage(ctx, obj.ID,
+		favorites != nil && *favorites, requested, derefString(after), contactNodesProjection(ctx)...)
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		return nil, validationError(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pageInfo := &graphql.PageInfo{HasNextPage: next != ""}
+	if next != "" {
+		pageInfo.EndCursor = &next
 	}
-	return r.appService.ListUserContacts(ctx, obj.ID)
+	return &graphql.ContactConnection{Nodes: contacts, PageInfo: pageInfo}, nil
 }
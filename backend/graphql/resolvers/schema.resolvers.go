@@ -9,13 +9,9 @@ import (
 	"fmt"
 	graphql1 "hub-control-plane/backend/graphql"
 	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/service"
 )
 
-// Tags is the resolver for the tags field.
-func (r *contactResolver) Tags(ctx context.Context, obj *models.ContactEntity) ([]string, error) {
-	panic(fmt.Errorf("not implemented: Tags - tags"))
-}
-
 // User is the resolver for the user field.
 func (r *contactResolver) User(ctx context.Context, obj *models.ContactEntity) (*models.UserEntity, error) {
 	panic(fmt.Errorf("not implemented: User - user"))
@@ -23,7 +19,7 @@ func (r *contactResolver) User(ctx context.Context, obj *models.ContactEntity) (
 
 // CreateUser is the resolver for the createUser field.
 func (r *mutationResolver) CreateUser(ctx context.Context, input graphql1.CreateUserInput) (*models.UserEntity, error) {
-	panic(fmt.Errorf("not implemented: CreateUser - createUser"))
+	return r.Resolver.CreateUser(ctx, input)
 }
 
 // UpdateUser is the resolver for the updateUser field.
@@ -38,12 +34,17 @@ func (r *mutationResolver) DeleteUser(ctx context.Context, id string) (bool, err
 
 // CreateContact is the resolver for the createContact field.
 func (r *mutationResolver) CreateContact(ctx context.Context, input graphql1.CreateContactInput) (*models.ContactEntity, error) {
-	panic(fmt.Errorf("not implemented: CreateContact - createContact"))
+	return r.Resolver.CreateContact(ctx, input)
+}
+
+// CreateContacts is the resolver for the createContacts field.
+func (r *mutationResolver) CreateContacts(ctx context.Context, inputs []*graphql1.CreateContactInput) (*service.BatchCreateResult, error) {
+	return r.Resolver.CreateContacts(ctx, inputs)
 }
 
 // UpdateContact is the resolver for the updateContact field.
 func (r *mutationResolver) UpdateContact(ctx context.Context, id string, userID string, input graphql1.UpdateContactInput) (*models.ContactEntity, error) {
-	panic(fmt.Errorf("not implemented: UpdateContact - updateContact"))
+	return r.Resolver.UpdateContact(ctx, id, userID, input)
 }
 
 // DeleteContact is the resolver for the deleteContact field.
@@ -51,6 +52,16 @@ func (r *mutationResolver) DeleteContact(ctx context.Context, id string, userID
 	panic(fmt.Errorf("not implemented: DeleteContact - deleteContact"))
 }
 
+// InvalidateUserCache is the resolver for the invalidateUserCache field.
+func (r *mutationResolver) InvalidateUserCache(ctx context.Context, userID string) (int, error) {
+	return r.Resolver.InvalidateUserCache(ctx, userID)
+}
+
+// SetCacheTTL is the resolver for the setCacheTTL field.
+func (r *mutationResolver) SetCacheTTL(ctx context.Context, seconds int) (int, error) {
+	return r.Resolver.SetCacheTTL(ctx, seconds)
+}
+
 // User is the resolver for the user field.
 func (r *queryResolver) User(ctx context.Context, id string) (*models.UserEntity, error) {
 	panic(fmt.Errorf("not implemented: User - user"))
@@ -58,7 +69,7 @@ func (r *queryResolver) User(ctx context.Context, id string) (*models.UserEntity
 
 // Users is the resolver for the users field.
 func (r *queryResolver) Users(ctx context.Context, limit *int, offset *int) ([]*models.UserEntity, error) {
-	panic(fmt.Errorf("not implemented: Users - users"))
+	return r.Resolver.Users(ctx, limit, offset)
 }
 
 // Contact is the resolver for the contact field.
@@ -68,7 +79,7 @@ func (r *queryResolver) Contact(ctx context.Context, id string, userID string) (
 
 // Contacts is the resolver for the contacts field.
 func (r *queryResolver) Contacts(ctx context.Context, limit *int, offset *int) ([]*models.ContactEntity, error) {
-	panic(fmt.Errorf("not implemented: Contacts - contacts"))
+	return r.Resolver.Contacts(ctx, limit, offset)
 }
 
 // UserContacts is the resolver for the userContacts field.
@@ -76,6 +87,11 @@ func (r *queryResolver) UserContacts(ctx context.Context, userID string, favorit
 	panic(fmt.Errorf("not implemented: UserContacts - userContacts"))
 }
 
+// Search is the resolver for the search field.
+func (r *queryResolver) Search(ctx context.Context, q string, limit *int, after *string) (*graphql1.SearchConnection, error) {
+	return r.Resolver.Search(ctx, q, limit, after)
+}
+
 // UserDashboard is the resolver for the userDashboard field.
 func (r *queryResolver) UserDashboard(ctx context.Context, userID string) (*graphql1.UserDashboard, error) {
 	panic(fmt.Errorf("not implemented: UserDashboard - userDashboard"))
@@ -87,8 +103,13 @@ func (r *queryResolver) SystemStats(ctx context.Context) (*graphql1.SystemStats,
 }
 
 // Contacts is the resolver for the contacts field.
-func (r *userResolver) Contacts(ctx context.Context, obj *models.UserEntity, limit *int, favorites *bool) ([]*models.ContactEntity, error) {
-	panic(fmt.Errorf("not implemented: Contacts - contacts"))
+func (r *userResolver) Contacts(ctx context.Context, obj *models.UserEntity, limit *int, after *string, favorites *bool) (*graphql1.ContactConnection, error) {
+	return (&UserResolver{r.Resolver}).Contacts(ctx, obj, limit, after, favorites)
+}
+
+// FavoriteCount is the resolver for the favoriteCount field.
+func (r *userResolver) FavoriteCount(ctx context.Context, obj *models.UserEntity) (int, error) {
+	return r.appService.CountFavoriteContacts(ctx, obj.ID)
 }
 
 // Contact returns graphql1.ContactResolver implementation.
@@ -1,10 +1,18 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/pagination"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/requestid"
 	"hub-control-plane/backend/service"
+	"hub-control-plane/backend/validation"
 )
 
 type AppHandler struct {
@@ -34,7 +42,16 @@ func (h *AppHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
+	if err := validation.CreateUser(req.Email, req.FirstName, req.LastName); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	user, err := h.appService.CreateUser(c.Request.Context(), req.Email, req.FirstName, req.LastName)
+	if errors.Is(err, service.ErrEmailTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -43,20 +60,58 @@ func (h *AppHandler) CreateUser(c *gin.Context) {
 	c.JSON(http.StatusCreated, user)
 }
 
+// CreateUsers handles POST /api/v1/users/bulk
+// The body is a JSON array of users. Responds 201 with one result per input, in order;
+// check each one's status (created, conflict or error).
+func (h *AppHandler) CreateUsers(c *gin.Context) {
+	var inputs []service.UserInput
+	if err := c.ShouldBindJSON(&inputs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(inputs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "at least one user is required"})
+		return
+	}
+
+	results, err := h.appService.CreateUsers(c.Request.Context(), inputs)
+	if err != nil {
+		if errors.Is(err, service.ErrTooManyUsers) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"results": results})
+}
+
 // GetUser handles GET /api/v1/users/:id
+// Pass ?fields=id,email to receive only those fields (any GET returning users or contacts).
 func (h *AppHandler) GetUser(c *gin.Context) {
 	userID := c.Param("id")
+	fields, ok := requestedFields[models.UserEntity](c)
+	if !ok {
+		return
+	}
 
 	user, err := h.appService.GetUser(c.Request.Context(), userID)
 	if err != nil {
+		if errors.Is(err, service.ErrNotCached) {
+			respondNotCached(c)
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, selectFields(user, fields))
 }
 
 // UpdateUser handles PUT /api/v1/users/:id
+// Updates naming a reserved attribute (keys, index keys, EntityType, CreatedAt) get 400.
+// With If-Unmodified-Since the update only applies if the user hasn't changed since; else 412.
 func (h *AppHandler) UpdateUser(c *gin.Context) {
 	userID := c.Param("id")
 	
@@ -66,8 +121,20 @@ func (h *AppHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	user, err := h.appService.UpdateUser(c.Request.Context(), userID, updates)
+	user, err := h.appService.UpdateUserIfUnmodifiedSince(c.Request.Context(), userID, updates, ifUnmodifiedSince(c))
 	if err != nil {
+		if errors.Is(err, validation.ErrValidation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrEmailTaken) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -75,11 +142,47 @@ func (h *AppHandler) UpdateUser(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
+// ifUnmodifiedSince returns the request's If-Unmodified-Since time, or zero when the
+// header is absent. An unparseable date is ignored, as RFC 9110 requires.
+func ifUnmodifiedSince(c *gin.Context) time.Time {
+	since, err := http.ParseTime(c.GetHeader("If-Unmodified-Since"))
+	if err != nil {
+		return time.Time{}
+	}
+	return since
+}
+
 // DeleteUser handles DELETE /api/v1/users/:id
+// Pass ?cascade=true to purge the user together with all of their contacts;
+// without it the delete is refused with 409 while any contacts remain.
 func (h *AppHandler) DeleteUser(c *gin.Context) {
 	userID := c.Param("id")
 
+	if c.Query("cascade") == "true" {
+		deleted, err := h.appService.DeleteUserCascade(c.Request.Context(), userID)
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "User and all data deleted successfully", "items_deleted": deleted})
+		return
+	}
+
 	if err := h.appService.DeleteUser(c.Request.Context(), userID); err != nil {
+		var hasContacts *service.UserHasContactsError
+		if errors.As(err, &hasContacts) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "contact_count": hasContacts.ContactCount})
+			return
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -88,30 +191,91 @@ func (h *AppHandler) DeleteUser(c *gin.Context) {
 }
 
 // ListUsers handles GET /api/v1/users
+// Supports ?limit=&cursor= paging with X-Total-Count, X-Next-Cursor and Link headers.
+// Pass ?format=ndjson to stream one user per line instead of a buffered array.
+// ?sort=email|created|name&order=asc|desc sorts the whole list before paging; by
+// default users come in GSI1 order.
 func (h *AppHandler) ListUsers(c *gin.Context) {
+	fields, ok := requestedFields[models.UserEntity](c)
+	if !ok {
+		return
+	}
+	compare, err := userSort(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if wantsNDJSON(c) {
+		if compare != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "sort is not supported with format=ndjson"})
+			return
+		}
+		if service.IsCacheOnly(c.Request.Context()) {
+			// Streams always read DynamoDB directly
+			respondNotCached(c)
+			return
+		}
+		pager, err := h.appService.UserPages(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		streamNDJSON[*models.UserEntity](c, pager, fields)
+		return
+	}
+
 	users, err := h.appService.ListAllUsers(c.Request.Context())
 	if err != nil {
+		if errors.Is(err, service.ErrNotCached) {
+			respondNotCached(c)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	sortUsers(users, compare)
+
+	p, err := parsePage(c, len(users))
+	if err != nil {
+		respondPaginationError(c, err)
+		return
+	}
+	setPaginationHeaders(c, len(users), p)
 
-	c.JSON(http.StatusOK, gin.H{"users": users, "count": len(users)})
+	pageItems := users[p.start:p.end]
+	c.JSON(http.StatusOK, gin.H{
+		"users":       selectFields(pageItems, fields),
+		"count":       len(pageItems),
+		"total":       len(users),
+		"limit":       p.limit,
+		"next_cursor": p.nextCursor,
+	})
 }
 
 // ============================================================================
 // CONTACT HANDLERS
 // ============================================================================
 
-// CreateContact handles POST /api/v1/users/:userId/contacts
+// CreateContact handles POST /api/v1/users/:id/contacts
+// Pass ?dedupe=true to reject a contact whose email the user already has (409).
+// An optional client-supplied "id" (UUID) makes the create idempotent: repeating it
+// returns the stored contact with 200 instead of creating a duplicate.
 func (h *AppHandler) CreateContact(c *gin.Context) {
-	userID := c.Param("userId")
+	userID := c.Param("id")
+	dedupe := c.Query("dedupe") == "true"
 	
 	var req struct {
-		Name       string `json:"name" binding:"required"`
-		Email      string `json:"email"`
-		Phone      string `json:"phone"`
-		Company    string `json:"company"`
-		IsFavorite bool   `json:"is_favorite"`
+		ID         string   `json:"id" binding:"omitempty,uuid"`
+		Name       string   `json:"name" binding:"required"`
+		Email      string   `json:"email"`
+		Phone      string   `json:"phone"`
+		Company    string   `json:"company"`
+		JobTitle   string   `json:"job_title"`
+		Address    string   `json:"address"`
+		Notes      string   `json:"notes"`
+		Tags       []string `json:"tags"`
+		IsFavorite bool     `json:"is_favorite"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -119,66 +283,368 @@ func (h *AppHandler) CreateContact(c *gin.Context) {
 		return
 	}
 
-	contact, err := h.appService.CreateContact(
-		c.Request.Context(),
-		userID,
-		req.Name,
-		req.Email,
-		req.Phone,
-		req.Company,
-		req.IsFavorite,
-	)
+	if err := validation.CreateContact(req.Name, req.Email); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	input := service.CreateContactInput{
+		UserID: userID,
+		ContactInput: service.ContactInput{
+			Name:       req.Name,
+			Email:      req.Email,
+			Phone:      req.Phone,
+			Company:    req.Company,
+			JobTitle:   req.JobTitle,
+			Address:    req.Address,
+			Notes:      req.Notes,
+			Tags:       req.Tags,
+			IsFavorite: req.IsFavorite,
+		},
+		RejectDuplicates: dedupe,
+	}
+
+	var contact *models.ContactEntity
+	var err error
+	created := true
+	if req.ID != "" {
+		contact, created, err = h.appService.CreateContactWithID(c.Request.Context(), req.ID, input)
+	} else {
+		contact, err = h.appService.CreateContact(c.Request.Context(), input)
+	}
+	if errors.Is(err, service.ErrContactExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, validation.ErrValidation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	if !created {
+		c.JSON(http.StatusOK, contact)
+		return
+	}
 	c.JSON(http.StatusCreated, contact)
 }
 
-// GetContact handles GET /api/v1/users/:userId/contacts/:contactId
+// ImportContacts handles POST /api/v1/users/:id/contacts/import
+// Pass ?dry_run=true to validate and dedupe the batch without writing anything, and
+// ?on_conflict=skip|overwrite|merge to choose what happens to rows matching an existing
+// contact's email (default skip).
+func (h *AppHandler) ImportContacts(c *gin.Context) {
+	userID := c.Param("id")
+	dryRun := c.Query("dry_run") == "true"
+	onConflict := c.DefaultQuery("on_conflict", service.ImportConflictSkip)
+
+	var req struct {
+		Contacts []service.ContactInput `json:"contacts" binding:"required,min=1"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.appService.ImportContacts(c.Request.Context(), userID, req.Contacts, dryRun, onConflict)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidConflictStrategy) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusCreated
+	if dryRun {
+		status = http.StatusOK
+	}
+	c.JSON(status, result)
+}
+
+// GetContact handles GET /api/v1/users/:id/contacts/:contactId
 func (h *AppHandler) GetContact(c *gin.Context) {
-	userID := c.Param("userId")
+	userID := c.Param("id")
 	contactID := c.Param("contactId")
+	fields, ok := requestedFields[models.ContactEntity](c)
+	if !ok {
+		return
+	}
 
 	contact, err := h.appService.GetContact(c.Request.Context(), userID, contactID)
 	if err != nil {
+		if errors.Is(err, service.ErrNotCached) {
+			respondNotCached(c)
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, contact)
+	c.JSON(http.StatusOK, selectFields(contact, fields))
 }
 
-// ListUserContacts handles GET /api/v1/users/:userId/contacts
+// ListUsersByCreatedDate handles GET /api/v1/users/by-created?from=&to=
+// from and to are RFC3339 timestamps, both inclusive; users come back oldest first.
+func (h *AppHandler) ListUsersByCreatedDate(c *gin.Context) {
+	fields, ok := requestedFields[models.UserEntity](c)
+	if !ok {
+		return
+	}
+	from, err := time.Parse(time.RFC3339, c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be an RFC3339 timestamp"})
+		return
+	}
+	to, err := time.Parse(time.RFC3339, c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be an RFC3339 timestamp"})
+		return
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+		return
+	}
+
+	users, err := h.appService.ListUsersByCreatedDate(c.Request.Context(), from, to)
+	if errors.Is(err, service.ErrNotCached) {
+		respondNotCached(c)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users": selectFields(users, fields),
+		"count": len(users),
+	})
+}
+
+// GetUserByEmail handles GET /api/v1/users/by-email?email= (case-insensitive)
+func (h *AppHandler) GetUserByEmail(c *gin.Context) {
+	email := c.Query("email")
+	if email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "email query parameter is required"})
+		return
+	}
+	fields, ok := requestedFields[models.UserEntity](c)
+	if !ok {
+		return
+	}
+
+	user, err := h.appService.GetUserByEmail(c.Request.Context(), email)
+	if errors.Is(err, service.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrNotCached) {
+		respondNotCached(c)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, selectFields(user, fields))
+}
+
+// SearchContacts handles GET /api/v1/users/:id/contacts/search?company=&tag=&favorite=&q=
+// Set params are ANDed; the response echoes the filters applied and whether the favorites
+// index or a filtered read of every contact served them (see service.SearchContacts).
+func (h *AppHandler) SearchContacts(c *gin.Context) {
+	search := service.ContactSearch{
+		Company: c.Query("company"),
+		Tag:     c.Query("tag"),
+		Query:   c.Query("q"),
+	}
+	if raw := c.Query("favorite"); raw != "" {
+		favorite, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "favorite must be true or false"})
+			return
+		}
+		search.Favorite = &favorite
+	}
+
+	result, err := h.appService.SearchContacts(c.Request.Context(), c.Param("id"), search)
+	if errors.Is(err, service.ErrNotCached) {
+		respondNotCached(c)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetContactByEmail handles GET /api/v1/users/:id/contacts/by-email?email=
+func (h *AppHandler) GetContactByEmail(c *gin.Context) {
+	userID := c.Param("id")
+	email := c.Query("email")
+
+	if email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "email query parameter is required"})
+		return
+	}
+	fields, ok := requestedFields[models.ContactEntity](c)
+	if !ok {
+		return
+	}
+
+	contact, err := h.appService.GetContactByEmail(c.Request.Context(), userID, email)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "contact not found"})
+		return
+	}
+	if errors.Is(err, service.ErrNotCached) {
+		respondNotCached(c)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, selectFields(contact, fields))
+}
+
+// GetContactsByIDs handles POST /api/v1/users/:id/contacts/batch-get
+// Responds with contacts in the order of the requested ids; missing ones are null.
+func (h *AppHandler) GetContactsByIDs(c *gin.Context) {
+	userID := c.Param("id")
+
+	var req struct {
+		IDs []string `json:"ids" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contacts, err := h.appService.GetContactsByIDs(c.Request.Context(), userID, req.IDs)
+	if errors.Is(err, service.ErrTooManyIDs) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrNotCached) {
+		respondNotCached(c)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"contacts": contacts})
+}
+
+// ListUserContacts handles GET /api/v1/users/:id/contacts
+// Supports ?limit=&cursor= paging with X-Total-Count, X-Next-Cursor and Link headers.
+// Pass ?format=ndjson to stream one contact per line instead of a buffered array.
 func (h *AppHandler) ListUserContacts(c *gin.Context) {
-	userID := c.Param("userId")
+	userID := c.Param("id")
+	fields, ok := requestedFields[models.ContactEntity](c)
+	if !ok {
+		return
+	}
+
+	if wantsNDJSON(c) {
+		if service.IsCacheOnly(c.Request.Context()) {
+			// Streams always read DynamoDB directly
+			respondNotCached(c)
+			return
+		}
+		pager, err := h.appService.UserContactPages(c.Request.Context(), userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		streamNDJSON[*models.ContactEntity](c, pager, fields)
+		return
+	}
 
 	contacts, err := h.appService.ListUserContacts(c.Request.Context(), userID)
 	if err != nil {
+		if errors.Is(err, service.ErrNotCached) {
+			respondNotCached(c)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"contacts": contacts, "count": len(contacts)})
+	p, err := parsePage(c, len(contacts))
+	if err != nil {
+		respondPaginationError(c, err)
+		return
+	}
+	setPaginationHeaders(c, len(contacts), p)
+
+	pageItems := contacts[p.start:p.end]
+	c.JSON(http.StatusOK, gin.H{
+		"contacts":    selectFields(pageItems, fields),
+		"count":       len(pageItems),
+		"total":       len(contacts),
+		"limit":       p.limit,
+		"next_cursor": p.nextCursor,
+	})
 }
 
-// ListFavoriteContacts handles GET /api/v1/users/:userId/contacts/favorites
+// ListFavoriteContacts handles GET /api/v1/users/:id/contacts/favorites
+// Supports ?limit=&cursor= paging with X-Total-Count, X-Next-Cursor and Link headers.
 func (h *AppHandler) ListFavoriteContacts(c *gin.Context) {
-	userID := c.Param("userId")
+	userID := c.Param("id")
+	fields, ok := requestedFields[models.ContactEntity](c)
+	if !ok {
+		return
+	}
 
 	contacts, err := h.appService.ListFavoriteContacts(c.Request.Context(), userID)
 	if err != nil {
+		if errors.Is(err, service.ErrNotCached) {
+			respondNotCached(c)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"favorites": contacts, "count": len(contacts)})
+	p, err := parsePage(c, len(contacts))
+	if err != nil {
+		respondPaginationError(c, err)
+		return
+	}
+	setPaginationHeaders(c, len(contacts), p)
+
+	pageItems := contacts[p.start:p.end]
+	c.JSON(http.StatusOK, gin.H{
+		"favorites":   selectFields(pageItems, fields),
+		"count":       len(pageItems),
+		"total":       len(contacts),
+		"limit":       p.limit,
+		"next_cursor": p.nextCursor,
+	})
 }
 
-// UpdateContact handles PUT /api/v1/users/:userId/contacts/:contactId
+// UpdateContact handles PUT /api/v1/users/:id/contacts/:contactId
+// Updates naming a reserved attribute (keys, index keys, EntityType, CreatedAt) get 400.
+// With If-Unmodified-Since the update only applies if the contact hasn't changed since; else 412.
 func (h *AppHandler) UpdateContact(c *gin.Context) {
-	userID := c.Param("userId")
+	userID := c.Param("id")
 	contactID := c.Param("contactId")
 	
 	var updates map[string]interface{}
@@ -187,8 +653,16 @@ func (h *AppHandler) UpdateContact(c *gin.Context) {
 		return
 	}
 
-	contact, err := h.appService.UpdateContact(c.Request.Context(), userID, contactID, updates)
+	contact, err := h.appService.UpdateContactIfUnmodifiedSince(c.Request.Context(), userID, contactID, updates, ifUnmodifiedSince(c))
 	if err != nil {
+		if errors.Is(err, validation.ErrValidation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -196,9 +670,91 @@ func (h *AppHandler) UpdateContact(c *gin.Context) {
 	c.JSON(http.StatusOK, contact)
 }
 
-// DeleteContact handles DELETE /api/v1/users/:userId/contacts/:contactId
+// BulkUpdateContacts handles POST /api/v1/users/:id/contacts/bulk-update
+// Responds 200 with per-ID results; check each entry's success flag.
+func (h *AppHandler) BulkUpdateContacts(c *gin.Context) {
+	userID := c.Param("id")
+
+	var req struct {
+		IDs     []string               `json:"ids" binding:"required,min=1"`
+		Updates map[string]interface{} `json:"updates" binding:"required"`
+		Atomic  bool                   `json:"atomic"` // All or nothing; rejects batches larger than one transaction
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Updates) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "updates must not be empty"})
+		return
+	}
+
+	results, err := h.appService.BulkUpdateContacts(c.Request.Context(), userID, req.IDs, req.Updates, req.Atomic)
+	if err != nil {
+		var tooLarge *repository.TransactionTooLargeError
+		if errors.Is(err, validation.ErrValidation) || errors.As(err, &tooLarge) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+// RenameTag handles POST /api/v1/users/:id/tags/rename
+// Responds 200 with the number of contacts changed (and failed, if any were edited meanwhile).
+func (h *AppHandler) RenameTag(c *gin.Context) {
+	userID := c.Param("id")
+
+	var req struct {
+		From string `json:"from" binding:"required"`
+		To   string `json:"to" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.appService.RenameTag(c.Request.Context(), userID, req.From, req.To)
+	respondTagChange(c, result, err)
+}
+
+// RemoveTag handles POST /api/v1/users/:id/tags/remove
+func (h *AppHandler) RemoveTag(c *gin.Context) {
+	userID := c.Param("id")
+
+	var req struct {
+		Tag string `json:"tag" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.appService.RemoveTag(c.Request.Context(), userID, req.Tag)
+	respondTagChange(c, result, err)
+}
+
+// respondTagChange writes the outcome of RenameTag or RemoveTag
+func respondTagChange(c *gin.Context, result *service.TagChangeResult, err error) {
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTag) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// DeleteContact handles DELETE /api/v1/users/:id/contacts/:contactId
 func (h *AppHandler) DeleteContact(c *gin.Context) {
-	userID := c.Param("userId")
+	userID := c.Param("id")
 	contactID := c.Param("contactId")
 
 	if err := h.appService.DeleteContact(c.Request.Context(), userID, contactID); err != nil {
@@ -208,3 +764,325 @@ func (h *AppHandler) DeleteContact(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Contact deleted successfully"})
 }
+
+// ListDeletedContacts handles GET /api/v1/users/:id/contacts/trash
+// Lists soft-deleted contacts that can still be restored or are awaiting purge.
+func (h *AppHandler) ListDeletedContacts(c *gin.Context) {
+	userID := c.Param("id")
+	fields, ok := requestedFields[models.ContactEntity](c)
+	if !ok {
+		return
+	}
+
+	contacts, err := h.appService.ListDeletedContacts(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"contacts": selectFields(contacts, fields), "count": len(contacts)})
+}
+
+// ListContactChanges handles GET /api/v1/users/:id/contacts/changes?since=
+// since is an RFC3339 timestamp (inclusive); pass the response's next_since on the next
+// poll. Soft-deleted contacts come back with "deleted": true.
+func (h *AppHandler) ListContactChanges(c *gin.Context) {
+	userID := c.Param("id")
+
+	since, err := time.Parse(time.RFC3339, c.Query("since"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC3339 timestamp"})
+		return
+	}
+
+	changes, err := h.appService.ListContactChanges(c.Request.Context(), userID, since)
+	if errors.Is(err, service.ErrNotCached) {
+		respondNotCached(c)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+// ExportUserData handles GET /api/v1/users/:id/export
+// Streams everything stored about the user (profile, contacts, trashed contacts, audit
+// history) as one JSON download, for data-export requests. Only the user may export
+// their own data: anonymous requests get 401 and other users 403. The status is sent
+// before the contacts are read, so a failure mid-export cuts the download short
+// (leaving invalid JSON) instead of returning an error status.
+func (h *AppHandler) ExportUserData(c *gin.Context) {
+	userID := c.Param("id")
+
+	switch caller := c.GetString(ContextUserIDKey); caller {
+	case "":
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	case userID:
+	default:
+		c.JSON(http.StatusForbidden, gin.H{"error": "users can only export their own data"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	export, err := h.appService.ExportUserData(ctx, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotCached):
+			respondNotCached(c)
+		case errors.Is(err, service.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.Header("Content-Disposition", `attachment; filename="user-`+userID+`-export.json"`)
+	c.Status(http.StatusOK)
+	if err := writeUserExport(ctx, c.Writer, export); err != nil {
+		requestid.Logf(ctx, "Warning: export of user %s aborted: %v", userID, err)
+	}
+}
+
+// RestoreContact handles POST /api/v1/users/:id/contacts/:contactId/restore
+// Takes no body. Contacts deleted longer ago than the trash retention get 410.
+func (h *AppHandler) RestoreContact(c *gin.Context) {
+	userID := c.Param("id")
+	contactID := c.Param("contactId")
+
+	contact, err := h.appService.RestoreContact(c.Request.Context(), userID, contactID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrRetentionExpired):
+			c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, contact)
+}
+
+// CloneContact handles POST /api/v1/users/:id/contacts/:contactId/clone
+func (h *AppHandler) CloneContact(c *gin.Context) {
+	userID := c.Param("id")
+	contactID := c.Param("contactId")
+
+	contact, err := h.appService.CloneContact(c.Request.Context(), userID, contactID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, validation.ErrValidation):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, contact)
+}
+
+// ContactAvatarRequest describes the image the client is about to upload
+type ContactAvatarRequest struct {
+	ContentType string `json:"content_type" binding:"required"`
+	Size        int64  `json:"size" binding:"required"`
+}
+
+// PresignContactAvatar handles POST /api/v1/users/:id/contacts/:contactId/avatar
+// Responds with a presigned S3 PUT URL; the client uploads the image there itself,
+// sending the returned headers, and the contact's avatar_url already points at it.
+func (h *AppHandler) PresignContactAvatar(c *gin.Context) {
+	userID := c.Param("id")
+	contactID := c.Param("contactId")
+
+	var req ContactAvatarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	upload, err := h.appService.PresignContactAvatar(c.Request.Context(), userID, contactID, req.ContentType, req.Size)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAvatarUploadsDisabled):
+			c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+		case errors.Is(err, repository.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, validation.ErrValidation):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, upload)
+}
+
+// TransferContactRequest names the user a contact is moved to
+type TransferContactRequest struct {
+	ToUserID string `json:"to_user_id" binding:"required,userid"`
+}
+
+// TransferContact handles POST /api/v1/users/:id/contacts/:contactId/transfer
+func (h *AppHandler) TransferContact(c *gin.Context) {
+	userID := c.Param("id")
+	contactID := c.Param("contactId")
+
+	var req TransferContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor := c.GetString(ContextUserIDKey)
+	if actor == "" {
+		actor = "anonymous"
+	}
+
+	contact, err := h.appService.MoveContact(c.Request.Context(), userID, contactID, req.ToUserID, actor)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrSameOwner):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, contact)
+}
+
+// ============================================================================
+// ADMIN HANDLERS
+// ============================================================================
+
+// FlushCacheRequest selects which cache entries to evict
+type FlushCacheRequest struct {
+	Scope string `json:"scope" binding:"required,oneof=user contacts all"`
+	ID    string `json:"id"`
+}
+
+// FlushCache handles POST /api/v1/admin/cache/flush
+func (h *AppHandler) FlushCache(c *gin.Context) {
+	var req FlushCacheRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Audit trail: who flushed what, correlated by request ID
+	requestid.Logf(c.Request.Context(), "Admin cache flush requested by %s: scope=%s id=%q", c.ClientIP(), req.Scope, req.ID)
+
+	deleted, err := h.appService.FlushCache(c.Request.Context(), req.Scope, req.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidFlushScope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "deleted": deleted})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"scope": req.Scope, "id": req.ID, "deleted": deleted})
+}
+
+// Reindex handles POST /api/v1/admin/reindex
+// Scans the whole table, so it can run for a while on large tables; safe to rerun.
+func (h *AppHandler) Reindex(c *gin.Context) {
+	requestid.Logf(c.Request.Context(), "Admin reindex requested by %s", c.ClientIP())
+
+	result, err := h.appService.Reindex(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "result": result})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListAllContacts handles GET /api/v1/admin/contacts?limit=&cursor=&entity=&company=&tag=&favorite=&q=
+// Pages through every user's contacts in a stable order; the filters are the ones
+// SearchContacts takes. Filtering happens after the limit, so a page can be short (or
+// empty) while next_cursor is still set.
+func (h *AppHandler) ListAllContacts(c *gin.Context) {
+	fields, ok := requestedFields[models.ContactEntity](c)
+	if !ok {
+		return
+	}
+	opts := service.ContactListOptions{
+		Cursor:     c.Query("cursor"),
+		EntityType: c.Query("entity"),
+		Filter: service.ContactSearch{
+			Company: c.Query("company"),
+			Tag:     c.Query("tag"),
+			Query:   c.Query("q"),
+		},
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		opts.Limit = limit
+	}
+	if raw := c.Query("favorite"); raw != "" {
+		favorite, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "favorite must be true or false"})
+			return
+		}
+		opts.Filter.Favorite = &favorite
+	}
+
+	page, err := h.appService.ListAllContacts(c.Request.Context(), opts)
+	if errors.Is(err, pagination.ErrInvalidCursor) || errors.Is(err, validation.ErrValidation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrNotCached) {
+		respondNotCached(c)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"contacts":    selectFields(page.Contacts, fields),
+		"count":       len(page.Contacts),
+		"next_cursor": page.NextCursor,
+	})
+}
+
+// ListAuditEntries handles GET /api/v1/admin/audit?entity_id=...
+func (h *AppHandler) ListAuditEntries(c *gin.Context) {
+	entityID := c.Query("entity_id")
+	if entityID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "entity_id query parameter is required"})
+		return
+	}
+
+	entries, err := h.appService.ListAuditEntries(c.Request.Context(), entityID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries, "count": len(entries)})
+}
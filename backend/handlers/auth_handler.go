@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/tenant"
+)
+
+// AuthHandler issues and revokes sessions
+type AuthHandler struct {
+	sessions *repository.SessionStore
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(sessions *repository.SessionStore) *AuthHandler {
+	return &AuthHandler{sessions: sessions}
+}
+
+// CreateSessionRequest optionally binds the session to a tenant
+type CreateSessionRequest struct {
+	TenantID string `json:"tenant_id"`
+}
+
+// CreateSession handles POST /api/v1/admin/users/:id/sessions
+// Issues a session token for a user. Admin-only until there's a login flow.
+// With a tenant_id, requests on the session use that tenant's table.
+func (h *AuthHandler) CreateSession(c *gin.Context) {
+	userID := c.Param("id")
+
+	var req CreateSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.TenantID != "" && !tenant.Valid(req.TenantID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tenant_id must be 1-32 lowercase letters, digits or dashes"})
+		return
+	}
+
+	token, err := h.sessions.Create(c.Request.Context(), userID, req.TenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := gin.H{"token": token, "user_id": userID}
+	if req.TenantID != "" {
+		response["tenant_id"] = req.TenantID
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// Logout handles DELETE /api/v1/auth/session
+// Revokes the session the request was authenticated with.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	token := c.GetString(contextSessionTokenKey)
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	if err := h.sessions.Revoke(c.Request.Context(), token); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// LogoutEverywhere handles DELETE /api/v1/auth/sessions
+// Revokes every session of the authenticated user.
+func (h *AuthHandler) LogoutEverywhere(c *gin.Context) {
+	userID := c.GetString(ContextUserIDKey)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	revoked, err := h.sessions.RevokeAll(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out everywhere", "sessions_revoked": revoked})
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================================================
+// PARTIAL RESPONSES
+// ============================================================================
+
+// knownFields caches the JSON field names of each entity type, keyed by reflect.Type
+var knownFields sync.Map
+
+// requestedFields parses ?fields=id,name,email for a response about entities of type T.
+// It returns nil when the parameter is absent (the full entity is sent), and responds
+// 400 and returns ok=false when a name isn't one of T's JSON fields.
+func requestedFields[T any](c *gin.Context) (fields []string, ok bool) {
+	raw, present := c.GetQuery("fields")
+	if !present {
+		return nil, true
+	}
+
+	known := jsonFields(reflect.TypeFor[T]())
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !known[name] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown field %q; fields are %s", name, strings.Join(sortedNames(known), ", "))})
+			return nil, false
+		}
+		fields = append(fields, name)
+	}
+	if len(fields) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "fields must name at least one field"})
+		return nil, false
+	}
+	return fields, true
+}
+
+// selectFields trims an entity, or each entity of a slice, to fields. With no fields
+// v is returned as is. Filtering works on the marshaled JSON, so the names are exactly
+// the ones a full response uses.
+func selectFields(v interface{}, fields []string) interface{} {
+	if fields == nil {
+		return v
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+
+	pick := func(entity map[string]json.RawMessage) map[string]json.RawMessage {
+		picked := make(map[string]json.RawMessage, len(fields))
+		for _, name := range fields {
+			if value, ok := entity[name]; ok {
+				picked[name] = value
+			}
+		}
+		return picked
+	}
+
+	var list []map[string]json.RawMessage
+	if json.Unmarshal(data, &list) == nil {
+		for i, entity := range list {
+			list[i] = pick(entity)
+		}
+		return list
+	}
+	var entity map[string]json.RawMessage
+	if json.Unmarshal(data, &entity) == nil {
+		return pick(entity)
+	}
+	return v
+}
+
+// jsonFields returns the JSON names of a struct type's fields, including those of
+// embedded structs; fields tagged json:"-" are left out
+func jsonFields(t reflect.Type) map[string]bool {
+	if cached, ok := knownFields.Load(t); ok {
+		return cached.(map[string]bool)
+	}
+
+	fields := make(map[string]bool)
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" || !field.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(tag, ",")
+			if field.Anonymous && name == "" {
+				collect(field.Type)
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			fields[name] = true
+		}
+	}
+	collect(t)
+
+	knownFields.Store(t, fields)
+	return fields
+}
+
+// sortedNames lists a field set alphabetically for error messages
+func sortedNames(fields map[string]bool) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
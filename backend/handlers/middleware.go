@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"hub-control-plane/backend/clock"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/requestid"
+	"hub-control-plane/backend/service"
+	"hub-control-plane/backend/tenant"
+	"hub-control-plane/backend/tracing"
+)
+
+// ContextUserIDKey is the gin context key the auth middleware stores the
+// authenticated user ID under
+const ContextUserIDKey = "userID"
+
+// contextSessionTokenKey holds the bearer token of an authenticated request,
+// so logout can revoke the session it came in on
+const contextSessionTokenKey = "sessionToken"
+
+// ============================================================================
+// TRACING
+// ============================================================================
+
+// Tracing opens a server span per request, continuing the caller's trace when a
+// W3C traceparent header is present. Service and repository spans hang off it
+// through the request context. A no-op unless tracing.Init configured an exporter.
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		// Name by route template so /users/1 and /users/2 group together
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		ctx, span := tracing.Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", c.Request.URL.Path),
+			))
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if id := requestid.FromContext(c.Request.Context()); id != "" {
+			span.SetAttributes(attribute.String("request.id", id))
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
+// ============================================================================
+// REQUEST ID
+// ============================================================================
+
+// RequestID tags each request with an ID (the caller's X-Request-ID, or a new UUID),
+// echoes it in the response, and stores it in the request context so service and
+// repository log lines for the request can be correlated.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if id == "" {
+			id = uuid.New().String()
+		}
+
+		c.Header(requestid.Header, id)
+		c.Request = c.Request.WithContext(requestid.WithID(c.Request.Context(), id))
+
+		c.Next()
+	}
+}
+
+// ============================================================================
+// REQUEST VALIDATION
+// ============================================================================
+
+// RequireJSON rejects write requests (POST/PUT/PATCH) that don't carry a JSON body.
+// Non-JSON content types get 415 and empty bodies get 400, so clients see a clear
+// error instead of whatever ShouldBindJSON makes of a form or empty payload. Attach
+// it to the routes that bind a body, not to a group holding body-less actions.
+func RequireJSON() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			c.Next()
+			return
+		}
+
+		contentType := c.ContentType()
+		if contentType != "application/json" && !strings.HasSuffix(contentType, "+json") {
+			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{
+				"error": "Content-Type must be application/json",
+			})
+			return
+		}
+
+		if c.Request.Body == nil || c.Request.Body == http.NoBody || c.Request.ContentLength == 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "request body is required"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ============================================================================
+// CACHE-ONLY READS
+// ============================================================================
+
+// CacheOnly serves a request from cache alone when it sends
+// "Cache-Control: only-if-cached": cached reads that miss fail with
+// service.ErrNotCached (503 over REST, code NOT_CACHED over GraphQL) instead of
+// querying DynamoDB. Reporting clients opt in per request; nothing changes without it.
+func CacheOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if onlyIfCached(c.GetHeader("Cache-Control")) {
+			c.Request = c.Request.WithContext(service.WithCacheOnly(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
+
+// onlyIfCached reports whether a Cache-Control header carries the only-if-cached directive
+func onlyIfCached(header string) bool {
+	for _, directive := range strings.Split(header, ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "only-if-cached") {
+			return true
+		}
+	}
+	return false
+}
+
+// respondNotCached writes the 503 for a cache-only read that missed
+func respondNotCached(c *gin.Context) {
+	c.JSON(http.StatusServiceUnavailable, gin.H{"error": service.ErrNotCached.Error()})
+}
+
+// ============================================================================
+// CONFLICT LOGGING
+// ============================================================================
+
+// TrackReads gives each request a read set, so the repository's conflict logging
+// (DYNAMODB_LOG_CONFLICTS) can spot an update that overwrote a write made after this
+// request read the item. With conflict logging off nothing is ever recorded in it.
+func TrackReads() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(repository.WithReadTracking(c.Request.Context()))
+		c.Next()
+	}
+}
+
+// ============================================================================
+// SESSION AUTH
+// ============================================================================
+
+// Authenticate resolves an "Authorization: Bearer <token>" header to a user via the
+// session store and stores the user ID under ContextUserIDKey. A session issued for a
+// tenant also puts the tenant on the request context, which routes its DynamoDB calls
+// and cache keys to that tenant. Requests without a token pass through anonymously;
+// unknown, expired or revoked tokens get 401 even if the client thinks they're still
+// valid. A nil store disables authentication.
+func Authenticate(sessions *repository.SessionStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if sessions == nil || header == "" {
+			c.Next()
+			return
+		}
+
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "malformed Authorization header"})
+			return
+		}
+
+		userID, tenantID, err := sessions.Validate(c.Request.Context(), token)
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or revoked session"})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "session store unavailable"})
+			return
+		}
+
+		c.Set(ContextUserIDKey, userID)
+		c.Set(contextSessionTokenKey, token)
+		if tenantID != "" {
+			c.Request = c.Request.WithContext(tenant.WithID(c.Request.Context(), tenantID))
+		}
+		c.Next()
+	}
+}
+
+// ============================================================================
+// ADMIN AUTH
+// ============================================================================
+
+// RequireAdminToken guards admin routes with a static bearer token.
+// An empty token disables the routes entirely rather than leaving them open.
+func RequireAdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin API is disabled"})
+			return
+		}
+
+		if !hasAdminToken(c, token) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid admin token"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// MarkAdmin flags requests bearing the admin token with service.WithAdmin and lets
+// everything else through unchanged. It's for endpoints that mix public and admin
+// operations (GraphQL), where the resolvers enforce the flag per operation.
+func MarkAdmin(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token != "" && hasAdminToken(c, token) {
+			c.Request = c.Request.WithContext(service.WithAdmin(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
+
+// hasAdminToken reports whether the request's bearer token is the admin token
+func hasAdminToken(c *gin.Context, token string) bool {
+	given := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+// ============================================================================
+// ACTIVITY TRACKING
+// ============================================================================
+
+// ActivityTracker records "last active" timestamps for authenticated users.
+// Writes are throttled per user so we hit DynamoDB at most once per interval.
+// Entries older than the interval throttle nothing, so they are swept out once per
+// interval and the map only holds the users active within the last two.
+type ActivityTracker struct {
+	appService *service.AppServiceWithCache
+	interval   time.Duration
+
+	mu        sync.Mutex
+	lastSeen  map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewActivityTracker creates a tracker that touches each user at most once per interval
+func NewActivityTracker(appService *service.AppServiceWithCache, interval time.Duration) *ActivityTracker {
+	return &ActivityTracker{
+		appService: appService,
+		interval:   interval,
+		lastSeen:   make(map[string]time.Time),
+	}
+}
+
+// Middleware returns a gin middleware that touches the authenticated user.
+// Unauthenticated requests (no user ID in context) are ignored.
+func (t *ActivityTracker) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString(ContextUserIDKey)
+		if userID != "" && t.shouldTouch(userID) {
+			// Don't hold up the request for a bookkeeping write (but keep its tenant)
+			touchCtx := tenant.WithID(context.Background(), tenant.FromContext(c.Request.Context()))
+			go func() {
+				ctx, cancel := context.WithTimeout(touchCtx, 5*time.Second)
+				defer cancel()
+
+				if err := t.appService.TouchUser(ctx, userID); err != nil {
+					log.Printf("Warning: failed to record activity for user %s: %v", userID, err)
+				}
+			}()
+		}
+
+		c.Next()
+	}
+}
+
+// shouldTouch reports whether the user is due for an activity write and,
+// if so, marks them as touched now
+func (t *ActivityTracker) shouldTouch(userID string) bool {
+	now := clock.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.lastSweep) >= t.interval {
+		for id, last := range t.lastSeen {
+			if now.Sub(last) >= t.interval {
+				delete(t.lastSeen, id)
+			}
+		}
+		t.lastSweep = now
+	}
+
+	if last, ok := t.lastSeen[userID]; ok && now.Sub(last) < t.interval {
+		return false
+	}
+	t.lastSeen[userID] = now
+	return true
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"hub-control-plane/backend/clock"
+)
+
+func TestActivityTracker_EvictsIdleUsers(t *testing.T) {
+	fixed := clock.NewFixed(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	t.Cleanup(clock.Set(fixed))
+	tracker := NewActivityTracker(nil, time.Hour)
+
+	if !tracker.shouldTouch("u1") || !tracker.shouldTouch("u2") {
+		t.Fatal("first request of a user wasn't touched")
+	}
+	fixed.Advance(30 * time.Minute)
+	if tracker.shouldTouch("u1") {
+		t.Error("u1 touched again within the interval")
+	}
+
+	// u2 stays idle; the next sweep drops both, and u3 is the only user left
+	fixed.Advance(time.Hour)
+	if !tracker.shouldTouch("u3") {
+		t.Fatal("u3 not touched")
+	}
+	if len(tracker.lastSeen) != 1 {
+		t.Errorf("tracker holds %d users, want only u3", len(tracker.lastSeen))
+	}
+	if !tracker.shouldTouch("u1") {
+		t.Error("u1 not touched again after the interval")
+	}
+}
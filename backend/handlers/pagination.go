@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/gin-gonic/gin"
+	"hub-control-plane/backend/pagination"
+)
+
+// Pagination response headers
+const (
+	headerTotalCount = "X-Total-Count"
+	headerNextCursor = "X-Next-Cursor"
+	headerPageLimit  = "X-Page-Limit"
+	headerLink       = "Link"
+)
+
+// page is the window of a list selected by ?limit= and ?cursor=
+type page struct {
+	start      int
+	end        int
+	limit      int // applied page size, after clamping to pagination.MaxPageSize
+	nextCursor string
+}
+
+// parsePage resolves ?limit and ?cursor against a list of total items.
+// A missing or oversized limit is clamped to pagination.MaxPageSize rather than rejected.
+func parsePage(c *gin.Context, total int) (page, error) {
+	p := page{start: 0, end: total, limit: pagination.MaxPageSize()}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		offset, err := decodeCursor(cursor)
+		if err != nil {
+			return p, err
+		}
+		p.start = offset
+	}
+	if p.start > total {
+		p.start = total
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return p, fmt.Errorf("limit must be a positive integer")
+		}
+		p.limit = pagination.ClampLimit(limit)
+	}
+	if p.start+p.limit < total {
+		p.end = p.start + p.limit
+		p.nextCursor = encodeCursor(p.end)
+	}
+
+	return p, nil
+}
+
+// setPaginationHeaders adds X-Total-Count and X-Page-Limit, and X-Next-Cursor plus
+// an RFC 5988 Link rel="next" header when there is another page
+func setPaginationHeaders(c *gin.Context, total int, p page) {
+	c.Header(headerTotalCount, strconv.Itoa(total))
+	c.Header(headerPageLimit, strconv.Itoa(p.limit))
+	if p.nextCursor == "" {
+		return
+	}
+
+	c.Header(headerNextCursor, p.nextCursor)
+
+	next := *c.Request.URL
+	query := next.Query()
+	query.Set("cursor", p.nextCursor)
+	next.RawQuery = query.Encode()
+	c.Header(headerLink, fmt.Sprintf("<%s>; rel=\"next\"", next.RequestURI()))
+}
+
+// respondPaginationError writes the 400 for a bad limit or cursor
+func respondPaginationError(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// encodeCursor makes an opaque, signed cursor from a list offset
+func encodeCursor(offset int) string {
+	return pagination.EncodeCursor(map[string]types.AttributeValue{
+		"offset": &types.AttributeValueMemberN{Value: strconv.Itoa(offset)},
+	})
+}
+
+// decodeCursor reverses encodeCursor
+func decodeCursor(cursor string) (int, error) {
+	key, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := key["offset"].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, pagination.ErrInvalidCursor
+	}
+	offset, err := strconv.Atoi(n.Value)
+	if err != nil || offset < 0 {
+		return 0, pagination.ErrInvalidCursor
+	}
+	return offset, nil
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"hub-control-plane/backend/models"
+)
+
+// ============================================================================
+// SORTING
+// ============================================================================
+
+// userSorts compares users by each ?sort= field of GET /api/v1/users.
+// Strings compare case-insensitively, the way emails are matched elsewhere.
+var userSorts = map[string]func(a, b *models.UserEntity) int{
+	"email": func(a, b *models.UserEntity) int {
+		return strings.Compare(strings.ToLower(a.Email), strings.ToLower(b.Email))
+	},
+	"created": func(a, b *models.UserEntity) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	},
+	"name": func(a, b *models.UserEntity) int {
+		return cmp.Or(
+			strings.Compare(strings.ToLower(a.FirstName), strings.ToLower(b.FirstName)),
+			strings.Compare(strings.ToLower(a.LastName), strings.ToLower(b.LastName)),
+		)
+	},
+}
+
+// userSort resolves ?sort=email|created|name and ?order=asc|desc (default asc) into a
+// comparison, or nil when no sort is requested and users keep their GSI1 order
+func userSort(c *gin.Context) (func(a, b *models.UserEntity) int, error) {
+	field, order := c.Query("sort"), c.DefaultQuery("order", "asc")
+	if order != "asc" && order != "desc" {
+		return nil, fmt.Errorf("order must be asc or desc")
+	}
+	if field == "" {
+		return nil, nil
+	}
+
+	compare, ok := userSorts[field]
+	if !ok {
+		return nil, fmt.Errorf("unknown sort field %q; sort by email, created or name", field)
+	}
+	if order == "desc" {
+		return func(a, b *models.UserEntity) int { return compare(b, a) }, nil
+	}
+	return compare, nil
+}
+
+// sortUsers orders users by compare; equal users keep their relative order, so paging
+// through a sorted list with ?cursor= is stable between requests
+func sortUsers(users []*models.UserEntity, compare func(a, b *models.UserEntity) int) {
+	if compare != nil {
+		slices.SortStableFunc(users, compare)
+	}
+}
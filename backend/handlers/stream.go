@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/service"
+)
+
+// ndjsonContentType is the media type for newline-delimited JSON responses
+const ndjsonContentType = "application/x-ndjson"
+
+// wantsNDJSON reports whether the client asked for a streamed list (?format=ndjson)
+func wantsNDJSON(c *gin.Context) bool {
+	return c.Query("format") == "ndjson"
+}
+
+// streamNDJSON writes every item from the pager as one JSON object per line,
+// flushing after each DynamoDB page so memory stays flat regardless of result size.
+// Once streaming has started the status can't change, so a mid-stream failure is
+// reported as a final {"error": ...} line. Items are trimmed to fields when set.
+func streamNDJSON[T any](c *gin.Context, pager *repository.QueryPager, fields []string) {
+	ctx := c.Request.Context()
+
+	c.Header("Content-Type", ndjsonContentType)
+	c.Status(http.StatusOK)
+
+	c.Stream(func(w io.Writer) bool {
+		if !pager.HasMorePages() {
+			return false
+		}
+
+		var page []T
+		enc := json.NewEncoder(w)
+		if err := pager.NextPage(ctx, &page); err != nil {
+			log.Printf("Warning: NDJSON stream aborted: %v", err)
+			_ = enc.Encode(gin.H{"error": err.Error()})
+			return false
+		}
+
+		for _, item := range page {
+			if err := enc.Encode(selectFields(item, fields)); err != nil {
+				// Client went away
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// writeUserExport streams export as one JSON object, flushing after each DynamoDB page:
+// {"exported_at", "user", "contacts": [...], "deleted_contacts": [...], "audit": [...]}
+func writeUserExport(ctx context.Context, w gin.ResponseWriter, export *service.UserExport) error {
+	head, err := json.Marshal(struct {
+		ExportedAt time.Time          `json:"exported_at"`
+		User       *models.UserEntity `json:"user"`
+	}{export.ExportedAt, export.User})
+	if err != nil {
+		return err
+	}
+	// Reopen the object to append the streamed lists
+	if _, err := w.Write(head[:len(head)-1]); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, `,"contacts":`); err != nil {
+		return err
+	}
+	if err := writeJSONArray[*models.ContactEntity](ctx, w, export.Contacts); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, `,"deleted_contacts":`); err != nil {
+		return err
+	}
+	if err := writeJSONArray[*models.ContactEntity](ctx, w, export.DeletedContacts); err != nil {
+		return err
+	}
+
+	audit := export.Audit
+	if audit == nil {
+		audit = []*models.AuditEntry{}
+	}
+	tail, err := json.Marshal(audit)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, `,"audit":`+string(tail)+"}"); err != nil {
+		return err
+	}
+	w.Flush()
+	return nil
+}
+
+// writeJSONArray writes every item from the pager as one JSON array, flushing after
+// each DynamoDB page
+func writeJSONArray[T any](ctx context.Context, w gin.ResponseWriter, pager *repository.QueryPager) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	first := true
+	for pager.HasMorePages() {
+		var page []T
+		if err := pager.NextPage(ctx, &page); err != nil {
+			return err
+		}
+		for _, item := range page {
+			data, err := json.Marshal(item)
+			if err != nil {
+				return err
+			}
+			if !first {
+				data = append([]byte{','}, data...)
+			}
+			first = false
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+	}
+	_, err := io.WriteString(w, "]")
+	return err
+}
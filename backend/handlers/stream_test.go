@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/gin-gonic/gin"
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/service"
+)
+
+// contactPages is a DynamoDB endpoint answering the nth Query with pages[n] (contact
+// IDs), chained with LastEvaluatedKey. With truncated set the last page claims there
+// is more, and the query for it fails.
+type contactPages struct {
+	pages     [][]string
+	truncated bool
+}
+
+func (f *contactPages) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var in struct{ ExclusiveStartKey map[string]map[string]string }
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	page := 0
+	if start := in.ExclusiveStartKey; start != nil {
+		page = len(start["SK"]["S"]) // LastEvaluatedKey SK is "#" repeated page times
+	}
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	if page >= len(f.pages) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"__type":  "com.amazonaws.dynamodb.v20120810#ValidationException",
+			"message": "page gone",
+		})
+		return
+	}
+
+	items := make([]map[string]map[string]string, 0, len(f.pages[page]))
+	for _, id := range f.pages[page] {
+		items = append(items, map[string]map[string]string{
+			"PK":    {"S": "USER#u1"},
+			"SK":    {"S": "CONTACT#" + id},
+			"ID":    {"S": id},
+			"Email": {"S": id + "@example.com"},
+		})
+	}
+	out := map[string]interface{}{"Count": len(items), "ScannedCount": len(items), "Items": items}
+	if page+1 < len(f.pages) || f.truncated {
+		out["LastEvaluatedKey"] = map[string]map[string]string{"PK": {"S": "USER#u1"}, "SK": {"S": strings.Repeat("#", page+1)}}
+	}
+	json.NewEncoder(w).Encode(out)
+}
+
+// newPager opens a pager over USER#u1 contacts served by table
+func newPager(t *testing.T, table http.Handler) *repository.QueryPager {
+	t.Helper()
+	srv := httptest.NewServer(table)
+	t.Cleanup(srv.Close)
+	repo := repository.NewGenericRepository(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(srv.URL),
+	}, "test-table")
+	pager, err := repo.QueryPages(context.Background(), "USER#u1", "CONTACT#")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pager
+}
+
+// streamRecorder is a ResponseRecorder that c.Stream can watch for the client going away
+type streamRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func newStreamRecorder() *streamRecorder {
+	return &streamRecorder{httptest.NewRecorder()}
+}
+
+func (r *streamRecorder) CloseNotify() <-chan bool {
+	return make(chan bool)
+}
+
+// ndjsonLines decodes each line of body as a JSON object
+func ndjsonLines(t *testing.T, body string) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %q is not a JSON object: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("one line per item across pages", func(t *testing.T) {
+		w := newStreamRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/?format=ndjson", nil)
+
+		streamNDJSON[*models.ContactEntity](c, newPager(t, &contactPages{pages: [][]string{{"c1", "c2"}, {"c3"}}}), []string{"id", "email"})
+
+		if got := w.Header().Get("Content-Type"); got != ndjsonContentType {
+			t.Errorf("Content-Type = %q, want %q", got, ndjsonContentType)
+		}
+		lines := ndjsonLines(t, w.Body.String())
+		if len(lines) != 3 {
+			t.Fatalf("got %d lines, want 3:\n%s", len(lines), w.Body.String())
+		}
+		for i, id := range []string{"c1", "c2", "c3"} {
+			if lines[i]["id"] != id || lines[i]["email"] != id+"@example.com" || len(lines[i]) != 2 {
+				t.Errorf("line %d = %v, want only id %s and its email", i, lines[i], id)
+			}
+		}
+	})
+
+	t.Run("failure mid-stream ends with an error line", func(t *testing.T) {
+		w := newStreamRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/?format=ndjson", nil)
+
+		streamNDJSON[*models.ContactEntity](c, newPager(t, &contactPages{pages: [][]string{{"c1"}}, truncated: true}), nil)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("status = %d, want 200 (sent before the failure)", w.Code)
+		}
+		lines := ndjsonLines(t, w.Body.String())
+		if len(lines) != 2 || lines[0]["id"] != "c1" || lines[1]["error"] == nil {
+			t.Errorf("lines = %v, want c1 then an error", lines)
+		}
+	})
+}
+
+func TestExportUserData_Ownership(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, tc := range []struct {
+		name   string
+		caller string
+		want   int
+	}{
+		{"anonymous", "", http.StatusUnauthorized},
+		{"another user", "u2", http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/users/u1/export", nil)
+			c.Params = gin.Params{{Key: "id", Value: "u1"}}
+			if tc.caller != "" {
+				c.Set(ContextUserIDKey, tc.caller)
+			}
+
+			// The service is never reached
+			NewAppHandler(nil).ExportUserData(c)
+
+			if w.Code != tc.want {
+				t.Errorf("status = %d, want %d", w.Code, tc.want)
+			}
+			if w.Header().Get("Content-Disposition") != "" {
+				t.Error("refused export still sent as a download")
+			}
+		})
+	}
+}
+
+func TestWriteUserExport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	export := &service.UserExport{
+		User:            models.NewUser("u1", "ada@example.com", "Ada", "Lovelace"),
+		Contacts:        newPager(t, &contactPages{pages: [][]string{{"c1"}, {"c2"}}}),
+		DeletedContacts: newPager(t, &contactPages{pages: [][]string{{}}}),
+	}
+	if err := writeUserExport(context.Background(), c.Writer, export); err != nil {
+		t.Fatalf("writeUserExport: %v", err)
+	}
+
+	var got struct {
+		User            *models.UserEntity      `json:"user"`
+		Contacts        []*models.ContactEntity `json:"contacts"`
+		DeletedContacts []*models.ContactEntity `json:"deleted_contacts"`
+		Audit           []*models.AuditEntry    `json:"audit"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("export is not one JSON object: %v\n%s", err, w.Body.String())
+	}
+	if got.User == nil || got.User.ID != "u1" {
+		t.Errorf("user = %+v, want u1", got.User)
+	}
+	if len(got.Contacts) != 2 || got.Contacts[0].ID != "c1" || got.Contacts[1].ID != "c2" {
+		t.Errorf("contacts = %v, want c1 and c2 from both pages", got.Contacts)
+	}
+	if got.DeletedContacts == nil || len(got.DeletedContacts) != 0 || got.Audit == nil {
+		t.Errorf("empty lists = %v / %v, want [] rather than null", got.DeletedContacts, got.Audit)
+	}
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// RegisterValidators registers the custom validation tags used by the
+// request DTOs with gin's binding engine. It must run before any handler
+// binds a request, otherwise binding fails with "undefined validation function".
+func RegisterValidators() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("unexpected binding engine %T", binding.Validator.Engine())
+	}
+
+	// userid: owner IDs are generated with uuid.New()
+	if err := v.RegisterValidation("userid", validateUserID); err != nil {
+		return fmt.Errorf("failed to register userid validator: %w", err)
+	}
+
+	return nil
+}
+
+// validateUserID checks that the field holds a UUID-shaped owner ID
+func validateUserID(fl validator.FieldLevel) bool {
+	_, err := uuid.Parse(fl.Field().String())
+	return err == nil
+}
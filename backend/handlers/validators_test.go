@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"hub-control-plane/backend/models"
+)
+
+func TestRegisterValidators_Binding(t *testing.T) {
+	if err := RegisterValidators(); err != nil {
+		t.Fatalf("RegisterValidators: %v", err)
+	}
+	const owner = "3f1c2a9e-8b4d-4e6f-9a1b-2c3d4e5f6a7b"
+
+	t.Run("valid create binds", func(t *testing.T) {
+		var req models.CreateContactRequest
+		body := `{"userid":"` + owner + `","name":"Ada","email":"ada@example.com","tags":["vip"],"is_favorite":true}`
+		if err := binding.JSON.BindBody([]byte(body), &req); err != nil {
+			t.Fatalf("bind: %v", err)
+		}
+		if req.UserID != owner || req.Name != "Ada" || req.Email != "ada@example.com" || !req.IsFavorite || len(req.Tags) != 1 {
+			t.Errorf("bound %+v", req)
+		}
+	})
+
+	t.Run("valid update binds", func(t *testing.T) {
+		var req models.UpdateContactRequest
+		body := `{"userid":"` + owner + `","name":"Ada","email":"ada@example.com"}`
+		if err := binding.JSON.BindBody([]byte(body), &req); err != nil {
+			t.Fatalf("bind: %v", err)
+		}
+		if req.UserID != owner || req.Name != "Ada" {
+			t.Errorf("bound %+v", req)
+		}
+	})
+
+	t.Run("owner id is optional", func(t *testing.T) {
+		var req models.CreateContactRequest
+		if err := binding.JSON.BindBody([]byte(`{"name":"Ada","email":"ada@example.com"}`), &req); err != nil {
+			t.Errorf("bind without userid: %v", err)
+		}
+	})
+
+	t.Run("malformed owner id is rejected", func(t *testing.T) {
+		var req models.CreateContactRequest
+		err := binding.JSON.BindBody([]byte(`{"userid":"u1","name":"Ada","email":"ada@example.com"}`), &req)
+		if err == nil || !strings.Contains(err.Error(), "userid") {
+			t.Errorf("bind = %v, want a userid validation error", err)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"net/http"
 	"os"
@@ -9,37 +10,97 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/gin-gonic/gin"
+	gql "github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/vektah/gqlparser/v2/ast"
 
 	// Local packages
 	"hub-control-plane/backend/config"
+	"hub-control-plane/backend/docs"
+	"hub-control-plane/backend/enrichment"
+	"hub-control-plane/backend/featureflag"
 	"hub-control-plane/backend/repository"
 	"hub-control-plane/backend/graphql"
 	"hub-control-plane/backend/graphql/resolvers"
 	"hub-control-plane/backend/service"
 	"hub-control-plane/backend/handlers"
+	"hub-control-plane/backend/pagination"
+	"hub-control-plane/backend/tracing"
+	"hub-control-plane/backend/webhook"
+)
+
+// Build information, stamped at link time:
+//
+//	go build -ldflags "-X main.Version=v2.1.0 -X main.Commit=$(git rev-parse --short HEAD) -X main.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// /health reports them so each environment shows exactly which build it runs.
+var (
+	Version   = "dev"
+	Commit    = "dev"
+	BuildDate = "dev"
 )
 
 func main() {
+	// --selftest checks the dependencies and exits instead of serving (e.g. as an init container)
+	selfTest := flag.Bool("selftest", false, "check config, DynamoDB and Redis, print a pass/fail table and exit non-zero on failure")
+	flag.Parse()
+
 	// Load configuration from environment variables
 	cfg := config.LoadConfig()
-	log.Printf("Starting server with config: Port=%s, Region=%s", cfg.Port, cfg.AWSRegion)
+	if *selfTest {
+		if !runSelfTest(context.Background(), os.Stdout, selfTestChecks(cfg)) {
+			os.Exit(1)
+		}
+		return
+	}
+	log.Printf("Starting server %s (commit %s, built %s) with config: Port=%s, Region=%s",
+		buildValue(Version), buildValue(Commit), buildValue(BuildDate), cfg.Port, cfg.AWSRegion)
 
 	// Initialize AWS SDK configuration
 	// This loads credentials from environment, IAM role, or AWS config files
-	awsConfig := config.NewAWSConfig(cfg.AWSRegion)
+	awsConfig, err := config.NewAWSConfig(cfg.AWSRegion)
+	if err != nil {
+		log.Fatalf("❌ Failed to load AWS config: %v", err)
+	}
+	log.Printf("✓ AWS config loaded (region: %s)", awsConfig.Region)
+
+	// OpenTelemetry tracing (no-op unless an OTLP endpoint is configured)
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.OTLPEndpoint, cfg.ServiceName)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize tracing: %v", err)
+	}
+	if cfg.OTLPEndpoint != "" {
+		log.Printf("✓ Tracing enabled (OTLP endpoint: %s)", cfg.OTLPEndpoint)
+	}
 	
 	// ==========================================
 	// REPOSITORY LAYER - Data Access
 	// ==========================================
 	
-	// Initialize User DynamoDB Repository
-	// This creates a concrete implementation of UserRepository interface
+	// Initialize the single-table DynamoDB repository
+	// This creates the SingleTableRepository every entity type goes through
 	// Pattern: NewXxxRepository(dependencies...) returns *XxxRepository
 	repo := repository.NewGenericRepository(awsConfig, cfg.DynamoDBTableName)
+	repo.EnableConsumedCapacity(cfg.DynamoDBConsumedCapacity)
+	repo.SetSlowQueryThreshold(time.Duration(cfg.DynamoDBSlowQueryMs) * time.Millisecond)
+	repo.EnableAttributeValidation(cfg.DynamoDBValidateAttrs)
+	repo.EnableConflictLogging(cfg.DynamoDBLogConflicts)
+	repo.EnableItemSizeMetrics(cfg.DynamoDBItemSizeMetrics)
+	repo.EnableTenantTables(cfg.TenantTables)
+	if cfg.KMSKeyID != "" {
+		repo.SetFieldEncryptor(repository.NewFieldEncryptor(kms.NewFromConfig(awsConfig), cfg.KMSKeyID, cfg.EncryptedFields...))
+		log.Printf("✓ Field encryption enabled for %v", cfg.EncryptedFields)
+	}
 	log.Printf("✓ DynamoDB generic repository initialized (table: %s)", cfg.DynamoDBTableName)
+	if cfg.TenantTables {
+		log.Printf("✓ Tenant tables enabled (<tenant>-%s)", cfg.DynamoDBTableName)
+	}
 	
 	// ==========================================
 	// CACHE LAYER - Performance Optimization
@@ -47,9 +108,25 @@ func main() {
 	
 	// Initialize Redis Cache for Users
 	// This creates a Redis client and wraps it with user-specific cache methods
-	cache := repository.NewRedisCache(cfg.RedisAddress, cfg.RedisPassword)
-	log.Printf("✓ User Redis cache initialized (address: %s)", cfg.RedisAddress)
+	cache, err := repository.NewRedisCache(redisOptions(cfg))
+	if err != nil {
+		log.Fatalf("❌ Failed to configure Redis: %v", err)
+	}
+	log.Printf("✓ User Redis cache initialized (mode: %s, addresses: %v)", cfg.RedisMode, cfg.RedisNodes())
+
+	// Optionally wait for Redis, which may still be starting during a coordinated deploy
+	if cfg.RedisStartupAttempts > 0 {
+		if err := cache.WaitUntilReachable(context.Background(), cfg.RedisStartupAttempts, cfg.RedisStartupBackoff); err != nil {
+			if !cfg.RedisStartupDegraded {
+				log.Fatalf("❌ %v", err)
+			}
+			log.Printf("Warning: %v; starting in degraded mode (reads fall back to DynamoDB; sessions need Redis)", err)
+		} else {
+			log.Printf("✓ Redis reachable")
+		}
+	}
 	redisClient := cache.GetClient() 
+	repository.InstrumentTracing(redisClient)
 	
 	// ==========================================
 	// SERVICE LAYER - Business Logic
@@ -58,13 +135,104 @@ func main() {
 	// Initialize User Service
 	// Dependency Injection: Pass in both repository and cache
 	// The service coordinates between cache and database
-	appService := service.NewAppServiceWithCache(repo, redisClient)
+	appService := service.NewAppServiceWithCache(repo, repository.NewRedisAdapter(redisClient))
+	appService.SetCacheLimits(cfg.CacheMaxListItems, cfg.CacheMaxListBytes)
+	appService.SetCacheTTLs(service.CacheTTLs{
+		User:      cfg.UserCacheTTL,
+		List:      cfg.ListCacheTTL,
+		Dashboard: cfg.DashboardCacheTTL,
+	})
+	appService.SetStaleWhileRevalidate(cfg.CacheStaleWindow)
+	appService.SetSlidingExpiry(cfg.CacheSlidingExpiry)
+	for entity, value := range map[string]string{
+		service.EntityUser:    cfg.CacheStrategyUser,
+		service.EntityContact: cfg.CacheStrategyContact,
+	} {
+		strategy, err := service.ParseCacheStrategy(value)
+		if err != nil {
+			log.Fatalf("❌ Invalid cache strategy for %s: %v", entity, err)
+		}
+		appService.SetCacheStrategy(entity, strategy)
+	}
+	cacheCodec, err := service.ParseCacheCodec(cfg.CacheCodec)
+	if err != nil {
+		log.Fatalf("❌ Invalid CACHE_CODEC: %v", err)
+	}
+	appService.SetCacheCodec(cacheCodec)
+	appService.SetReadRepair(cfg.CacheReadRepair)
+	appService.SetTrashRetention(cfg.TrashRetention)
+	appService.SetMaxTransactionItems(cfg.TxMaxItems)
+	appService.SetContactEmailDomains(cfg.ContactEmailDomains)
+	appService.SetCloneKeepsFavorite(cfg.CloneKeepsFavorite)
+	appService.SetContactListIndex(cfg.ContactListIndex)
+
+	// Feature flags: FEATURE_FLAGS defaults, overridden at runtime by the feature_flags Redis hash
+	flagDefaults, err := featureflag.ParseDefaults(service.DefaultFlags, cfg.FeatureFlags)
+	if err != nil {
+		log.Fatalf("❌ Invalid FEATURE_FLAGS: %v", err)
+	}
+	flags := featureflag.New(flagDefaults)
+	if err := flags.Refresh(context.Background(), cache.FeatureFlagOverrides); err != nil {
+		log.Printf("Warning: %v; using FEATURE_FLAGS defaults", err)
+	}
+	appService.SetFlags(flags)
+	if cfg.AvatarBucket != "" {
+		appService.SetAvatarUploads(repository.NewObjectUploads(awsConfig, cfg.AvatarBucket, cfg.AvatarUploadTTL), cfg.AvatarMaxBytes)
+		log.Printf("✓ Contact avatar uploads enabled (bucket: %s)", cfg.AvatarBucket)
+	}
 	log.Printf("✓ App service initialized")
+
+	// Background purge of expired trash; replicas coordinate through a Redis lock
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	if cfg.TrashPurgeInterval > 0 {
+		go appService.RunTrashPurge(purgeCtx, repository.NewRedisLocker(redisClient), cfg.TrashPurgeInterval)
+		log.Printf("✓ Trash purge scheduled every %s (retention %s)", cfg.TrashPurgeInterval, cfg.TrashRetention)
+	}
+	// Pick up feature flag overrides without a restart; stops with the purge job at shutdown
+	if cfg.FeatureFlagsRefresh > 0 {
+		go flags.Watch(purgeCtx, cache.FeatureFlagOverrides, cfg.FeatureFlagsRefresh)
+	}
+
+	// Contact lifecycle webhooks (only when endpoints are configured)
+	var webhooks *webhook.Dispatcher
+	if len(cfg.WebhookURLs) > 0 {
+		if cfg.WebhookSecret == "" {
+			log.Printf("Warning: WEBHOOK_SECRET is empty, webhook signatures are not meaningful")
+		}
+		webhooks = webhook.NewDispatcher(cfg.WebhookURLs, cfg.WebhookSecret)
+		appService.SetWebhooks(webhooks)
+		log.Printf("✓ Webhooks enabled (%d endpoints)", len(cfg.WebhookURLs))
+	}
+
+	// Contact enrichment (only when a provider is configured)
+	if cfg.EnrichmentURL != "" {
+		appService.SetEnricher(enrichment.NewHTTPEnricher(cfg.EnrichmentURL, cfg.EnrichmentAPIKey), cfg.EnrichmentWorkers, cfg.EnrichmentRate)
+		log.Printf("✓ Contact enrichment enabled (%d workers, %d/s)", cfg.EnrichmentWorkers, cfg.EnrichmentRate)
+	}
 	
+	// Sign pagination cursors so clients can't forge keys into other users' data
+	if cfg.CursorSecret == "" {
+		log.Printf("Warning: PAGINATION_CURSOR_SECRET is empty, cursors are signed with a per-process key and break across restarts and instances")
+	}
+	pagination.SetSecret(cfg.CursorSecret)
+	pagination.SetMaxPageSize(cfg.MaxPageSize)
+
+	// Register custom binding validators used by the request DTOs
+	if err := handlers.RegisterValidators(); err != nil {
+		log.Fatalf("❌ Failed to register validators: %v", err)
+	}
+
 	// Create app handler for REST API
 	appHandler := handlers.NewAppHandler(appService)
 	log.Printf("✓ App handler initialized")
 
+	// Revocable sessions for REST auth (logout, log out everywhere)
+	sessions := repository.NewSessionStore(redisClient, time.Duration(cfg.SessionTTL)*time.Second)
+	authHandler := handlers.NewAuthHandler(sessions)
+
+	// Track "last active" for authenticated users, at most one write per user per hour
+	activityTracker := handlers.NewActivityTracker(appService, time.Hour)
+
 	// ==========================================
 	// GRAPHQL SETUP
 	// ==========================================
@@ -73,20 +241,21 @@ func main() {
 	gqlResolver := resolvers.NewResolver(appService)
 	log.Printf("✓ GraphQL resolver initialized")
 	
-	// Create GraphQL server
-	gqlServer := handler.NewDefaultServer(
+	// Create GraphQL server; persisted queries live in Redis so any replica can resolve a hash
+	gqlServer := newGraphQLServer(
 		graphql.NewExecutableSchema(
 			graphql.Config{Resolvers: gqlResolver},
 		),
+		repository.NewAPQCache(redisClient, cfg.GraphQLAPQTTL),
 	)
-	log.Printf("✓ GraphQL server initialized")
+	log.Printf("✓ GraphQL server initialized (persisted queries kept %s)", cfg.GraphQLAPQTTL)
 
 	// ==========================================
 	// HTTP SERVER SETUP
 	// ==========================================
 	
 	// Setup router with all handlers
-	router := setupRouter(appHandler, gqlServer)
+	router := setupRouter(appHandler, activityTracker, gqlServer, authHandler, sessions, cfg.AdminToken)
 	log.Printf("✓ Router configured")
 
 	// Create HTTP server with configured handler
@@ -130,26 +299,97 @@ func main() {
 		log.Fatal("❌ Server forced to shutdown:", err)
 	}
 
+	// Stop scheduling trash purges
+	stopPurge()
+
+	// Let in-flight webhook deliveries and queued enrichments finish
+	webhooks.Wait()
+	appService.StopEnrichment()
+
+	// Stop the repository's background work, then drop the Redis connections
+	if err := repo.Close(ctx); err != nil {
+		log.Printf("Warning: failed to close repository: %v", err)
+	}
+	if err := cache.Close(); err != nil {
+		log.Printf("Warning: failed to close Redis cache: %v", err)
+	}
+
+	// Flush buffered spans
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("Warning: failed to flush traces: %v", err)
+	}
+
 	log.Println("✅ Server exited gracefully")
 }
 
+// newGraphQLServer is gqlgen's default server (transports, query cache, introspection)
+// with automatic persisted queries stored in apqCache: clients send a query's SHA-256,
+// and register the text once when the server answers PERSISTED_QUERY_NOT_FOUND.
+func newGraphQLServer(schema gql.ExecutableSchema, apqCache gql.Cache[string]) *handler.Server {
+	srv := handler.New(schema)
+
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{Cache: apqCache})
+
+	srv.SetErrorPresenter(resolvers.ErrorPresenter)
+	return srv
+}
+
+// buildValue is a link-time build variable, or "dev" when it was stamped empty
+func buildValue(v string) string {
+	if v == "" {
+		return "dev"
+	}
+	return v
+}
+
+// redisOptions maps the REDIS_* settings onto the Redis client options
+func redisOptions(cfg *config.Config) repository.RedisOptions {
+	return repository.RedisOptions{
+		Mode:       cfg.RedisMode,
+		Addresses:  cfg.RedisNodes(),
+		MasterName: cfg.RedisMasterName,
+		Password:   cfg.RedisPassword,
+	}
+}
+
 // setupRouter configures all HTTP routes and middleware
 func setupRouter(
     appHandler *handlers.AppHandler,
+    activityTracker *handlers.ActivityTracker,
     gqlServer *handler.Server,
+    authHandler *handlers.AuthHandler,
+    sessions *repository.SessionStore,
+    adminToken string,
 ) *gin.Engine {
     router := gin.Default()
+    router.Use(handlers.Tracing())
+    router.Use(handlers.RequestID())
+    router.Use(handlers.CacheOnly())
+    router.Use(handlers.TrackReads())
 
     // ==========================================
     // HEALTH CHECK ENDPOINT
     // ==========================================
     router.GET("/health", func(c *gin.Context) {
         c.JSON(http.StatusOK, gin.H{
-            "status":    "healthy",
-            "timestamp": time.Now().UTC(),
-            "service":   "hub-control-plane",
-            "version":   "2.0.0",
-            "apis":      []string{"REST", "GraphQL"},
+            "status":     "healthy",
+            "timestamp":  time.Now().UTC(),
+            "service":    "hub-control-plane",
+            "version":    buildValue(Version),
+            "commit":     buildValue(Commit),
+            "build_date": buildValue(BuildDate),
+            "apis":       []string{"REST", "GraphQL"},
         })
     })
 
@@ -158,37 +398,87 @@ func setupRouter(
     // ==========================================
     
     // GraphQL API endpoint
-    router.POST("/graphql", gin.WrapH(gqlServer))
-    router.GET("/graphql", gin.WrapH(gqlServer))
+    // Admin mutations check for the admin token themselves
+    router.POST("/graphql", handlers.MarkAdmin(adminToken), gin.WrapH(gqlServer))
+    router.GET("/graphql", handlers.MarkAdmin(adminToken), gin.WrapH(gqlServer))
     
     // GraphQL Playground (development tool)
     router.GET("/playground", gin.WrapH(playground.Handler("GraphQL Playground", "/graphql")))
 
+    // ==========================================
+    // API CONTRACT
+    // ==========================================
+
+    // OpenAPI 3 spec for the REST routes (kept in sync by main_test.go)
+    router.GET("/openapi.json", func(c *gin.Context) {
+        c.JSON(http.StatusOK, docs.OpenAPI())
+    })
+
     // ==========================================
     // REST API ENDPOINTS (v1)
     // ==========================================
     v1 := router.Group("/api/v1")
+    v1.Use(handlers.Authenticate(sessions), activityTracker.Middleware())
     {
+        // Only routes that bind a JSON body require one; clone and restore take none
+        requireJSON := handlers.RequireJSON()
+
+        // Session routes - act on the session the request authenticated with
+        auth := v1.Group("/auth")
+        {
+            auth.DELETE("/session", authHandler.Logout)
+            auth.DELETE("/sessions", authHandler.LogoutEverywhere)
+        }
+
         // User routes
         users := v1.Group("/users")
         {
-            users.POST("", appHandler.CreateUser)
+            users.POST("", requireJSON, appHandler.CreateUser)
+            users.POST("/bulk", requireJSON, appHandler.CreateUsers)
 			users.GET("", appHandler.ListUsers)
+            users.GET("/by-created", appHandler.ListUsersByCreatedDate)
+            users.GET("/by-email", appHandler.GetUserByEmail)
             users.GET("/:id", appHandler.GetUser)
-            users.PUT("/:id", appHandler.UpdateUser)
+            users.PUT("/:id", requireJSON, appHandler.UpdateUser)
             users.DELETE("/:id", appHandler.DeleteUser)
+            users.GET("/:id/export", appHandler.ExportUserData)
         }
         
         // Contact routes - using :id for userId to keep RESTful
         userContacts := v1.Group("/users/:id")
         {
-			userContacts.POST("/contacts", appHandler.CreateContact)
+			userContacts.POST("/contacts", requireJSON, appHandler.CreateContact)
+			userContacts.POST("/contacts/import", requireJSON, appHandler.ImportContacts)
+			userContacts.POST("/contacts/bulk-update", requireJSON, appHandler.BulkUpdateContacts)
+			userContacts.POST("/tags/rename", requireJSON, appHandler.RenameTag)
+			userContacts.POST("/tags/remove", requireJSON, appHandler.RemoveTag)
+			userContacts.POST("/contacts/batch-get", requireJSON, appHandler.GetContactsByIDs)
 			userContacts.GET("/contacts", appHandler.ListUserContacts)
 			userContacts.GET("/contacts/favorites", appHandler.ListFavoriteContacts)
+			userContacts.GET("/contacts/trash", appHandler.ListDeletedContacts)
+			userContacts.GET("/contacts/changes", appHandler.ListContactChanges)
+			userContacts.GET("/contacts/by-email", appHandler.GetContactByEmail)
+			userContacts.GET("/contacts/search", appHandler.SearchContacts)
 			userContacts.GET("/contacts/:contactId", appHandler.GetContact)
-			userContacts.PUT("/contacts/:contactId", appHandler.UpdateContact)
+			userContacts.PUT("/contacts/:contactId", requireJSON, appHandler.UpdateContact)
 			userContacts.DELETE("/contacts/:contactId", appHandler.DeleteContact)
+			userContacts.POST("/contacts/:contactId/transfer", requireJSON, appHandler.TransferContact)
+			userContacts.POST("/contacts/:contactId/restore", appHandler.RestoreContact)
+			userContacts.POST("/contacts/:contactId/clone", appHandler.CloneContact)
+			userContacts.POST("/contacts/:contactId/avatar", requireJSON, appHandler.PresignContactAvatar)
         }
+
+    }
+
+    // Admin routes - operational tooling, guarded by the admin bearer token.
+    // Registered outside v1 so its Authorization header isn't read as a session token.
+    admin := router.Group("/api/v1/admin", handlers.RequireAdminToken(adminToken))
+    {
+        admin.POST("/cache/flush", appHandler.FlushCache)
+        admin.POST("/reindex", appHandler.Reindex)
+        admin.GET("/audit", appHandler.ListAuditEntries)
+        admin.GET("/contacts", appHandler.ListAllContacts)
+        admin.POST("/users/:id/sessions", authHandler.CreateSession)
     }
 
     return router
@@ -209,46 +499,49 @@ BENEFITS:
   3. Clarity: Dependencies are explicit, not hidden
   4. No Magic: No reflection or runtime discovery
 
-EXAMPLE INITIALIZATION CHAIN FOR USER:
+INITIALIZATION CHAIN:
 
-  1. awsConfig = config.NewAWSConfig(region)
+  1. awsConfig, err = config.NewAWSConfig(region)
      └─> Creates AWS SDK configuration
   
-  2. userRepo = repository.NewDynamoDBRepository(awsConfig, tableName)
+  2. repo = repository.NewGenericRepository(awsConfig, tableName)
      └─> Creates DynamoDB client
-     └─> Implements UserRepository interface
+     └─> Implements SingleTableRepository for every entity type
   
-  3. userCache = repository.NewRedisCache(address, password)
-     └─> Creates Redis client
-     └─> Implements UserCache interface
+  3. cache, err = repository.NewRedisCache(redisOptions(cfg))
+     └─> Creates a single-node, cluster or sentinel Redis client
+     └─> Wrapped by repository.NewRedisAdapter to implement Cache
   
-  4. userService = service.NewUserService(userRepo, userCache)
+  4. appService = service.NewAppServiceWithCache(repo, cache)
      └─> Receives both repository and cache
-     └─> Implements business logic
+     └─> Implements business logic for users and contacts
      └─> Coordinates cache-aside pattern
   
-  5. userHandler = handlers.NewUserHandler(userService)
+  5. appHandler = handlers.NewAppHandler(appService)
      └─> Receives service
      └─> Handles HTTP requests/responses
      └─> Validates input
      └─> Returns JSON
 
+  There is a single service for all entities; there are no per-entity
+  UserService/ContactService types to wire up.
+
 USAGE IN MAIN:
 
-  // Create repositories (data access layer)
-  userRepo := repository.NewDynamoDBRepository(awsConfig, cfg.DynamoDBTableName)
+  // Create repository (data access layer)
+  repo := repository.NewGenericRepository(awsConfig, cfg.DynamoDBTableName)
   
   // Create cache layer
-  userCache := repository.NewRedisCache(cfg.RedisAddress, cfg.RedisPassword)
+  cache, err := repository.NewRedisCache(redisOptions(cfg))
   
   // Create service (business logic) - inject dependencies
-  userService := service.NewUserService(userRepo, userCache)
+  appService := service.NewAppServiceWithCache(repo, repository.NewRedisAdapter(cache.GetClient()))
   
   // Create handler (HTTP layer) - inject service
-  userHandler := handlers.NewUserHandler(userService)
+  appHandler := handlers.NewAppHandler(appService)
   
   // Setup routes - inject handler
-  router := setupRouter(userHandler, contactHandler)
+  router := setupRouter(appHandler, activityTracker, gqlServer, cfg.AdminToken)
 
 ALTERNATIVE APPROACHES:
 
@@ -0,0 +1,421 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	gql "github.com/99designs/gqlgen/graphql"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"hub-control-plane/backend/docs"
+	"hub-control-plane/backend/graphql"
+	"hub-control-plane/backend/graphql/resolvers"
+	"hub-control-plane/backend/handlers"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/service"
+	"hub-control-plane/backend/tracing"
+)
+
+// undocumentedRoutes are served by setupRouter but aren't part of the REST contract
+var undocumentedRoutes = map[string]bool{
+	"GET /graphql":      true,
+	"POST /graphql":     true,
+	"GET /playground":   true,
+	"GET /openapi.json": true,
+}
+
+var ginParam = regexp.MustCompile(`:(\w+)`)
+
+// TestOpenAPIMatchesRoutes fails when a route is added without documenting it,
+// or the spec describes a route that no longer exists
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := setupRouter(
+		handlers.NewAppHandler(nil),
+		handlers.NewActivityTracker(nil, time.Hour),
+		nil,
+		handlers.NewAuthHandler(nil),
+		nil,
+		"",
+	)
+
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		key := route.Method + " " + ginParam.ReplaceAllString(route.Path, "{$1}")
+		if !undocumentedRoutes[key] {
+			registered[key] = true
+		}
+	}
+
+	documented := make(map[string]bool)
+	for path, item := range docs.OpenAPI().Paths {
+		for method := range item {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	for _, key := range sortedKeys(registered) {
+		if !documented[key] {
+			t.Errorf("route %s is registered but missing from the OpenAPI spec", key)
+		}
+	}
+	for _, key := range sortedKeys(documented) {
+		if !registered[key] {
+			t.Errorf("OpenAPI spec documents %s but no such route is registered", key)
+		}
+	}
+}
+
+func TestOpenAPIEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := setupRouter(handlers.NewAppHandler(nil), handlers.NewActivityTracker(nil, time.Hour), nil, handlers.NewAuthHandler(nil), nil, "")
+
+	req, err := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /openapi.json = %d, want 200", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"openapi":"3.0.3"`) {
+		t.Errorf("response is not an OpenAPI 3 document: %.200s", rec.Body.String())
+	}
+}
+
+// TestFieldsRejectsUnknownNames checks ?fields= is validated before the service is called
+func TestFieldsRejectsUnknownNames(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := setupRouter(handlers.NewAppHandler(nil), handlers.NewActivityTracker(nil, time.Hour), nil, handlers.NewAuthHandler(nil), nil, "")
+
+	for _, path := range []string{
+		"/api/v1/users/u1?fields=id,bogus",
+		"/api/v1/users/u1/contacts/c1?fields=first_name",
+		"/api/v1/users/u1/contacts?fields=,",
+	} {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s = %d, want 400", path, rec.Code)
+		}
+	}
+}
+
+// TestListUsersRejectsUnknownSort checks ?sort= and ?order= are validated before the service is called
+func TestListUsersRejectsUnknownSort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := setupRouter(handlers.NewAppHandler(nil), handlers.NewActivityTracker(nil, time.Hour), nil, handlers.NewAuthHandler(nil), nil, "")
+
+	for _, query := range []string{"sort=id", "sort=email&order=up", "sort=name&format=ndjson"} {
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/users?"+query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("GET /api/v1/users?%s = %d, want 400", query, rec.Code)
+		}
+	}
+}
+
+// TestRequireJSONOnBodyRoutes checks routes that bind a body still reject non-JSON ones
+func TestRequireJSONOnBodyRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := setupRouter(handlers.NewAppHandler(nil), handlers.NewActivityTracker(nil, time.Hour), nil, handlers.NewAuthHandler(nil), nil, "")
+
+	for _, tc := range []struct {
+		contentType, body string
+		want              int
+	}{
+		{"text/plain", "name=A", http.StatusUnsupportedMediaType},
+		{"application/json", "", http.StatusBadRequest},
+	} {
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/users/u1/contacts", strings.NewReader(tc.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", tc.contentType)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != tc.want {
+			t.Errorf("POST with %s body %q = %d, want %d", tc.contentType, tc.body, rec.Code, tc.want)
+		}
+	}
+}
+
+// recordingRepo fails every read or write it implements, recording the partition key it
+// was asked for, so a test can see which user a route resolved to without a table
+type recordingRepo struct {
+	repository.SingleTableRepository
+	pks []string
+}
+
+var errRecorded = errors.New("recorded")
+
+func (r *recordingRepo) Get(ctx context.Context, pk, sk string, result repository.BaseModel) error {
+	r.pks = append(r.pks, pk)
+	return errRecorded
+}
+
+func (r *recordingRepo) Query(ctx context.Context, pk string, skPrefix string, resultSlice interface{}) error {
+	r.pks = append(r.pks, pk)
+	return errRecorded
+}
+
+func (r *recordingRepo) QueryItems(ctx context.Context, pk string, skPrefix string) ([]map[string]types.AttributeValue, error) {
+	r.pks = append(r.pks, pk)
+	return nil, errRecorded
+}
+
+func (r *recordingRepo) QueryWithStats(ctx context.Context, pk string, skPrefix string, filter *expression.ConditionBuilder) ([]map[string]types.AttributeValue, repository.QueryStats, error) {
+	r.pks = append(r.pks, pk)
+	return nil, repository.QueryStats{}, errRecorded
+}
+
+func (r *recordingRepo) Update(ctx context.Context, pk, sk string, updates map[string]interface{}) error {
+	r.pks = append(r.pks, pk)
+	return errRecorded
+}
+
+func (r *recordingRepo) TransactUpdate(ctx context.Context, keys []map[string]string, updates map[string]interface{}) error {
+	for _, key := range keys {
+		r.pks = append(r.pks, key["PK"])
+	}
+	return errRecorded
+}
+
+// missCache misses every read, so the service always goes to the repository
+type missCache struct {
+	repository.Cache
+}
+
+func (missCache) Get(ctx context.Context, key string) ([]byte, error) {
+	return nil, repository.ErrCacheMiss
+}
+
+// TestContactRoutesReadUserID checks contact routes under /users/:id reach the service
+// with the user from the path
+func TestContactRoutesReadUserID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, tc := range []struct {
+		method, path, body string
+	}{
+		{http.MethodPost, "/api/v1/users/u1/contacts", `{"name":"A","email":"a@example.com"}`},
+		{http.MethodGet, "/api/v1/users/u1/contacts", ""},
+		{http.MethodGet, "/api/v1/users/u1/contacts/favorites", ""},
+		{http.MethodGet, "/api/v1/users/u1/contacts/c1", ""},
+		{http.MethodPut, "/api/v1/users/u1/contacts/c1", `{"name":"A"}`},
+		{http.MethodDelete, "/api/v1/users/u1/contacts/c1", ""},
+		{http.MethodPost, "/api/v1/users/u1/contacts/import", `{"contacts":[{"name":"A","email":"a@example.com"}]}`},
+		{http.MethodPost, "/api/v1/users/u1/contacts/bulk-update", `{"ids":["c1","c2"],"updates":{"Company":"Acme"}}`},
+		{http.MethodGet, "/api/v1/users/u1/contacts/by-email?email=a@example.com", ""},
+		{http.MethodPost, "/api/v1/users/u1/contacts/c1/clone", ""},
+		{http.MethodPost, "/api/v1/users/u1/contacts/c1/restore", ""},
+	} {
+		repo := &recordingRepo{}
+		appService := service.NewAppServiceWithCache(repo, missCache{})
+		router := setupRouter(handlers.NewAppHandler(appService), handlers.NewActivityTracker(nil, time.Hour), nil, handlers.NewAuthHandler(nil), nil, "")
+
+		req, err := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if len(repo.pks) == 0 {
+			t.Errorf("%s %s = %d without reaching the repository: %s", tc.method, tc.path, rec.Code, rec.Body.String())
+			continue
+		}
+		if repo.pks[0] != "USER#u1" {
+			t.Errorf("%s %s read partition %q, want USER#u1", tc.method, tc.path, repo.pks[0])
+		}
+	}
+}
+
+// emptyTable is a DynamoDB endpoint that finds nothing: every Query returns no items
+type emptyTable struct{}
+
+func (emptyTable) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	w.Write([]byte(`{"Count":0,"ScannedCount":0,"Items":[]}`))
+}
+
+// TestDeleteUserNotFound checks deleting a missing user is a 404, with or without cascade
+func TestDeleteUserNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	srv := httptest.NewServer(emptyTable{})
+	t.Cleanup(srv.Close)
+	repo := repository.NewGenericRepository(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(srv.URL),
+	}, "test-table")
+	appService := service.NewAppServiceWithCache(repo, missCache{})
+	router := setupRouter(handlers.NewAppHandler(appService), handlers.NewActivityTracker(nil, time.Hour), nil, handlers.NewAuthHandler(nil), nil, "")
+
+	for _, path := range []string{"/api/v1/users/missing", "/api/v1/users/missing?cascade=true"} {
+		req, err := http.NewRequest(http.MethodDelete, path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("DELETE %s = %d, want 404: %s", path, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+// TestHealthReportsBuildInfo checks /health reports the version, commit and build date
+func TestHealthReportsBuildInfo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	previous := Commit
+	Commit = "abc1234"
+	t.Cleanup(func() { Commit = previous })
+
+	router := setupRouter(handlers.NewAppHandler(nil), handlers.NewActivityTracker(nil, time.Hour), nil, handlers.NewAuthHandler(nil), nil, "")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode /health: %v", err)
+	}
+	want := map[string]string{"version": "dev", "commit": "abc1234", "build_date": "dev"}
+	for field, value := range want {
+		if body[field] != value {
+			t.Errorf("%s = %v, want %q", field, body[field], value)
+		}
+	}
+}
+
+// TestTracingContinuesTraceparent checks the request span joins the caller's trace
+func TestTracingContinuesTraceparent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	if _, err := tracing.Init(context.Background(), "", "test"); err != nil {
+		t.Fatal(err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	router := setupRouter(handlers.NewAppHandler(nil), handlers.NewActivityTracker(nil, time.Hour), nil, handlers.NewAuthHandler(nil), nil, "")
+
+	req, err := http.NewRequest(http.MethodGet, "/health", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET /health" {
+		t.Errorf("span name = %q, want %q", span.Name(), "GET /health")
+	}
+	if got := span.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %s, want the one from traceparent", got)
+	}
+	if got := span.Parent().SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("parent span ID = %s, want the one from traceparent", got)
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestGraphQLPersistedQueries(t *testing.T) {
+	cache := gql.MapCache[string]{}
+	srv := newGraphQLServer(graphql.NewExecutableSchema(graphql.Config{Resolvers: resolvers.NewResolver(nil)}), cache)
+
+	query := "{ __typename }"
+	sum := sha256.Sum256([]byte(query))
+	hash := hex.EncodeToString(sum[:])
+	extensions := fmt.Sprintf(`"extensions":{"persistedQuery":{"version":1,"sha256Hash":%q}}`, hash)
+
+	post := func(body string) string {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+
+	if got := post("{" + extensions + "}"); !strings.Contains(got, "PersistedQueryNotFound") {
+		t.Fatalf("unknown hash: got %s, want PersistedQueryNotFound", got)
+	}
+	if got := post(fmt.Sprintf(`{"query":%q,%s}`, query, extensions)); !strings.Contains(got, `"__typename":"Query"`) {
+		t.Fatalf("registering query: got %s", got)
+	}
+	if cache[hash] != query {
+		t.Fatalf("cache[%s] = %q, want the registered query", hash, cache[hash])
+	}
+	if got := post("{" + extensions + "}"); !strings.Contains(got, `"__typename":"Query"`) {
+		t.Errorf("hash only after registering: got %s", got)
+	}
+}
+
+func TestRunSelfTest(t *testing.T) {
+	var out bytes.Buffer
+	passed := runSelfTest(context.Background(), &out, []selfTestCheck{
+		{"config", func(ctx context.Context) error { return nil }},
+		{"redis", func(ctx context.Context) error { return errors.New("connection refused") }},
+	})
+	if passed {
+		t.Error("runSelfTest passed with a failing check")
+	}
+
+	report := out.String()
+	for _, want := range []string{"CHECK", "config  PASS", "redis   FAIL    connection refused"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+
+	if !runSelfTest(context.Background(), &out, []selfTestCheck{{"config", func(ctx context.Context) error { return nil }}}) {
+		t.Error("runSelfTest failed with only passing checks")
+	}
+}
@@ -2,7 +2,10 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
+
+	"hub-control-plane/backend/clock"
 )
 
 // ============================================================================
@@ -37,7 +40,7 @@ func (e *DynamoDBEntity) GetEntityType() string { return e.EntityType }
 
 // SetTimestamps sets created/updated timestamps
 func (e *DynamoDBEntity) SetTimestamps() {
-	now := time.Now().UTC()
+	now := clock.Now()
 	if e.CreatedAt.IsZero() {
 		e.CreatedAt = now
 	}
@@ -49,14 +52,21 @@ func (e *DynamoDBEntity) SetTimestamps() {
 // ============================================================================
 
 type UserEntity struct {
-	DynamoDBEntity              // Embedded base entity
-	ID             string       `json:"id" dynamodbav:"ID"`
-	Email          string       `json:"email" dynamodbav:"Email"`
-	FirstName      string       `json:"first_name" dynamodbav:"FirstName"`
-	LastName       string       `json:"last_name" dynamodbav:"LastName"`
+	DynamoDBEntity                 // Embedded base entity
+	ID                  string     `json:"id" dynamodbav:"ID"`
+	Email               string     `json:"email" dynamodbav:"Email"`
+	FirstName           string     `json:"first_name" dynamodbav:"FirstName"`
+	LastName            string     `json:"last_name" dynamodbav:"LastName"`
+	LastActiveAt        *time.Time `json:"last_active_at,omitempty" dynamodbav:"LastActiveAt,omitempty"`               // Set by TouchUser, throttled
+	ContactCount        int        `json:"contact_count" dynamodbav:"ContactCount,omitempty"`                          // Live contacts; kept in step by the contact write transactions
+	AllowedEmailDomains []string   `json:"allowed_email_domains,omitempty" dynamodbav:"AllowedEmailDomains,omitempty"` // Contact email domains this user may store (empty = global default)
 }
 
+// IsSearchResult makes UserEntity a member of the GraphQL SearchResult union
+func (UserEntity) IsSearchResult() {}
+
 // NewUser creates a new user with proper keys
+// CreatedAt is fixed here rather than at put time because it is part of GSI1SK.
 func NewUser(id, email, firstName, lastName string) *UserEntity {
 	user := &UserEntity{
 		ID:        id,
@@ -64,34 +74,87 @@ func NewUser(id, email, firstName, lastName string) *UserEntity {
 		FirstName: firstName,
 		LastName:  lastName,
 	}
+	user.CreatedAt = clock.Now()
 	
 	// Set single-table design keys
 	user.PK = fmt.Sprintf("USER#%s", id)
 	user.SK = "METADATA"
 	user.GSI1PK = "USER"
-	user.GSI1SK = fmt.Sprintf("USER#%s", id)
+	user.GSI1SK = UserGSI1SK(id, user.CreatedAt)
 	user.EntityType = "USER"
 	
 	return user
 }
 
+// userCreatedLayout is a fixed-width UTC timestamp, so GSI1SK strings sort in time order
+// (RFC3339Nano drops trailing zeros, which breaks lexicographic ordering)
+const userCreatedLayout = "2006-01-02T15:04:05.000000000Z"
+
+// UserGSI1SK is a user's GSI1 sort key: <created>#USER#<id>, so a GSI1 query on
+// GSI1PK=USER returns users ordered by creation time
+func UserGSI1SK(id string, createdAt time.Time) string {
+	return fmt.Sprintf("%s#USER#%s", createdAt.UTC().Format(userCreatedLayout), id)
+}
+
+// UserCreatedRange returns the GSI1SK bounds for users created between from and to
+// (both inclusive), for use with BETWEEN. The upper bound is the timestamp one
+// nanosecond after to, which sorts after every key created at to.
+func UserCreatedRange(from, to time.Time) (lower, upper string) {
+	return from.UTC().Format(userCreatedLayout), to.Add(time.Nanosecond).UTC().Format(userCreatedLayout)
+}
+
+// UserEmailIndex is the sentinel item that reserves an email for one user. It is written
+// in the same transaction as the user with attribute_not_exists(PK), so two users can't
+// end up with the same email, and it maps the email back to the user with one Get.
+type UserEmailIndex struct {
+	DynamoDBEntity        // Embedded base entity
+	UserID         string `json:"user_id" dynamodbav:"UserID"`
+}
+
+// UserEmailPK is the sentinel partition key for email: EMAIL#<trimmed, lowercased email>,
+// so addresses differing only in case reserve the same item
+func UserEmailPK(email string) string {
+	return fmt.Sprintf("EMAIL#%s", strings.ToLower(strings.TrimSpace(email)))
+}
+
+// NewUserEmailIndex builds the sentinel reserving email for userID
+func NewUserEmailIndex(email, userID string) *UserEmailIndex {
+	index := &UserEmailIndex{UserID: userID}
+	index.SetTimestamps()
+	index.PK = UserEmailPK(email)
+	index.SK = "METADATA"
+	index.GSI1PK = "USER_EMAIL"
+	index.GSI1SK = index.PK
+	index.EntityType = "USER_EMAIL"
+	return index
+}
+
 // ============================================================================
 // Contact Model - Single Table Design
 // ============================================================================
 
 type ContactEntity struct {
-	DynamoDBEntity              // Embedded base entity
-	ID             string       `json:"id" dynamodbav:"ID"`
-	UserID         string       `json:"user_id" dynamodbav:"UserID"`
-	Name           string       `json:"name" dynamodbav:"Name"`
-	Email          string       `json:"email" dynamodbav:"Email"`
-	Phone          string       `json:"phone" dynamodbav:"Phone"`
-	Company        string       `json:"company" dynamodbav:"Company"`
-	IsFavorite     bool         `json:"is_favorite" dynamodbav:"IsFavorite"`
+	DynamoDBEntity            // Embedded base entity
+	ID             string     `json:"id" dynamodbav:"ID"`
+	UserID         string     `json:"user_id" dynamodbav:"UserID"`
+	Name           string     `json:"name" dynamodbav:"Name"`
+	Email          string     `json:"email" dynamodbav:"Email"`
+	Phone          string     `json:"phone" dynamodbav:"Phone"`
+	Company        string     `json:"company" dynamodbav:"Company"`
+	JobTitle       string     `json:"job_title" dynamodbav:"JobTitle,omitempty"`
+	Address        string     `json:"address" dynamodbav:"Address,omitempty"`
+	Notes          string     `json:"notes" dynamodbav:"Notes,omitempty"`          // Encrypted at rest when field encryption is on
+	AvatarURL      string     `json:"avatar_url" dynamodbav:"AvatarURL,omitempty"` // Set by PresignContactAvatar
+	Tags           []string   `json:"tags,omitempty" dynamodbav:"Tags,omitempty"`  // A list, so contains() can filter on one tag
+	IsFavorite     bool       `json:"is_favorite" dynamodbav:"IsFavorite"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty" dynamodbav:"DeletedAt,omitempty"` // Set only on trash items
 }
 
+// IsSearchResult makes ContactEntity a member of the GraphQL SearchResult union
+func (ContactEntity) IsSearchResult() {}
+
 // NewContact creates a new contact with proper keys
-func NewContact(id, userID, name, email, phone, company string, isFavorite bool) *ContactEntity {
+func NewContact(id, userID, name, email, phone, company, jobTitle, address, notes string, isFavorite bool) *ContactEntity {
 	contact := &ContactEntity{
 		ID:         id,
 		UserID:     userID,
@@ -99,6 +162,9 @@ func NewContact(id, userID, name, email, phone, company string, isFavorite bool)
 		Email:      email,
 		Phone:      phone,
 		Company:    company,
+		JobTitle:   jobTitle,
+		Address:    address,
+		Notes:      notes,
 		IsFavorite: isFavorite,
 	}
 	
@@ -114,6 +180,120 @@ func NewContact(id, userID, name, email, phone, company string, isFavorite bool)
 	return contact
 }
 
+// NewFavoriteContactIndex builds the denormalized favorites index item for a contact.
+// It is a full copy of the contact stored under SK FAV#<id>, so a user's favorites
+// can be read with a begins_with query instead of filtering every contact.
+// GSI1PK/EntityType differ from CONTACT so the copy never shows up in contact listings.
+func NewFavoriteContactIndex(contact *ContactEntity) *ContactEntity {
+	fav := *contact
+	fav.SK = fmt.Sprintf("FAV#%s", contact.ID)
+	fav.GSI1PK = "CONTACT_FAV"
+	fav.GSI1SK = fmt.Sprintf("FAV#%s", contact.ID)
+	fav.EntityType = "CONTACT_FAV"
+	return &fav
+}
+
+// NewDeletedContact builds the trash item a soft-deleted contact is kept as: a copy
+// stored under SK TRASH#<id> and stamped with DeletedAt. Moving it out of CONTACT#
+// means every existing contact query skips it without a filter.
+func NewDeletedContact(contact *ContactEntity, deletedAt time.Time) *ContactEntity {
+	trash := *contact
+	trash.SK = fmt.Sprintf("TRASH#%s", contact.ID)
+	trash.GSI1PK = "CONTACT_TRASH"
+	trash.GSI1SK = fmt.Sprintf("TRASH#%s", contact.ID)
+	trash.EntityType = "CONTACT_TRASH"
+	trash.DeletedAt = &deletedAt
+	return &trash
+}
+
+// RestoredContact turns a trash item back into a live contact, keeping its ID and CreatedAt
+func RestoredContact(trash *ContactEntity) *ContactEntity {
+	contact := *trash
+	contact.SK = fmt.Sprintf("CONTACT#%s", trash.ID)
+	contact.GSI1PK = "CONTACT"
+	contact.GSI1SK = fmt.Sprintf("CONTACT#%s", trash.ID)
+	contact.EntityType = "CONTACT"
+	contact.DeletedAt = nil
+	return &contact
+}
+
+// ============================================================================
+// Audit Model - Single Table Design
+// ============================================================================
+
+// Audit actions
+const (
+	AuditContactTransferred = "contact.transferred"
+)
+
+// AuditEntry records a change made to an entity, newest last within the entity's partition
+type AuditEntry struct {
+	DynamoDBEntity              // Embedded base entity
+	ID             string       `json:"id" dynamodbav:"ID"`
+	TargetType     string       `json:"target_type" dynamodbav:"TargetType"` // Audited entity type (CONTACT, USER, ...)
+	TargetID       string       `json:"target_id" dynamodbav:"TargetID"`
+	Action         string       `json:"action" dynamodbav:"Action"`
+	Actor          string       `json:"actor" dynamodbav:"Actor"`
+	OldOwnerID     string       `json:"old_owner_id,omitempty" dynamodbav:"OldOwnerID,omitempty"`
+	NewOwnerID     string       `json:"new_owner_id,omitempty" dynamodbav:"NewOwnerID,omitempty"`
+	RequestID      string       `json:"request_id,omitempty" dynamodbav:"RequestID,omitempty"`
+}
+
+// NewAuditEntry creates an audit entry with proper keys.
+// The timestamp leads the SK so an entity's history reads back in order.
+func NewAuditEntry(id, targetType, targetID, action, actor string) *AuditEntry {
+	entry := &AuditEntry{
+		ID:         id,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Action:     action,
+		Actor:      actor,
+	}
+	entry.SetTimestamps()
+
+	// Set single-table design keys
+	// PK: AUDIT#456 (all history for one entity)
+	// SK: AUDIT#<created_at>#<id> (chronological)
+	entry.PK = fmt.Sprintf("AUDIT#%s", targetID)
+	entry.SK = fmt.Sprintf("AUDIT#%s#%s", entry.CreatedAt.Format(time.RFC3339Nano), id)
+	entry.GSI1PK = "AUDIT"
+	entry.GSI1SK = entry.SK
+	entry.EntityType = "AUDIT"
+
+	return entry
+}
+
+// ============================================================================
+// Index Keys
+// ============================================================================
+
+// IndexKeys are the GSI1 attributes the constructors above set on every entity
+type IndexKeys struct {
+	EntityType string
+	GSI1PK     string
+	GSI1SK     string
+}
+
+// IndexKeysFor derives the index attributes an item should carry from its PK/SK,
+// following the patterns below. ok is false for keys that match no known entity.
+// createdAt feeds the USER sort key; a zero time sorts such users first.
+func IndexKeysFor(pk, sk string, createdAt time.Time) (keys IndexKeys, ok bool) {
+	switch {
+	case strings.HasPrefix(pk, "USER#") && sk == "METADATA":
+		return IndexKeys{"USER", "USER", UserGSI1SK(strings.TrimPrefix(pk, "USER#"), createdAt)}, true
+	case strings.HasPrefix(pk, "USER#") && strings.HasPrefix(sk, "CONTACT#"):
+		return IndexKeys{"CONTACT", "CONTACT", sk}, true
+	case strings.HasPrefix(pk, "USER#") && strings.HasPrefix(sk, "FAV#"):
+		return IndexKeys{"CONTACT_FAV", "CONTACT_FAV", sk}, true
+	case strings.HasPrefix(pk, "USER#") && strings.HasPrefix(sk, "TRASH#"):
+		return IndexKeys{"CONTACT_TRASH", "CONTACT_TRASH", sk}, true
+	case strings.HasPrefix(pk, "AUDIT#") && strings.HasPrefix(sk, "AUDIT#"):
+		return IndexKeys{"AUDIT", "AUDIT", sk}, true
+	case strings.HasPrefix(pk, "EMAIL#") && sk == "METADATA":
+		return IndexKeys{"USER_EMAIL", "USER_EMAIL", pk}, true
+	}
+	return IndexKeys{}, false
+}
 
 // ============================================================================
 // Key Design Patterns Explained
@@ -125,13 +305,35 @@ SINGLE TABLE DESIGN PATTERNS:
 1. USER (standalone entity)
    PK: USER#123
    SK: METADATA
-   Access: Direct lookup by user ID
+   GSI1SK: 2024-03-01T12:00:00.000000000Z#USER#123
+   Access: Direct lookup by user ID, or users created in a date range via
+   GSI1PK = USER AND GSI1SK BETWEEN (see UserCreatedRange)
+
+   Email sentinel (reserves a user's email, written in the user's transactions)
+   PK: EMAIL#ada@example.com (lowercased)
+   SK: METADATA
+   Access: Direct lookup of a user ID by email; attribute_not_exists(PK) keeps emails unique
 
 2. CONTACT (belongs to user)
    PK: USER#123
    SK: CONTACT#456
    Access: Query all contacts for a user
 
+   Favorites index (denormalized copy, only while IsFavorite is true)
+   PK: USER#123
+   SK: FAV#456
+   Access: Query a user's favorites with begins_with(SK, "FAV#")
+
+   Trash (soft-deleted copy, replaces the CONTACT# item until restored or purged)
+   PK: USER#123
+   SK: TRASH#456
+   Access: Query a user's deleted contacts with begins_with(SK, "TRASH#")
+
+   Audit history (one partition per audited entity, e.g. a contact)
+   PK: AUDIT#456
+   SK: AUDIT#<timestamp>#<id>
+   Access: Query an entity's history in order
+
 3. ORDER (belongs to user, searchable by status)
    PK: USER#123
    SK: ORDER#789
@@ -157,6 +359,7 @@ GSI1 Usage:
   * All orders with status "PENDING"
   * All products in category "Electronics"
   * All comments by a specific user
+  * All users created between two dates
 
 Benefits:
 - Single table for all entities
@@ -0,0 +1,126 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ErrInvalidCursor is returned for cursors that are malformed or have been tampered with.
+// REST maps it to 400 and GraphQL to a VALIDATION error.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+var (
+	secretMu sync.RWMutex
+	secret   = randomSecret()
+)
+
+// SetSecret sets the HMAC key cursors are signed with. All instances behind a load
+// balancer must share it, otherwise a cursor issued by one is rejected by another.
+// An empty key (and the default, before SetSecret) is a random one generated for this
+// process: cursors still can't be forged, but don't survive a restart or work on
+// another instance.
+func SetSecret(key string) {
+	secretMu.Lock()
+	defer secretMu.Unlock()
+	if key == "" {
+		secret = randomSecret()
+		return
+	}
+	secret = []byte(key)
+}
+
+// randomSecret returns a fresh 256-bit HMAC key
+func randomSecret() []byte {
+	key := make([]byte, sha256.Size)
+	rand.Read(key) // never fails
+	return key
+}
+
+// cursorValue is the JSON form of a key attribute; exactly one field is set
+type cursorValue struct {
+	S *string `json:"S,omitempty"`
+	N *string `json:"N,omitempty"`
+}
+
+// EncodeCursor turns a DynamoDB LastEvaluatedKey into an opaque, signed cursor:
+// base64url(JSON) + "." + base64url(HMAC-SHA256). An empty key encodes to "" (no more pages).
+// Only string and number attributes are supported, which covers every key in the table.
+func EncodeCursor(key map[string]types.AttributeValue) string {
+	if len(key) == 0 {
+		return ""
+	}
+
+	values := make(map[string]cursorValue, len(key))
+	for name, av := range key {
+		switch v := av.(type) {
+		case *types.AttributeValueMemberS:
+			values[name] = cursorValue{S: &v.Value}
+		case *types.AttributeValueMemberN:
+			values[name] = cursorValue{N: &v.Value}
+		}
+	}
+
+	payload, _ := json.Marshal(values) // map of strings can't fail to marshal
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(sign(payload))
+}
+
+// DecodeCursor reverses EncodeCursor, returning ErrInvalidCursor if the cursor is
+// malformed or its signature doesn't match. An empty cursor decodes to a nil key.
+func DecodeCursor(cursor string) (map[string]types.AttributeValue, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	encoded, sig, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	gotSig, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(gotSig, sign(payload)) {
+		return nil, ErrInvalidCursor
+	}
+
+	var values map[string]cursorValue
+	if err := json.Unmarshal(payload, &values); err != nil || len(values) == 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	key := make(map[string]types.AttributeValue, len(values))
+	for name, v := range values {
+		switch {
+		case v.S != nil && v.N == nil:
+			key[name] = &types.AttributeValueMemberS{Value: *v.S}
+		case v.N != nil && v.S == nil:
+			key[name] = &types.AttributeValueMemberN{Value: *v.N}
+		default:
+			return nil, fmt.Errorf("%w: bad value for %q", ErrInvalidCursor, name)
+		}
+	}
+
+	return key, nil
+}
+
+// sign returns the HMAC-SHA256 of payload under the configured secret
+func sign(payload []byte) []byte {
+	secretMu.RLock()
+	defer secretMu.RUnlock()
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
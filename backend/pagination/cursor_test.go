@@ -0,0 +1,98 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	SetSecret("test-secret")
+
+	key := map[string]types.AttributeValue{
+		"PK":    &types.AttributeValueMemberS{Value: "USER#1"},
+		"SK":    &types.AttributeValueMemberS{Value: "CONTACT#2"},
+		"Count": &types.AttributeValueMemberN{Value: "42"},
+	}
+
+	decoded, err := DecodeCursor(EncodeCursor(key))
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if got := decoded["PK"].(*types.AttributeValueMemberS).Value; got != "USER#1" {
+		t.Errorf("PK = %q, want USER#1", got)
+	}
+	if got := decoded["SK"].(*types.AttributeValueMemberS).Value; got != "CONTACT#2" {
+		t.Errorf("SK = %q, want CONTACT#2", got)
+	}
+	if got := decoded["Count"].(*types.AttributeValueMemberN).Value; got != "42" {
+		t.Errorf("Count = %q, want 42", got)
+	}
+}
+
+func TestCursorEmpty(t *testing.T) {
+	if got := EncodeCursor(nil); got != "" {
+		t.Errorf("EncodeCursor(nil) = %q, want empty", got)
+	}
+	if key, err := DecodeCursor(""); key != nil || err != nil {
+		t.Errorf("DecodeCursor(\"\") = %v, %v; want nil, nil", key, err)
+	}
+}
+
+func TestCursorRejectsTampering(t *testing.T) {
+	SetSecret("test-secret")
+	cursor := EncodeCursor(map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: "USER#1"},
+	})
+	payload, sig, _ := strings.Cut(cursor, ".")
+
+	// Same signature over a different user's key
+	forged := EncodeCursor(map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: "USER#2"},
+	})
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	for name, bad := range map[string]string{
+		"garbage":         "not-a-cursor",
+		"no signature":    payload,
+		"swapped payload": forgedPayload + "." + sig,
+		"bad base64":      "!!!." + sig,
+	} {
+		if _, err := DecodeCursor(bad); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: err = %v, want ErrInvalidCursor", name, err)
+		}
+	}
+
+	// A cursor signed with another secret is rejected too
+	SetSecret("other-secret")
+	defer SetSecret("test-secret")
+	if _, err := DecodeCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("foreign secret: err = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestCursorEmptySecretIsRandom(t *testing.T) {
+	defer SetSecret("test-secret")
+	key := map[string]types.AttributeValue{"PK": &types.AttributeValueMemberS{Value: "USER#1"}}
+
+	// Without a configured secret a cursor can't be signed by anyone who knows the format
+	SetSecret("")
+	cursor := EncodeCursor(key)
+	if _, err := DecodeCursor(cursor); err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, nil)
+	payload, _, _ := strings.Cut(cursor, ".")
+	raw, _ := base64.RawURLEncoding.DecodeString(payload)
+	mac.Write(raw)
+	forged := payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	if _, err := DecodeCursor(forged); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("cursor signed with an empty key: err = %v, want ErrInvalidCursor", err)
+	}
+}
@@ -0,0 +1,37 @@
+package pagination
+
+import "sync/atomic"
+
+// DefaultMaxPageSize is the page size cap used until SetMaxPageSize is called
+const DefaultMaxPageSize = 1000
+
+var maxPageSize atomic.Int64
+
+func init() {
+	maxPageSize.Store(DefaultMaxPageSize)
+}
+
+// SetMaxPageSize sets the largest page any list (REST, GraphQL or a repository
+// query page) will return. Values below 1 keep the current setting.
+func SetMaxPageSize(n int) {
+	if n < 1 {
+		return
+	}
+	maxPageSize.Store(int64(n))
+}
+
+// MaxPageSize returns the current page size cap
+func MaxPageSize() int {
+	return int(maxPageSize.Load())
+}
+
+// ClampLimit returns the page size to actually use for a requested limit.
+// Limits above the cap are clamped rather than rejected; a limit below 1
+// (i.e. none requested) gets the cap.
+func ClampLimit(limit int) int {
+	max := MaxPageSize()
+	if limit < 1 || limit > max {
+		return max
+	}
+	return limit
+}
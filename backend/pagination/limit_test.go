@@ -0,0 +1,35 @@
+package pagination
+
+import "testing"
+
+func TestClampLimit(t *testing.T) {
+	SetMaxPageSize(50)
+	defer SetMaxPageSize(DefaultMaxPageSize)
+
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{10, 10},
+		{50, 50},
+		{51, 50},
+		{100000, 50},
+		{0, 50},
+		{-1, 50},
+	}
+	for _, tt := range tests {
+		if got := ClampLimit(tt.limit); got != tt.want {
+			t.Errorf("ClampLimit(%d) = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestSetMaxPageSize_IgnoresNonPositive(t *testing.T) {
+	SetMaxPageSize(25)
+	defer SetMaxPageSize(DefaultMaxPageSize)
+
+	SetMaxPageSize(0)
+	if got := MaxPageSize(); got != 25 {
+		t.Errorf("MaxPageSize() = %d, want 25", got)
+	}
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"hub-control-plane/backend/requestid"
+)
+
+// apqKeyPrefix namespaces GraphQL automatic persisted queries (apq:<sha256>). A query's
+// text is the same for every tenant, so like the feature flags it isn't tenant-namespaced.
+const apqKeyPrefix = "apq:"
+
+// DefaultAPQTTL is how long a registered persisted query is kept by default
+const DefaultAPQTTL = 24 * time.Hour
+
+// APQCache stores GraphQL automatic persisted queries in Redis so every replica can
+// resolve a hash any of them registered. It implements gqlgen's graphql.Cache[string].
+// Redis errors are logged and read as misses; the client then just resends the query.
+type APQCache struct {
+	client redis.UniversalClient
+	ttl    time.Duration
+}
+
+// NewAPQCache keeps registered queries for ttl (0 = DefaultAPQTTL)
+func NewAPQCache(client redis.UniversalClient, ttl time.Duration) *APQCache {
+	if ttl <= 0 {
+		ttl = DefaultAPQTTL
+	}
+	return &APQCache{client: client, ttl: ttl}
+}
+
+// Get returns the query registered under hash
+func (c *APQCache) Get(ctx context.Context, hash string) (string, bool) {
+	query, err := c.client.Get(ctx, apqKeyPrefix+hash).Result()
+	if err != nil {
+		if err != redis.Nil {
+			requestid.Logf(ctx, "Warning: failed to read persisted query %s: %v", hash, err)
+		}
+		return "", false
+	}
+	return query, true
+}
+
+// Add registers query under hash. gqlgen only calls it after checking that hash is the
+// query's SHA-256, so clients can't plant a different query under a known hash.
+func (c *APQCache) Add(ctx context.Context, hash, query string) {
+	if err := c.client.Set(ctx, apqKeyPrefix+hash, query, c.ttl).Err(); err != nil {
+		requestid.Logf(ctx, "Warning: failed to store persisted query %s: %v", hash, err)
+	}
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"hub-control-plane/backend/requestid"
+)
+
+// expectedAttributes caches the attribute names each model type requires, keyed by reflect.Type
+var expectedAttributes sync.Map
+
+// EnableAttributeValidation turns on a development aid: every fetched item is checked
+// against the dynamodbav tags of the type it is decoded into, and attributes the model
+// expects but the item lacks are logged. attributevalue silently leaves those fields
+// zero, which hides drift such as legacy "userid" items read into ContactEntity.UserID.
+// Costs a map walk per item, so keep it off in production.
+func (r *GenericRepository) EnableAttributeValidation(enabled bool) {
+	r.validateAttributes = enabled
+}
+
+// checkAttributes logs the expected attributes missing from each item. target is the
+// unmarshal destination: a pointer to a struct or to a slice of structs (or pointers).
+func (r *GenericRepository) checkAttributes(ctx context.Context, items []map[string]types.AttributeValue, target interface{}) {
+	if !r.validateAttributes {
+		return
+	}
+	t := modelType(reflect.TypeOf(target))
+	if t == nil {
+		return
+	}
+	expected := attributesOf(t)
+
+	for _, item := range items {
+		var missing []string
+		for _, name := range expected {
+			if _, ok := item[name]; ok {
+				continue
+			}
+			if actual := caseInsensitiveMatch(item, name); actual != "" {
+				missing = append(missing, name+" (found "+actual+")")
+			} else {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			requestid.Logf(ctx, "Warning: schema drift: %s/%s is missing %s for %s",
+				keyString(item, "PK"), keyString(item, "SK"), strings.Join(missing, ", "), t.Name())
+		}
+	}
+}
+
+// modelType unwraps pointers and slices down to the struct being decoded into
+func modelType(t reflect.Type) reflect.Type {
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// attributesOf lists the attribute names t's dynamodbav tags require (omitempty fields
+// are optional and skipped), following embedded structs like DynamoDBEntity
+func attributesOf(t reflect.Type) []string {
+	if cached, ok := expectedAttributes.Load(t); ok {
+		return cached.([]string)
+	}
+
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("dynamodbav")
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			names = append(names, attributesOf(field.Type)...)
+			continue
+		}
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if strings.Contains(opts, "omitempty") {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	expectedAttributes.Store(t, names)
+	return names
+}
+
+// caseInsensitiveMatch returns an item attribute that differs from name only by case
+func caseInsensitiveMatch(item map[string]types.AttributeValue, name string) string {
+	for actual := range item {
+		if strings.EqualFold(actual, name) {
+			return actual
+		}
+	}
+	return ""
+}
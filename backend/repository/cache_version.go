@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"hub-control-plane/backend/tenant"
+)
+
+// Cache schema versions, one per cached type. Bump a type's version whenever its JSON
+// shape changes incompatibly: RedisAdapter writes it into every key of that type
+// (user:1 is stored as v2:user:1), so a deploy with the bump stops reading the old
+// entries at once, without a flush, and they expire by their TTL.
+const (
+	UserCacheVersion      = 1 // user:<id>, users:list
+	ContactCacheVersion   = 1 // contact:<user>:<id>, contacts:<view>:user:<user>
+	DashboardCacheVersion = 1 // dashboard:<user>
+)
+
+// cacheKeyVersions maps each versioned key prefix to its type's version
+var cacheKeyVersions = []struct {
+	prefix  string
+	version int
+}{
+	{"user:", UserCacheVersion},
+	{"users:", UserCacheVersion},
+	{"contact:", ContactCacheVersion},
+	{"contacts:", ContactCacheVersion},
+	{"dashboard:", DashboardCacheVersion},
+}
+
+// versionedKey prefixes key (or a key pattern) with its type's version as v<N>:<key>.
+// Keys of other types are returned unchanged.
+func versionedKey(key string) string {
+	for _, v := range cacheKeyVersions {
+		if strings.HasPrefix(key, v.prefix) {
+			return "v" + strconv.Itoa(v.version) + ":" + key
+		}
+	}
+	return key
+}
+
+// redisKey is the Redis key for a service cache key: versioned, then namespaced for
+// ctx's tenant (t:<tenant>:v<N>:<key>)
+func redisKey(ctx context.Context, key string) string {
+	return tenant.CacheKey(ctx, versionedKey(key))
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"hub-control-plane/backend/requestid"
+)
+
+type readSetKey struct{}
+
+// readSet remembers the UpdatedAt each item had when a request read it, by PK/SK
+type readSet struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// WithReadTracking returns a copy of ctx that remembers the items read through it, so
+// conflict logging can tell an update that overwrote someone else's write. It only
+// allocates the tracker; nothing is recorded unless EnableConflictLogging is on.
+func WithReadTracking(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readSetKey{}, &readSet{})
+}
+
+// EnableConflictLogging turns on a debug aid for sizing the need for optimistic locking.
+// Items fetched with Get record their UpdatedAt in the request's read set (see
+// WithReadTracking), and Update asks DynamoDB for the UpdatedAt it replaced; when the
+// two differ, another writer got in between and this update silently won, which is
+// logged as a warning. Updates still apply as before. Costs the returned attribute and
+// a map entry per read, so keep it off in production.
+func (r *GenericRepository) EnableConflictLogging(enabled bool) {
+	r.logConflicts = enabled
+}
+
+// recordRead notes the UpdatedAt of an item fetched in this request
+func (r *GenericRepository) recordRead(ctx context.Context, pk, sk string, item map[string]types.AttributeValue) {
+	if !r.logConflicts {
+		return
+	}
+	reads, _ := ctx.Value(readSetKey{}).(*readSet)
+	updatedAt, ok := storedTime(item, "UpdatedAt")
+	if reads == nil || !ok {
+		return
+	}
+
+	reads.mu.Lock()
+	defer reads.mu.Unlock()
+	if reads.seen == nil {
+		reads.seen = make(map[string]time.Time)
+	}
+	reads.seen[pk+"|"+sk] = updatedAt
+}
+
+// checkConflict compares the UpdatedAt an update replaced (old holds the UPDATED_OLD
+// attributes) with the one this request read, then records written as the item's new
+// version so a second update in the same request isn't reported as a conflict
+func (r *GenericRepository) checkConflict(ctx context.Context, pk, sk string, old map[string]types.AttributeValue, written time.Time) {
+	if !r.logConflicts {
+		return
+	}
+	reads, _ := ctx.Value(readSetKey{}).(*readSet)
+	if reads == nil {
+		return
+	}
+
+	reads.mu.Lock()
+	read, wasRead := reads.seen[pk+"|"+sk]
+	if wasRead {
+		reads.seen[pk+"|"+sk] = written
+	}
+	reads.mu.Unlock()
+
+	if !wasRead {
+		return
+	}
+	if replaced, ok := storedTime(old, "UpdatedAt"); ok && !replaced.Equal(read) {
+		requestid.Logf(ctx, "Warning: write conflict: %s/%s was read at UpdatedAt %s but %s was stored when it was updated (last write wins)",
+			pk, sk, read.Format(time.RFC3339Nano), replaced.Format(time.RFC3339Nano))
+	}
+}
+
+// storedTime decodes a timestamp attribute (an RFC3339Nano string)
+func storedTime(item map[string]types.AttributeValue, name string) (time.Time, bool) {
+	s, ok := item[name].(*types.AttributeValueMemberS)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, s.Value)
+	return t, err == nil
+}
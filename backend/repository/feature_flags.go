@@ -0,0 +1,13 @@
+package repository
+
+import "context"
+
+// featureFlagsKey is the Redis hash of runtime feature flag overrides (field = flag,
+// value = on/off). It is deployment-wide, so it is not tenant-namespaced.
+const featureFlagsKey = "feature_flags"
+
+// FeatureFlagOverrides returns every field of the feature_flags hash. A missing hash
+// means no overrides.
+func (c *RedisCache) FeatureFlagOverrides(ctx context.Context) (map[string]string, error) {
+	return c.client.HGetAll(ctx, featureFlagsKey).Result()
+}
@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+// KMSAPI is the subset of the KMS client used for envelope encryption
+type KMSAPI interface {
+	GenerateDataKey(ctx context.Context, params *kms.GenerateDataKeyInput, optFns ...func(*kms.Options)) (*kms.GenerateDataKeyOutput, error)
+	Decrypt(ctx context.Context, params *kms.DecryptInput, optFns ...func(*kms.Options)) (*kms.DecryptOutput, error)
+}
+
+// encryptedPrefix marks a string attribute written by FieldEncryptor:
+//
+//	enc:v1:<base64 KMS-wrapped data key>:<base64 nonce||AES-256-GCM ciphertext>
+//
+// Values without it are read back unchanged, so fields written before encryption was
+// turned on stay readable.
+const encryptedPrefix = "enc:v1:"
+
+// dataKeyMaxAge bounds how long one KMS data key is reused for writes
+const dataKeyMaxAge = 5 * time.Minute
+
+// maxCachedDataKeys bounds the unwrapped-key cache used on reads
+const maxCachedDataKeys = 1000
+
+var errMalformedCiphertext = errors.New("malformed encrypted attribute")
+
+// FieldEncryptor applies envelope encryption to designated string attributes: each value
+// is sealed with AES-256-GCM under a data key from KMS, and the KMS-wrapped key is stored
+// alongside it. Only the named attributes are touched.
+//
+// Encrypted attributes can't be used in key, filter or condition expressions.
+type FieldEncryptor struct {
+	kms    KMSAPI
+	keyID  string
+	fields map[string]bool
+
+	mu         sync.Mutex
+	dataKey    []byte // current plaintext data key for writes
+	wrappedKey []byte // dataKey as encrypted by KMS
+	issuedAt   time.Time
+	unwrapped  map[string][]byte // wrapped key (base64) -> plaintext, for reads
+}
+
+// NewFieldEncryptor encrypts the given attributes (by DynamoDB attribute name) under keyID
+func NewFieldEncryptor(client KMSAPI, keyID string, fields ...string) *FieldEncryptor {
+	e := &FieldEncryptor{
+		kms:       client,
+		keyID:     keyID,
+		fields:    make(map[string]bool, len(fields)),
+		unwrapped: make(map[string][]byte),
+	}
+	for _, field := range fields {
+		e.fields[field] = true
+	}
+	return e
+}
+
+// Encrypts reports whether attribute is one of the designated fields
+func (e *FieldEncryptor) Encrypts(attribute string) bool {
+	return e != nil && e.fields[attribute]
+}
+
+// EncryptItem replaces designated non-empty string attributes of item in place
+func (e *FieldEncryptor) EncryptItem(ctx context.Context, item map[string]types.AttributeValue) error {
+	if e == nil {
+		return nil
+	}
+	for name, value := range item {
+		if !e.fields[name] {
+			continue
+		}
+		encrypted, err := e.EncryptValue(ctx, value)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", name, err)
+		}
+		item[name] = encrypted
+	}
+	return nil
+}
+
+// EncryptValue seals a single string attribute value; other types and "" pass through
+func (e *FieldEncryptor) EncryptValue(ctx context.Context, value types.AttributeValue) (types.AttributeValue, error) {
+	s, ok := value.(*types.AttributeValueMemberS)
+	if !ok || s.Value == "" || strings.HasPrefix(s.Value, encryptedPrefix) {
+		return value, nil
+	}
+
+	key, wrapped, err := e.writeKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := seal(key, []byte(s.Value))
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.AttributeValueMemberS{Value: encryptedPrefix +
+		base64.StdEncoding.EncodeToString(wrapped) + ":" +
+		base64.StdEncoding.EncodeToString(sealed)}, nil
+}
+
+// DecryptItems restores designated attributes of every item in place
+func (e *FieldEncryptor) DecryptItems(ctx context.Context, items ...map[string]types.AttributeValue) error {
+	if e == nil {
+		return nil
+	}
+	for _, item := range items {
+		for name, value := range item {
+			if !e.fields[name] {
+				continue
+			}
+			s, ok := value.(*types.AttributeValueMemberS)
+			if !ok || !strings.HasPrefix(s.Value, encryptedPrefix) {
+				continue
+			}
+			plaintext, err := e.decrypt(ctx, s.Value)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt %s: %w", name, err)
+			}
+			item[name] = &types.AttributeValueMemberS{Value: plaintext}
+		}
+	}
+	return nil
+}
+
+func (e *FieldEncryptor) decrypt(ctx context.Context, value string) (string, error) {
+	wrappedB64, sealedB64, ok := strings.Cut(strings.TrimPrefix(value, encryptedPrefix), ":")
+	if !ok {
+		return "", errMalformedCiphertext
+	}
+	sealed, err := base64.StdEncoding.DecodeString(sealedB64)
+	if err != nil {
+		return "", errMalformedCiphertext
+	}
+
+	key, err := e.readKey(ctx, wrappedB64)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(key, sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// writeKey returns the data key for new ciphertexts, asking KMS for a fresh one
+// once the current key is older than dataKeyMaxAge
+func (e *FieldEncryptor) writeKey(ctx context.Context) ([]byte, []byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.dataKey != nil && time.Since(e.issuedAt) < dataKeyMaxAge {
+		return e.dataKey, e.wrappedKey, nil
+	}
+
+	output, err := e.kms.GenerateDataKey(ctx, &kms.GenerateDataKeyInput{
+		KeyId:   aws.String(e.keyID),
+		KeySpec: kmstypes.DataKeySpecAes256,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	e.dataKey, e.wrappedKey, e.issuedAt = output.Plaintext, output.CiphertextBlob, time.Now()
+	e.cacheUnwrapped(base64.StdEncoding.EncodeToString(output.CiphertextBlob), output.Plaintext)
+	return e.dataKey, e.wrappedKey, nil
+}
+
+// readKey unwraps a stored data key, via the cache when it has been seen before
+func (e *FieldEncryptor) readKey(ctx context.Context, wrappedB64 string) ([]byte, error) {
+	e.mu.Lock()
+	key, ok := e.unwrapped[wrappedB64]
+	e.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	wrapped, err := base64.StdEncoding.DecodeString(wrappedB64)
+	if err != nil {
+		return nil, errMalformedCiphertext
+	}
+	output, err := e.kms.Decrypt(ctx, &kms.DecryptInput{
+		CiphertextBlob: wrapped,
+		KeyId:          aws.String(e.keyID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key: %w", err)
+	}
+
+	e.mu.Lock()
+	e.cacheUnwrapped(wrappedB64, output.Plaintext)
+	e.mu.Unlock()
+	return output.Plaintext, nil
+}
+
+// cacheUnwrapped remembers a plaintext data key; callers hold e.mu
+func (e *FieldEncryptor) cacheUnwrapped(wrappedB64 string, key []byte) {
+	if len(e.unwrapped) >= maxCachedDataKeys {
+		e.unwrapped = make(map[string][]byte)
+	}
+	e.unwrapped[wrappedB64] = key
+}
+
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errMalformedCiphertext
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt attribute: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
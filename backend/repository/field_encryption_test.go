@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// fakeKMS "wraps" data keys by prefixing them, and counts calls so tests can
+// check data keys are reused instead of fetched per value
+type fakeKMS struct {
+	generated, decrypted int
+}
+
+var wrapPrefix = []byte("wrapped:")
+
+func (f *fakeKMS) GenerateDataKey(ctx context.Context, in *kms.GenerateDataKeyInput, _ ...func(*kms.Options)) (*kms.GenerateDataKeyOutput, error) {
+	f.generated++
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return &kms.GenerateDataKeyOutput{
+		Plaintext:      key,
+		CiphertextBlob: append(append([]byte{}, wrapPrefix...), key...),
+		KeyId:          in.KeyId,
+	}, nil
+}
+
+func (f *fakeKMS) Decrypt(ctx context.Context, in *kms.DecryptInput, _ ...func(*kms.Options)) (*kms.DecryptOutput, error) {
+	f.decrypted++
+	if !bytes.HasPrefix(in.CiphertextBlob, wrapPrefix) {
+		return nil, errors.New("InvalidCiphertextException")
+	}
+	return &kms.DecryptOutput{Plaintext: bytes.TrimPrefix(in.CiphertextBlob, wrapPrefix)}, nil
+}
+
+type noteItem struct {
+	PK    string `dynamodbav:"PK"`
+	Name  string `dynamodbav:"Name"`
+	Notes string `dynamodbav:"Notes"`
+}
+
+func TestFieldEncryptor_RoundTripsNotes(t *testing.T) {
+	ctx := context.Background()
+	kmsClient := &fakeKMS{}
+	encryptor := NewFieldEncryptor(kmsClient, "alias/contacts", "Notes")
+
+	item, err := attributevalue.MarshalMap(noteItem{PK: "USER#1", Name: "Ada", Notes: "prefers email after 6pm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := encryptor.EncryptItem(ctx, item); err != nil {
+		t.Fatalf("EncryptItem: %v", err)
+	}
+
+	stored := item["Notes"].(*types.AttributeValueMemberS).Value
+	if !strings.HasPrefix(stored, encryptedPrefix) || strings.Contains(stored, "6pm") {
+		t.Fatalf("Notes stored as %q, want ciphertext", stored)
+	}
+	if name := item["Name"].(*types.AttributeValueMemberS).Value; name != "Ada" {
+		t.Errorf("undesignated Name was changed to %q", name)
+	}
+
+	// A fresh encryptor (as after a restart) has to unwrap the key through KMS
+	reader := NewFieldEncryptor(kmsClient, "alias/contacts", "Notes")
+	if err := reader.DecryptItems(ctx, item); err != nil {
+		t.Fatalf("DecryptItems: %v", err)
+	}
+	var got noteItem
+	if err := attributevalue.UnmarshalMap(item, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Notes != "prefers email after 6pm" {
+		t.Errorf("decrypted Notes = %q", got.Notes)
+	}
+	if kmsClient.decrypted != 1 {
+		t.Errorf("KMS Decrypt called %d times, want 1", kmsClient.decrypted)
+	}
+}
+
+func TestFieldEncryptor_ReusesDataKey(t *testing.T) {
+	ctx := context.Background()
+	kmsClient := &fakeKMS{}
+	encryptor := NewFieldEncryptor(kmsClient, "alias/contacts", "Notes")
+
+	for _, note := range []string{"one", "two", "three"} {
+		item := map[string]types.AttributeValue{"Notes": &types.AttributeValueMemberS{Value: note}}
+		if err := encryptor.EncryptItem(ctx, item); err != nil {
+			t.Fatal(err)
+		}
+		if err := encryptor.DecryptItems(ctx, item); err != nil {
+			t.Fatal(err)
+		}
+		if got := item["Notes"].(*types.AttributeValueMemberS).Value; got != note {
+			t.Errorf("round trip = %q, want %q", got, note)
+		}
+	}
+	if kmsClient.generated != 1 || kmsClient.decrypted != 0 {
+		t.Errorf("KMS calls: %d GenerateDataKey, %d Decrypt; want 1 and 0", kmsClient.generated, kmsClient.decrypted)
+	}
+}
+
+func TestFieldEncryptor_PlaintextPassesThrough(t *testing.T) {
+	// Notes written before encryption was enabled stay readable
+	item := map[string]types.AttributeValue{"Notes": &types.AttributeValueMemberS{Value: "legacy note"}}
+	if err := NewFieldEncryptor(&fakeKMS{}, "k", "Notes").DecryptItems(context.Background(), item); err != nil {
+		t.Fatalf("DecryptItems: %v", err)
+	}
+	if got := item["Notes"].(*types.AttributeValueMemberS).Value; got != "legacy note" {
+		t.Errorf("Notes = %q", got)
+	}
+}
+
+func TestEncryptUpdates_LeavesCallerMapAlone(t *testing.T) {
+	repo := &GenericRepository{}
+	repo.SetFieldEncryptor(NewFieldEncryptor(&fakeKMS{}, "k", "Notes"))
+
+	updates := map[string]interface{}{"Name": "Ada", "Notes": "secret"}
+	sealed, err := repo.encryptUpdates(context.Background(), updates)
+	if err != nil {
+		t.Fatalf("encryptUpdates: %v", err)
+	}
+	if updates["Notes"] != "secret" {
+		t.Errorf("caller's map was modified: %v", updates["Notes"])
+	}
+	av, ok := sealed["Notes"].(*types.AttributeValueMemberS)
+	if !ok || !strings.HasPrefix(av.Value, encryptedPrefix) {
+		t.Errorf("sealed Notes = %#v, want an encrypted attribute value", sealed["Notes"])
+	}
+	if sealed["Name"] != "Ada" {
+		t.Errorf("sealed Name = %v", sealed["Name"])
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -11,12 +12,22 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"hub-control-plane/backend/clock"
+	"hub-control-plane/backend/pagination"
+	"hub-control-plane/backend/requestid"
+	"hub-control-plane/backend/tenant"
+	"hub-control-plane/backend/tracing"
 )
 
 // Common errors
 var (
-	ErrNotFound      = errors.New("item not found")
-	ErrAlreadyExists = errors.New("item already exists")
+	ErrNotFound        = errors.New("item not found")
+	ErrAlreadyExists   = errors.New("item already exists")
+	ErrBoundExceeded   = errors.New("increment would exceed bounds")
+	ErrConditionFailed = errors.New("condition check failed")
 )
 
 // BaseModel interface that all models must implement
@@ -33,6 +44,33 @@ type BaseModel interface {
 type GenericRepository struct {
 	client    *dynamodb.Client
 	tableName string
+
+	// returnConsumedCapacity asks DynamoDB to report RCU/WCU per call (off by default)
+	returnConsumedCapacity bool
+
+	// slowQueryThreshold logs calls that take longer than this (0 = disabled)
+	slowQueryThreshold time.Duration
+
+	// encryptor seals designated attributes on write and opens them on read (nil = off)
+	encryptor *FieldEncryptor
+
+	// validateAttributes logs fetched items missing attributes their model expects (debug)
+	validateAttributes bool
+
+	// tenantTables routes each call to its tenant's table (see tenant.TableName)
+	tenantTables bool
+
+	// logConflicts warns when an update overwrites a write made since the request's Get (debug)
+	logConflicts bool
+
+	// itemSizes records the size of written items (nil = off)
+	itemSizes metric.Int64Histogram
+
+	// Background work (QueryStream producers) that Close stops and waits for
+	lifecycleMu sync.Mutex
+	closing     chan struct{}
+	closed      bool
+	workers     sync.WaitGroup
 }
 
 // NewGenericRepository creates a new generic repository
@@ -43,51 +81,105 @@ func NewGenericRepository(awsConfig aws.Config, tableName string) *GenericReposi
 	}
 }
 
+// EnableConsumedCapacity turns on ReturnConsumedCapacity=TOTAL for Get/Put/Update/Query
+// and logs the consumed capacity of each call. Off by default to avoid the extra payload.
+func (r *GenericRepository) EnableConsumedCapacity(enabled bool) {
+	r.returnConsumedCapacity = enabled
+}
+
+// SetSlowQueryThreshold logs a warning for every DynamoDB call slower than threshold.
+// Zero disables the log.
+func (r *GenericRepository) SetSlowQueryThreshold(threshold time.Duration) {
+	r.slowQueryThreshold = threshold
+}
+
+// SetFieldEncryptor turns on envelope encryption for the encryptor's designated attributes.
+// Every read and write path goes through it, so callers only ever see plaintext.
+func (r *GenericRepository) SetFieldEncryptor(encryptor *FieldEncryptor) {
+	r.encryptor = encryptor
+}
+
+// EnableTenantTables makes every call use the table of the tenant in its context
+// (<tenant>-<table>) instead of the configured table. Calls without a tenant keep
+// using the configured table.
+func (r *GenericRepository) EnableTenantTables(enabled bool) {
+	r.tenantTables = enabled
+}
+
+// table returns the table a call with ctx reads and writes
+func (r *GenericRepository) table(ctx context.Context) string {
+	if r.tenantTables {
+		return tenant.TableName(ctx, r.tableName)
+	}
+	return r.tableName
+}
+
 // Put creates or updates an item in DynamoDB
 // T must implement BaseModel interface
 func (r *GenericRepository) Put(ctx context.Context, item BaseModel) error {
+	ctx, done := r.observe(ctx, "PutItem", item.GetPK(), item.GetSK())
+	defer done()
+
 	// Add timestamps
 	if timestamped, ok := item.(interface{ SetTimestamps() }); ok {
 		timestamped.SetTimestamps()
 	}
 
-	av, err := attributevalue.MarshalMap(item)
+	av, err := r.marshalItem(ctx, item)
 	if err != nil {
 		return fmt.Errorf("failed to marshal item: %w", err)
 	}
 
 	input := &dynamodb.PutItemInput{
-		TableName: aws.String(r.tableName),
-		Item:      av,
+		TableName:              aws.String(r.table(ctx)),
+		Item:                   av,
+		ReturnConsumedCapacity: r.consumedCapacityMode(),
 	}
 
-	_, err = r.client.PutItem(ctx, input)
+	output, err := r.client.PutItem(ctx, input)
 	if err != nil {
 		return fmt.Errorf("failed to put item: %w", err)
 	}
+	r.logConsumedCapacity(ctx, "PutItem", output.ConsumedCapacity)
 
 	return nil
 }
 
 // PutIfNotExists creates an item only if it doesn't exist (prevents overwrites)
 func (r *GenericRepository) PutIfNotExists(ctx context.Context, item BaseModel) error {
+	return r.PutIfNotExistsOn(ctx, item, "PK")
+}
+
+// PutIfNotExistsOn creates an item only if no stored item with its key has keyAttr set,
+// for tables whose uniqueness attribute isn't PK (e.g. attribute_not_exists(id)). Like
+// PutIfNotExists, a taken key returns ErrAlreadyExists.
+func (r *GenericRepository) PutIfNotExistsOn(ctx context.Context, item BaseModel, keyAttr string) error {
+	if keyAttr == "" {
+		return fmt.Errorf("key attribute is required")
+	}
+
+	ctx, done := r.observe(ctx, "PutItem", item.GetPK(), item.GetSK())
+	defer done()
+
 	// Add timestamps
 	if timestamped, ok := item.(interface{ SetTimestamps() }); ok {
 		timestamped.SetTimestamps()
 	}
 
-	av, err := attributevalue.MarshalMap(item)
+	av, err := r.marshalItem(ctx, item)
 	if err != nil {
 		return fmt.Errorf("failed to marshal item: %w", err)
 	}
 
 	input := &dynamodb.PutItemInput{
-		TableName:           aws.String(r.tableName),
-		Item:                av,
-		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+		TableName:              aws.String(r.table(ctx)),
+		Item:                   av,
+		ConditionExpression:      aws.String("attribute_not_exists(#key)"),
+		ExpressionAttributeNames: map[string]string{"#key": keyAttr},
+		ReturnConsumedCapacity:   r.consumedCapacityMode(),
 	}
 
-	_, err = r.client.PutItem(ctx, input)
+	output, err := r.client.PutItem(ctx, input)
 	if err != nil {
 		var ccf *types.ConditionalCheckFailedException
 		if errors.As(err, &ccf) {
@@ -95,45 +187,201 @@ func (r *GenericRepository) PutIfNotExists(ctx context.Context, item BaseModel)
 		}
 		return fmt.Errorf("failed to put item: %w", err)
 	}
+	r.logConsumedCapacity(ctx, "PutItem", output.ConsumedCapacity)
 
 	return nil
 }
 
+// PutIfAbsentOrGet creates item if no item with its key exists; otherwise it leaves the
+// stored item untouched and unmarshals it into result. created reports which happened.
+// Collapses the create-or-fetch race into one call: the existing item comes back with
+// the failed conditional put (ALL_OLD), falling back to a Get if the response omits it.
+func (r *GenericRepository) PutIfAbsentOrGet(ctx context.Context, item BaseModel, result BaseModel) (created bool, err error) {
+	ctx, done := r.observe(ctx, "PutItem", item.GetPK(), item.GetSK())
+	defer done()
+
+	// Add timestamps
+	if timestamped, ok := item.(interface{ SetTimestamps() }); ok {
+		timestamped.SetTimestamps()
+	}
+
+	av, err := r.marshalItem(ctx, item)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal item: %w", err)
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName:                           aws.String(r.table(ctx)),
+		Item:                                av,
+		ConditionExpression:                 aws.String("attribute_not_exists(PK)"),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		ReturnConsumedCapacity:              r.consumedCapacityMode(),
+	}
+
+	output, err := r.client.PutItem(ctx, input)
+	if err == nil {
+		r.logConsumedCapacity(ctx, "PutItem", output.ConsumedCapacity)
+		return true, nil
+	}
+
+	var ccf *types.ConditionalCheckFailedException
+	if !errors.As(err, &ccf) {
+		return false, fmt.Errorf("failed to put item: %w", err)
+	}
+
+	// Already exists - return the stored item
+	if ccf.Item == nil {
+		if err := r.Get(ctx, item.GetPK(), item.GetSK(), result); err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+	if err := r.unmarshalItem(ctx, ccf.Item, result); err != nil {
+		return false, fmt.Errorf("failed to unmarshal item: %w", err)
+	}
+	return false, nil
+}
+
 // Get retrieves an item by PK and SK
 // The result parameter must be a pointer to the struct you want to unmarshal into
 func (r *GenericRepository) Get(ctx context.Context, pk, sk string, result BaseModel) error {
+	ctx, done := r.observe(ctx, "GetItem", pk, sk)
+	defer done()
+
 	input := &dynamodb.GetItemInput{
-		TableName: aws.String(r.tableName),
+		TableName: aws.String(r.table(ctx)),
 		Key: map[string]types.AttributeValue{
 			"PK": &types.AttributeValueMemberS{Value: pk},
 			"SK": &types.AttributeValueMemberS{Value: sk},
 		},
+		ReturnConsumedCapacity: r.consumedCapacityMode(),
 	}
 
 	output, err := r.client.GetItem(ctx, input)
 	if err != nil {
 		return fmt.Errorf("failed to get item: %w", err)
 	}
+	r.logConsumedCapacity(ctx, "GetItem", output.ConsumedCapacity)
 
 	if output.Item == nil {
 		return ErrNotFound
 	}
 
-	if err := attributevalue.UnmarshalMap(output.Item, result); err != nil {
+	if err := r.unmarshalItem(ctx, output.Item, result); err != nil {
 		return fmt.Errorf("failed to unmarshal item: %w", err)
 	}
+	r.recordRead(ctx, pk, sk, output.Item)
 
 	return nil
 }
 
 // Update updates specific attributes of an item
+// Strict: the item must already exist (attribute_exists(PK)), otherwise ErrNotFound.
+// Use Upsert when the item should be created on first write.
 func (r *GenericRepository) Update(ctx context.Context, pk, sk string, updates map[string]interface{}) error {
+	return r.update(ctx, pk, sk, updates, nil)
+}
+
+// UpdateIfUnmodifiedSince is Update that only applies if the stored UpdatedAt is not
+// after since, at the one-second resolution of HTTP dates (If-Unmodified-Since).
+// A newer item is left untouched and ErrConditionFailed is returned.
+func (r *GenericRepository) UpdateIfUnmodifiedSince(ctx context.Context, pk, sk string, updates map[string]interface{}, since time.Time) error {
+	condition := expression.Name("UpdatedAt").LessThan(expression.Value(unmodifiedSinceBound(since)))
+	return r.update(ctx, pk, sk, updates, &condition)
+}
+
+// unmodifiedSinceBound is the exclusive upper bound for a stored UpdatedAt that counts as
+// unmodified since since. UpdatedAt is stored as an RFC3339Nano string in UTC, so the
+// comparison is on strings: the bound is the start of the next second with all nine
+// fractional digits, which sorts after every time within since's second (".5Z" < ".000000001Z"
+// would fail without them) and before anything later.
+func unmodifiedSinceBound(since time.Time) string {
+	return since.UTC().Truncate(time.Second).Add(time.Second).Format("2006-01-02T15:04:05.000000000Z")
+}
+
+// update applies updates to an existing item, optionally under an extra condition
+func (r *GenericRepository) update(ctx context.Context, pk, sk string, updates map[string]interface{}, condition *expression.ConditionBuilder) error {
+	ctx, done := r.observe(ctx, "UpdateItem", pk, sk)
+	defer done()
+
 	// Add updated_at timestamp
-	updates["UpdatedAt"] = time.Now().UTC()
+	now := clock.Now()
+	updates["UpdatedAt"] = now
+	sealed, err := r.encryptUpdates(ctx, updates)
+	if err != nil {
+		return err
+	}
 
 	// Build update expression
 	update := expression.UpdateBuilder{}
-	for key, value := range updates {
+	for key, value := range sealed {
+		update = update.Set(expression.Name(key), expression.Value(value))
+	}
+
+	exists := expression.AttributeExists(expression.Name("PK"))
+	if condition != nil {
+		exists = exists.And(*condition)
+	}
+
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(exists).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.table(ctx)),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: pk},
+			"SK": &types.AttributeValueMemberS{Value: sk},
+		},
+		ExpressionAttributeNames:            expr.Names(),
+		ExpressionAttributeValues:           expr.Values(),
+		UpdateExpression:                    expr.Update(),
+		ConditionExpression:                 expr.Condition(),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		ReturnConsumedCapacity:              r.consumedCapacityMode(),
+	}
+	if r.logConflicts {
+		// UpdatedAt is always set, so the old attributes include the version we replaced
+		input.ReturnValues = types.ReturnValueUpdatedOld
+	}
+
+	output, err := r.client.UpdateItem(ctx, input)
+	if err != nil {
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			// The old item comes back only if it exists, so its absence means not found
+			if ccf.Item == nil {
+				return ErrNotFound
+			}
+			return ErrConditionFailed
+		}
+		return fmt.Errorf("failed to update item: %w", err)
+	}
+	r.logConsumedCapacity(ctx, "UpdateItem", output.ConsumedCapacity)
+	r.checkConflict(ctx, pk, sk, output.Attributes, now)
+
+	return nil
+}
+
+// Upsert sets specific attributes of an item, creating the item if it doesn't exist.
+// Unlike Update there is no existence condition, so it is idempotent and suited to
+// preference-style items. CreatedAt is only set when the item is first created.
+func (r *GenericRepository) Upsert(ctx context.Context, pk, sk string, updates map[string]interface{}) error {
+	ctx, done := r.observe(ctx, "UpdateItem", pk, sk)
+	defer done()
+
+	now := clock.Now()
+	updates["UpdatedAt"] = now
+	sealed, err := r.encryptUpdates(ctx, updates)
+	if err != nil {
+		return err
+	}
+
+	// Build update expression
+	update := expression.Set(expression.Name("CreatedAt"),
+		expression.IfNotExists(expression.Name("CreatedAt"), expression.Value(now)))
+	for key, value := range sealed {
 		update = update.Set(expression.Name(key), expression.Value(value))
 	}
 
@@ -143,7 +391,179 @@ func (r *GenericRepository) Update(ctx context.Context, pk, sk string, updates m
 	}
 
 	input := &dynamodb.UpdateItemInput{
-		TableName: aws.String(r.tableName),
+		TableName: aws.String(r.table(ctx)),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: pk},
+			"SK": &types.AttributeValueMemberS{Value: sk},
+		},
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+		ReturnConsumedCapacity:    r.consumedCapacityMode(),
+	}
+
+	output, err := r.client.UpdateItem(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to upsert item: %w", err)
+	}
+	r.logConsumedCapacity(ctx, "UpdateItem", output.ConsumedCapacity)
+
+	return nil
+}
+
+// IncrementBounds limits the value Increment may produce. Nil fields are unbounded.
+type IncrementBounds struct {
+	Min *int64 // e.g. 0 so a decrement can't go negative
+	Max *int64 // e.g. a capacity so an increment can't overshoot it
+}
+
+// Increment atomically adds amount (negative to decrement) to a numeric attribute
+// of an existing item and returns the new value. A missing attribute counts as 0.
+// With bounds, the add only happens if the result stays within them; otherwise the
+// item is left untouched and ErrBoundExceeded is returned.
+func (r *GenericRepository) Increment(ctx context.Context, pk, sk, attribute string, amount int64, bounds *IncrementBounds) (int64, error) {
+	ctx, done := r.observe(ctx, "UpdateItem", pk, sk)
+	defer done()
+
+	name := expression.Name(attribute)
+	update := expression.Set(name, expression.Plus(expression.IfNotExists(name, expression.Value(0)), expression.Value(amount)))
+
+	condition := expression.AttributeExists(expression.Name("PK"))
+	if bounds != nil {
+		condition = condition.And(incrementBoundsCondition(attribute, amount, bounds))
+	}
+
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.table(ctx)),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: pk},
+			"SK": &types.AttributeValueMemberS{Value: sk},
+		},
+		ExpressionAttributeNames:            expr.Names(),
+		ExpressionAttributeValues:           expr.Values(),
+		UpdateExpression:                    expr.Update(),
+		ConditionExpression:                 expr.Condition(),
+		ReturnValues:                        types.ReturnValueUpdatedNew,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		ReturnConsumedCapacity:              r.consumedCapacityMode(),
+	}
+
+	output, err := r.client.UpdateItem(ctx, input)
+	if err != nil {
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			// The old item comes back only if it exists, so its absence means not found
+			if ccf.Item == nil {
+				return 0, ErrNotFound
+			}
+			return 0, ErrBoundExceeded
+		}
+		return 0, fmt.Errorf("failed to increment item: %w", err)
+	}
+	r.logConsumedCapacity(ctx, "UpdateItem", output.ConsumedCapacity)
+
+	var value int64
+	if err := attributevalue.Unmarshal(output.Attributes[attribute], &value); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal %s: %w", attribute, err)
+	}
+	return value, nil
+}
+
+// incrementBoundsCondition builds the condition that current+amount stays within bounds.
+// It's written against the current value (current >= min-amount, current <= max-amount)
+// since condition expressions can't do arithmetic. A missing attribute is 0, so it
+// passes a bound only if amount alone satisfies it.
+func incrementBoundsCondition(attribute string, amount int64, bounds *IncrementBounds) expression.ConditionBuilder {
+	name := expression.Name(attribute)
+	var conditions []expression.ConditionBuilder
+
+	if bounds.Min != nil {
+		c := name.GreaterThanEqual(expression.Value(*bounds.Min - amount))
+		if amount >= *bounds.Min {
+			c = expression.Or(expression.AttributeNotExists(name), c)
+		}
+		conditions = append(conditions, c)
+	}
+	if bounds.Max != nil {
+		c := name.LessThanEqual(expression.Value(*bounds.Max - amount))
+		if amount <= *bounds.Max {
+			c = expression.Or(expression.AttributeNotExists(name), c)
+		}
+		conditions = append(conditions, c)
+	}
+
+	switch len(conditions) {
+	case 0:
+		return expression.AttributeExists(expression.Name("PK"))
+	case 1:
+		return conditions[0]
+	default:
+		return expression.And(conditions[0], conditions[1])
+	}
+}
+
+// Touch sets a single timestamp attribute to now without bumping UpdatedAt.
+// Used for activity tracking where the write must stay as small as possible.
+func (r *GenericRepository) Touch(ctx context.Context, pk, sk, attribute string) error {
+	ctx, done := r.observe(ctx, "UpdateItem", pk, sk)
+	defer done()
+
+	update := expression.Set(expression.Name(attribute), expression.Value(clock.Now()))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.table(ctx)),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: pk},
+			"SK": &types.AttributeValueMemberS{Value: sk},
+		},
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       aws.String("attribute_exists(PK)"),
+	}
+
+	_, err = r.client.UpdateItem(ctx, input)
+	if err != nil {
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to touch item: %w", err)
+	}
+
+	return nil
+}
+
+// SetIfMissing writes each attribute in values only where the item doesn't have it yet
+// (SET #a = if_not_exists(#a, :v)), so it is idempotent and never overwrites newer data.
+// Returns ErrNotFound if the item doesn't exist.
+func (r *GenericRepository) SetIfMissing(ctx context.Context, pk, sk string, values map[string]interface{}) error {
+	ctx, done := r.observe(ctx, "UpdateItem", pk, sk)
+	defer done()
+
+	update := expression.UpdateBuilder{}
+	for key, value := range values {
+		name := expression.Name(key)
+		update = update.Set(name, expression.IfNotExists(name, expression.Value(value)))
+	}
+
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.table(ctx)),
 		Key: map[string]types.AttributeValue{
 			"PK": &types.AttributeValueMemberS{Value: pk},
 			"SK": &types.AttributeValueMemberS{Value: sk},
@@ -168,8 +588,11 @@ func (r *GenericRepository) Update(ctx context.Context, pk, sk string, updates m
 
 // Delete removes an item from DynamoDB
 func (r *GenericRepository) Delete(ctx context.Context, pk, sk string) error {
+	ctx, done := r.observe(ctx, "DeleteItem", pk, sk)
+	defer done()
+
 	input := &dynamodb.DeleteItemInput{
-		TableName: aws.String(r.tableName),
+		TableName: aws.String(r.table(ctx)),
 		Key: map[string]types.AttributeValue{
 			"PK": &types.AttributeValueMemberS{Value: pk},
 			"SK": &types.AttributeValueMemberS{Value: sk},
@@ -189,8 +612,38 @@ func (r *GenericRepository) Delete(ctx context.Context, pk, sk string) error {
 	return nil
 }
 
+// DeleteIfExists removes an item if it is there and reports whether it was. Unlike
+// Delete it has no existence condition, so deleting a missing item is a no-op rather
+// than ErrNotFound; use it where a repeated delete (a retry, a cleanup job) is expected.
+// The deleted item is returned by DynamoDB to tell the two cases apart, at no extra RCU.
+func (r *GenericRepository) DeleteIfExists(ctx context.Context, pk, sk string) (existed bool, err error) {
+	ctx, done := r.observe(ctx, "DeleteItem", pk, sk)
+	defer done()
+
+	input := &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.table(ctx)),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: pk},
+			"SK": &types.AttributeValueMemberS{Value: sk},
+		},
+		ReturnValues:           types.ReturnValueAllOld,
+		ReturnConsumedCapacity: r.consumedCapacityMode(),
+	}
+
+	output, err := r.client.DeleteItem(ctx, input)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete item: %w", err)
+	}
+	r.logConsumedCapacity(ctx, "DeleteItem", output.ConsumedCapacity)
+
+	return len(output.Attributes) > 0, nil
+}
+
 // Query queries items by PK (and optionally SK prefix)
 func (r *GenericRepository) Query(ctx context.Context, pk string, skPrefix string, resultSlice interface{}) error {
+	ctx, done := r.observe(ctx, "Query", pk, skPrefix)
+	defer done()
+
 	var keyCondition expression.KeyConditionBuilder
 	
 	if skPrefix == "" {
@@ -208,141 +661,600 @@ func (r *GenericRepository) Query(ctx context.Context, pk string, skPrefix strin
 	}
 
 	input := &dynamodb.QueryInput{
-		TableName:                 aws.String(r.tableName),
+		TableName:                 aws.String(r.table(ctx)),
 		KeyConditionExpression:    expr.KeyCondition(),
 		ExpressionAttributeNames:  expr.Names(),
 		ExpressionAttributeValues: expr.Values(),
+		ReturnConsumedCapacity:    r.consumedCapacityMode(),
 	}
 
 	output, err := r.client.Query(ctx, input)
 	if err != nil {
 		return fmt.Errorf("failed to query items: %w", err)
 	}
+	r.logConsumedCapacity(ctx, "Query", output.ConsumedCapacity)
 
-	if err := attributevalue.UnmarshalListOfMaps(output.Items, resultSlice); err != nil {
+	if err := r.unmarshalItems(ctx, output.Items, resultSlice); err != nil {
 		return fmt.Errorf("failed to unmarshal items: %w", err)
 	}
 
 	return nil
 }
 
-// QueryByEntityType queries items by entity type using GSI1
-func (r *GenericRepository) QueryByEntityType(ctx context.Context, entityType string, resultSlice interface{}) error {
-	keyCondition := expression.Key("GSI1PK").Equal(expression.Value(entityType))
+// QueryItems is Query without the unmarshal step: it returns the raw items so the
+// caller can decode them one at a time with UnmarshalItems
+func (r *GenericRepository) QueryItems(ctx context.Context, pk string, skPrefix string) ([]map[string]types.AttributeValue, error) {
+	ctx, done := r.observe(ctx, "Query", pk, skPrefix)
+	defer done()
+
+	keyCondition := expression.Key("PK").Equal(expression.Value(pk))
+	if skPrefix != "" {
+		keyCondition = keyCondition.And(expression.Key("SK").BeginsWith(skPrefix))
+	}
 
 	expr, err := expression.NewBuilder().WithKeyCondition(keyCondition).Build()
 	if err != nil {
-		return fmt.Errorf("failed to build expression: %w", err)
+		return nil, fmt.Errorf("failed to build expression: %w", err)
 	}
 
 	input := &dynamodb.QueryInput{
-		TableName:                 aws.String(r.tableName),
-		IndexName:                 aws.String("GSI1"),
+		TableName:                 aws.String(r.table(ctx)),
 		KeyConditionExpression:    expr.KeyCondition(),
 		ExpressionAttributeNames:  expr.Names(),
 		ExpressionAttributeValues: expr.Values(),
+		ReturnConsumedCapacity:    r.consumedCapacityMode(),
 	}
 
 	output, err := r.client.Query(ctx, input)
 	if err != nil {
-		return fmt.Errorf("failed to query by entity type: %w", err)
+		return nil, fmt.Errorf("failed to query items: %w", err)
 	}
+	r.logConsumedCapacity(ctx, "Query", output.ConsumedCapacity)
 
-	if err := attributevalue.UnmarshalListOfMaps(output.Items, resultSlice); err != nil {
-		return fmt.Errorf("failed to unmarshal items: %w", err)
+	if err := r.encryptor.DecryptItems(ctx, output.Items...); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return output.Items, nil
 }
 
-// QueryWithFilter queries with additional filter conditions
-func (r *GenericRepository) QueryWithFilter(
-	ctx context.Context,
-	pk string,
-	skPrefix string,
-	filterCondition expression.ConditionBuilder,
-	resultSlice interface{},
-) error {
-	var keyCondition expression.KeyConditionBuilder
-	
-	if skPrefix == "" {
-		keyCondition = expression.Key("PK").Equal(expression.Value(pk))
-	} else {
-		keyCondition = expression.Key("PK").Equal(expression.Value(pk)).
-			And(expression.Key("SK").BeginsWith(skPrefix))
+// QueryPage fetches a single page of at most limit items with this PK (and optionally
+// SK prefix), resuming after startKey (nil for the first page). It returns the page's
+// LastEvaluatedKey, which is nil once there is nothing left to read. DynamoDB may
+// return a non-nil key on a page that happens to end exactly at the last item.
+//
+// With a projection only those attributes (plus PK and SK) are read, which cuts the
+// bytes returned but not the RCU: DynamoDB still reads whole items. Fields outside the
+// projection unmarshal to their zero values.
+func (r *GenericRepository) QueryPage(ctx context.Context, pk string, skPrefix string, limit int, startKey map[string]types.AttributeValue, resultSlice interface{}, projection ...string) (map[string]types.AttributeValue, error) {
+	ctx, done := r.observe(ctx, "Query", pk, skPrefix, attribute.Int("db.dynamodb.limit", limit))
+	defer done()
+
+	keyCondition := expression.Key("PK").Equal(expression.Value(pk))
+	if skPrefix != "" {
+		keyCondition = keyCondition.And(expression.Key("SK").BeginsWith(skPrefix))
 	}
 
-	expr, err := expression.NewBuilder().
-		WithKeyCondition(keyCondition).
-		WithFilter(filterCondition).
-		Build()
+	builder := expression.NewBuilder().WithKeyCondition(keyCondition)
+	if len(projection) > 0 {
+		names := expression.NamesList(expression.Name("PK"), expression.Name("SK"))
+		for _, name := range projection {
+			names = names.AddNames(expression.Name(name))
+		}
+		builder = builder.WithProjection(names)
+	}
+
+	expr, err := builder.Build()
 	if err != nil {
-		return fmt.Errorf("failed to build expression: %w", err)
+		return nil, fmt.Errorf("failed to build expression: %w", err)
 	}
 
 	input := &dynamodb.QueryInput{
-		TableName:                 aws.String(r.tableName),
+		TableName:                 aws.String(r.table(ctx)),
 		KeyConditionExpression:    expr.KeyCondition(),
-		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
 		ExpressionAttributeNames:  expr.Names(),
 		ExpressionAttributeValues: expr.Values(),
+		Limit:                     aws.Int32(int32(limit)),
+		ExclusiveStartKey:         startKey,
+		ReturnConsumedCapacity:    r.consumedCapacityMode(),
 	}
 
 	output, err := r.client.Query(ctx, input)
 	if err != nil {
-		return fmt.Errorf("failed to query with filter: %w", err)
+		return nil, fmt.Errorf("failed to query page: %w", err)
 	}
+	r.logConsumedCapacity(ctx, "Query", output.ConsumedCapacity)
 
-	if err := attributevalue.UnmarshalListOfMaps(output.Items, resultSlice); err != nil {
-		return fmt.Errorf("failed to unmarshal items: %w", err)
+	if len(projection) > 0 {
+		// Projected items lack attributes by design, so skip the schema-drift check
+		if err := r.encryptor.DecryptItems(ctx, output.Items...); err != nil {
+			return nil, err
+		}
+		err = attributevalue.UnmarshalListOfMaps(output.Items, resultSlice)
+	} else {
+		err = r.unmarshalItems(ctx, output.Items, resultSlice)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal items: %w", err)
 	}
 
-	return nil
+	return output.LastEvaluatedKey, nil
 }
 
-// BatchGet retrieves multiple items by their keys
-func (r *GenericRepository) BatchGet(ctx context.Context, keys []map[string]string, resultSlice interface{}) error {
-	if len(keys) == 0 {
-		return nil
-	}
+// Count returns how many items match PK (and optionally SK prefix) using Select=COUNT,
+// so no item data is transferred. Pages through results past the 1 MB query limit.
+func (r *GenericRepository) Count(ctx context.Context, pk string, skPrefix string) (int, error) {
+	return r.CountWithFilter(ctx, pk, skPrefix, expression.ConditionBuilder{})
+}
 
-	// Convert keys to DynamoDB format
-	dynamoKeys := make([]map[string]types.AttributeValue, len(keys))
-	for i, key := range keys {
-		dynamoKeys[i] = map[string]types.AttributeValue{
-			"PK": &types.AttributeValueMemberS{Value: key["PK"]},
-			"SK": &types.AttributeValueMemberS{Value: key["SK"]},
-		}
+// CountWithFilter is Count restricted to items matching filter (an unset builder counts everything).
+// Capacity: DynamoDB applies the filter after reading, so every item under the key condition
+// is billed even though only matches are counted. For hot paths prefer a sparse index item
+// (like FAV#) that the key condition alone can select.
+func (r *GenericRepository) CountWithFilter(ctx context.Context, pk string, skPrefix string, filter expression.ConditionBuilder) (int, error) {
+	ctx, done := r.observe(ctx, "Query COUNT", pk, skPrefix)
+	defer done()
+
+	keyCondition := expression.Key("PK").Equal(expression.Value(pk))
+	if skPrefix != "" {
+		keyCondition = keyCondition.And(expression.Key("SK").BeginsWith(skPrefix))
 	}
 
-	input := &dynamodb.BatchGetItemInput{
-		RequestItems: map[string]types.KeysAndAttributes{
-			r.tableName: {
-				Keys: dynamoKeys,
-			},
-		},
+	builder := expression.NewBuilder().WithKeyCondition(keyCondition)
+	if filter.IsSet() {
+		builder = builder.WithFilter(filter)
 	}
-
-	output, err := r.client.BatchGetItem(ctx, input)
+	expr, err := builder.Build()
 	if err != nil {
-		return fmt.Errorf("failed to batch get items: %w", err)
+		return 0, fmt.Errorf("failed to build expression: %w", err)
 	}
 
-	items := output.Responses[r.tableName]
-	if err := attributevalue.UnmarshalListOfMaps(items, resultSlice); err != nil {
-		return fmt.Errorf("failed to unmarshal items: %w", err)
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table(ctx)),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		Select:                    types.SelectCount,
+		ReturnConsumedCapacity:    r.consumedCapacityMode(),
+	}
+
+	count := 0
+	paginator := dynamodb.NewQueryPaginator(r.client, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count items: %w", err)
+		}
+		r.logConsumedCapacity(ctx, "Query COUNT", output.ConsumedCapacity)
+		count += int(output.Count)
+	}
+
+	return count, nil
+}
+
+// ItemError records why a single raw item couldn't be unmarshalled
+type ItemError struct {
+	Index int
+	PK    string
+	SK    string
+	Err   error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("item %d (PK=%s, SK=%s): %v", e.Index, e.PK, e.SK, e.Err)
+}
+
+func (e *ItemError) Unwrap() error { return e.Err }
+
+// UnmarshalItems decodes raw items one at a time. Items that fail to decode are
+// skipped and reported as *ItemError values joined into the returned error, so one
+// corrupt row doesn't cost the caller every other item.
+func UnmarshalItems[T any](items []map[string]types.AttributeValue) ([]T, error) {
+	results := make([]T, 0, len(items))
+	var errs []error
+
+	for i, item := range items {
+		var result T
+		if err := attributevalue.UnmarshalMap(item, &result); err != nil {
+			errs = append(errs, &ItemError{Index: i, PK: keyString(item, "PK"), SK: keyString(item, "SK"), Err: err})
+			continue
+		}
+		results = append(results, result)
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// keyString returns a string key attribute from a raw item, or "" if missing
+func keyString(item map[string]types.AttributeValue, name string) string {
+	if v, ok := item[name].(*types.AttributeValueMemberS); ok {
+		return v.Value
+	}
+	return ""
+}
+
+// QueryByEntityType queries items by entity type using GSI1
+func (r *GenericRepository) QueryByEntityType(ctx context.Context, entityType string, resultSlice interface{}) error {
+	keyCondition := expression.Key("GSI1PK").Equal(expression.Value(entityType))
+
+	if _, err := r.QueryIndex(ctx, "GSI1", keyCondition, QueryOptions{}, resultSlice); err != nil {
+		return fmt.Errorf("failed to query by entity type: %w", err)
+	}
+	return nil
+}
+
+// QueryByEntityTypeBetween queries GSI1 for an entity type whose GSI1SK lies between
+// fromSK and toSK (both inclusive), in sort key order. Every page is read, so a wide
+// range returns all of it rather than the first 1 MB.
+func (r *GenericRepository) QueryByEntityTypeBetween(ctx context.Context, entityType, fromSK, toSK string, resultSlice interface{}) error {
+	keyCondition := expression.Key("GSI1PK").Equal(expression.Value(entityType)).
+		And(expression.Key("GSI1SK").Between(expression.Value(fromSK), expression.Value(toSK)))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCondition).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	pager := &QueryPager{
+		repo:      r,
+		operation: "Query GSI1",
+		pk:        entityType,
+		paginator: dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+			TableName:                 aws.String(r.table(ctx)),
+			IndexName:                 aws.String("GSI1"),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			ReturnConsumedCapacity:    r.consumedCapacityMode(),
+		}),
+	}
+
+	var items []map[string]types.AttributeValue
+	for pager.HasMorePages() {
+		page, err := pager.nextItems(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to query by entity type: %w", err)
+		}
+		items = append(items, page...)
+	}
+
+	if err := r.unmarshalItems(ctx, items, resultSlice); err != nil {
+		return fmt.Errorf("failed to unmarshal items: %w", err)
+	}
+	return nil
+}
+
+// QueryPager walks a query one DynamoDB page at a time so large result sets can
+// be processed (or streamed) without buffering them all in memory.
+// Pages hold at most pagination.MaxPageSize items.
+type QueryPager struct {
+	repo      *GenericRepository
+	operation string
+	pk, sk    string // for the slow-query log
+	paginator *dynamodb.QueryPaginator
+}
+
+// HasMorePages reports whether another page can be fetched
+func (p *QueryPager) HasMorePages() bool {
+	return p.paginator.HasMorePages()
+}
+
+// NextPage fetches the next page and unmarshals it into resultSlice
+func (p *QueryPager) NextPage(ctx context.Context, resultSlice interface{}) error {
+	items, err := p.nextItems(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := p.repo.unmarshalItems(ctx, items, resultSlice); err != nil {
+		return fmt.Errorf("failed to unmarshal items: %w", err)
+	}
+
+	return nil
+}
+
+// nextItems fetches the next page as raw (still encrypted) items
+func (p *QueryPager) nextItems(ctx context.Context) ([]map[string]types.AttributeValue, error) {
+	ctx, done := p.repo.observe(ctx, p.operation, p.pk, p.sk)
+	defer done()
+
+	output, err := p.paginator.NextPage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query page: %w", err)
+	}
+	p.repo.logConsumedCapacity(ctx, p.operation, output.ConsumedCapacity)
+	return output.Items, nil
+}
+
+// QueryPages returns a pager over items with this PK (and optionally SK prefix)
+func (r *GenericRepository) QueryPages(ctx context.Context, pk string, skPrefix string) (*QueryPager, error) {
+	keyCondition := expression.Key("PK").Equal(expression.Value(pk))
+	if skPrefix != "" {
+		keyCondition = keyCondition.And(expression.Key("SK").BeginsWith(skPrefix))
+	}
+
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCondition).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table(ctx)),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		Limit:                     aws.Int32(int32(pagination.MaxPageSize())),
+		ReturnConsumedCapacity:    r.consumedCapacityMode(),
+	}
+
+	return &QueryPager{
+		repo:      r,
+		operation: "Query",
+		pk:        pk,
+		sk:        skPrefix,
+		paginator: dynamodb.NewQueryPaginator(r.client, input),
+	}, nil
+}
+
+// QueryByEntityTypePages returns a pager over all items of an entity type using GSI1
+func (r *GenericRepository) QueryByEntityTypePages(ctx context.Context, entityType string) (*QueryPager, error) {
+	keyCondition := expression.Key("GSI1PK").Equal(expression.Value(entityType))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCondition).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table(ctx)),
+		IndexName:                 aws.String("GSI1"),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		Limit:                     aws.Int32(int32(pagination.MaxPageSize())),
+		ReturnConsumedCapacity:    r.consumedCapacityMode(),
+	}
+
+	return &QueryPager{
+		repo:      r,
+		operation: "Query GSI1",
+		pk:        entityType,
+		paginator: dynamodb.NewQueryPaginator(r.client, input),
+	}, nil
+}
+
+// QueryWithFilter queries with additional filter conditions
+func (r *GenericRepository) QueryWithFilter(
+	ctx context.Context,
+	pk string,
+	skPrefix string,
+	filterCondition expression.ConditionBuilder,
+	resultSlice interface{},
+) error {
+	ctx, done := r.observe(ctx, "Query", pk, skPrefix)
+	defer done()
+
+	var keyCondition expression.KeyConditionBuilder
+	
+	if skPrefix == "" {
+		keyCondition = expression.Key("PK").Equal(expression.Value(pk))
+	} else {
+		keyCondition = expression.Key("PK").Equal(expression.Value(pk)).
+			And(expression.Key("SK").BeginsWith(skPrefix))
+	}
+
+	expr, err := expression.NewBuilder().
+		WithKeyCondition(keyCondition).
+		WithFilter(filterCondition).
+		Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table(ctx)),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ReturnConsumedCapacity:    r.consumedCapacityMode(),
+	}
+
+	output, err := r.client.Query(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to query with filter: %w", err)
+	}
+	r.logConsumedCapacity(ctx, "Query", output.ConsumedCapacity)
+
+	if err := r.unmarshalItems(ctx, output.Items, resultSlice); err != nil {
+		return fmt.Errorf("failed to unmarshal items: %w", err)
+	}
+
+	return nil
+}
+
+// DynamoDB BatchGetItem limits
+const (
+	batchGetMaxKeys     = 100 // keys per BatchGetItem request
+	batchGetConcurrency = 4   // chunks fetched in parallel
+	batchGetMaxAttempts = 5   // attempts per chunk while UnprocessedKeys remain
+)
+
+// Scan walks the whole table page by page, calling fn with each page of raw items as
+// stored (designated attributes stay encrypted). An unset filter returns every item.
+// pageSize caps items read per request, keeping each batch's capacity burst small.
+// For maintenance jobs only: a scan reads (and bills) every item in the table.
+func (r *GenericRepository) Scan(ctx context.Context, filter expression.ConditionBuilder, pageSize int, fn func(items []map[string]types.AttributeValue) error) error {
+	input := &dynamodb.ScanInput{
+		TableName:              aws.String(r.table(ctx)),
+		ReturnConsumedCapacity: r.consumedCapacityMode(),
+	}
+	if pageSize > 0 {
+		input.Limit = aws.Int32(int32(pageSize))
+	}
+	if filter.IsSet() {
+		expr, err := expression.NewBuilder().WithFilter(filter).Build()
+		if err != nil {
+			return fmt.Errorf("failed to build expression: %w", err)
+		}
+		input.FilterExpression = expr.Filter()
+		input.ExpressionAttributeNames = expr.Names()
+		input.ExpressionAttributeValues = expr.Values()
+	}
+
+	paginator := dynamodb.NewScanPaginator(r.client, input)
+	for paginator.HasMorePages() {
+		output, err := r.scanPage(ctx, paginator)
+		if err != nil {
+			return err
+		}
+		if err := fn(output.Items); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scanPage fetches one Scan page inside its own span
+func (r *GenericRepository) scanPage(ctx context.Context, paginator *dynamodb.ScanPaginator) (*dynamodb.ScanOutput, error) {
+	ctx, done := r.observe(ctx, "Scan", "", "")
+	defer done()
+
+	output, err := paginator.NextPage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan table: %w", err)
+	}
+	r.logConsumedCapacity(ctx, "Scan", output.ConsumedCapacity)
+	return output, nil
+}
+
+// BatchGet retrieves multiple items by their keys
+// Keys are split into chunks of 100 (the BatchGetItem limit) fetched with bounded
+// concurrency; UnprocessedKeys are retried with backoff. Missing items are skipped,
+// and result order is not guaranteed to match keys.
+func (r *GenericRepository) BatchGet(ctx context.Context, keys []map[string]string, resultSlice interface{}) error {
+	ctx, done := r.observe(ctx, "BatchGetItem", "", "", attribute.Int("db.dynamodb.item_count", len(keys)))
+	defer done()
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	// Convert keys to DynamoDB format
+	dynamoKeys := make([]map[string]types.AttributeValue, len(keys))
+	for i, key := range keys {
+		dynamoKeys[i] = map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: key["PK"]},
+			"SK": &types.AttributeValueMemberS{Value: key["SK"]},
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		items    []map[string]types.AttributeValue
+		firstErr error
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, batchGetConcurrency)
+	)
+
+	for start := 0; start < len(dynamoKeys); start += batchGetMaxKeys {
+		end := start + batchGetMaxKeys
+		if end > len(dynamoKeys) {
+			end = len(dynamoKeys)
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(chunk []map[string]types.AttributeValue) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			chunkItems, err := r.batchGetChunk(ctx, chunk)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			items = append(items, chunkItems...)
+		}(dynamoKeys[start:end])
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	if err := r.unmarshalItems(ctx, items, resultSlice); err != nil {
+		return fmt.Errorf("failed to unmarshal items: %w", err)
 	}
 
 	return nil
 }
 
+// batchGetChunk fetches up to 100 keys, retrying UnprocessedKeys with exponential backoff
+func (r *GenericRepository) batchGetChunk(ctx context.Context, keys []map[string]types.AttributeValue) ([]map[string]types.AttributeValue, error) {
+	var items []map[string]types.AttributeValue
+	table := r.table(ctx)
+	backoff := 50 * time.Millisecond
+
+	for attempt := 1; len(keys) > 0; attempt++ {
+		input := &dynamodb.BatchGetItemInput{
+			RequestItems: map[string]types.KeysAndAttributes{
+				table: {
+					Keys: keys,
+				},
+			},
+			ReturnConsumedCapacity: r.consumedCapacityMode(),
+		}
+
+		output, err := r.client.BatchGetItem(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to batch get items: %w", err)
+		}
+		for i := range output.ConsumedCapacity {
+			r.logConsumedCapacity(ctx, "BatchGetItem", &output.ConsumedCapacity[i])
+		}
+
+		items = append(items, output.Responses[table]...)
+		keys = output.UnprocessedKeys[table].Keys
+		if len(keys) == 0 {
+			break
+		}
+
+		if attempt == batchGetMaxAttempts {
+			return nil, fmt.Errorf("failed to batch get items: %d keys still unprocessed after %d attempts", len(keys), attempt)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	return items, nil
+}
+
 // BatchWrite performs batch write operations (Put/Delete)
+// UnprocessedItems are retried with backoff; anything that still can't be written
+// comes back as a *BatchWriteError listing exactly those keys.
 func (r *GenericRepository) BatchWrite(ctx context.Context, putItems []BaseModel, deleteKeys []map[string]string) error {
+	ctx, done := r.observe(ctx, "BatchWriteItem", "", "", attribute.Int("db.dynamodb.item_count", len(putItems)+len(deleteKeys)))
+	defer done()
+
 	writeRequests := make([]types.WriteRequest, 0)
 
 	// Add put requests
 	for _, item := range putItems {
-		av, err := attributevalue.MarshalMap(item)
+		av, err := r.marshalItem(ctx, item)
 		if err != nil {
 			return fmt.Errorf("failed to marshal item: %w", err)
 		}
@@ -366,53 +1278,228 @@ func (r *GenericRepository) BatchWrite(ctx context.Context, putItems []BaseModel
 		})
 	}
 
-	// DynamoDB batch write limit is 25 items
-	for i := 0; i < len(writeRequests); i += 25 {
-		end := i + 25
+	// DynamoDB batch write limit is 25 items. A failed chunk doesn't stop the
+	// others; every item that couldn't be written is reported in a BatchWriteError.
+	var failed []types.WriteRequest
+	var lastErr error
+	for i := 0; i < len(writeRequests); i += batchWriteMaxItems {
+		end := i + batchWriteMaxItems
 		if end > len(writeRequests) {
 			end = len(writeRequests)
 		}
 
-		batch := writeRequests[i:end]
+		unprocessed, err := r.batchWriteChunk(ctx, writeRequests[i:end])
+		if err != nil {
+			lastErr = err
+		}
+		failed = append(failed, unprocessed...)
+	}
+
+	if len(failed) > 0 {
+		keys := make([]map[string]string, len(failed))
+		for i, req := range failed {
+			keys[i] = writeRequestKey(req)
+		}
+		return &BatchWriteError{Keys: keys, Err: lastErr}
+	}
+
+	return nil
+}
+
+// DynamoDB BatchWriteItem limits
+const (
+	batchWriteMaxItems    = 25 // requests per BatchWriteItem call
+	batchWriteMaxAttempts = 5  // attempts per chunk while UnprocessedItems remain
+)
+
+// BatchWriteError reports the items a BatchWrite could not write; all other items
+// were written. Err is the last request error, or nil if the failed items were only
+// left unprocessed (throttled) after every retry.
+type BatchWriteError struct {
+	Keys []map[string]string // PK/SK of each failed put or delete
+	Err  error
+}
+
+func (e *BatchWriteError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("failed to batch write %d items: %v", len(e.Keys), e.Err)
+	}
+	return fmt.Sprintf("failed to batch write items: %d still unprocessed after %d attempts", len(e.Keys), batchWriteMaxAttempts)
+}
+
+func (e *BatchWriteError) Unwrap() error {
+	return e.Err
+}
+
+// batchWriteChunk writes up to 25 requests, retrying UnprocessedItems with exponential
+// backoff. It returns the requests that were not written: all of them if a call fails,
+// or whatever is still unprocessed after the last attempt.
+func (r *GenericRepository) batchWriteChunk(ctx context.Context, requests []types.WriteRequest) ([]types.WriteRequest, error) {
+	table := r.table(ctx)
+	backoff := 50 * time.Millisecond
+
+	for attempt := 1; len(requests) > 0; attempt++ {
 		input := &dynamodb.BatchWriteItemInput{
 			RequestItems: map[string][]types.WriteRequest{
-				r.tableName: batch,
+				table: requests,
 			},
+			ReturnConsumedCapacity: r.consumedCapacityMode(),
 		}
 
-		_, err := r.client.BatchWriteItem(ctx, input)
+		output, err := r.client.BatchWriteItem(ctx, input)
 		if err != nil {
-			return fmt.Errorf("failed to batch write items: %w", err)
+			return requests, fmt.Errorf("failed to batch write items: %w", err)
+		}
+		for i := range output.ConsumedCapacity {
+			r.logConsumedCapacity(ctx, "BatchWriteItem", &output.ConsumedCapacity[i])
 		}
+
+		requests = output.UnprocessedItems[table]
+		if len(requests) == 0 || attempt == batchWriteMaxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return requests, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
 	}
 
-	return nil
+	return requests, nil
+}
+
+// writeRequestKey returns the PK/SK a put or delete request targets
+func writeRequestKey(req types.WriteRequest) map[string]string {
+	item := map[string]types.AttributeValue{}
+	if req.PutRequest != nil {
+		item = req.PutRequest.Item
+	} else if req.DeleteRequest != nil {
+		item = req.DeleteRequest.Key
+	}
+
+	key := make(map[string]string, 2)
+	for _, name := range []string{"PK", "SK"} {
+		if v, ok := item[name].(*types.AttributeValueMemberS); ok {
+			key[name] = v.Value
+		}
+	}
+	return key
 }
 
-// Transaction performs a transactional write
+// Transaction performs a transactional write of bare puts and deletes.
+// Use TransactWrite when the transaction also needs conditional updates or checks.
 func (r *GenericRepository) Transaction(ctx context.Context, puts []BaseModel, deletes []map[string]string) error {
-	transactItems := make([]types.TransactWriteItem, 0)
+	return r.TransactWrite(ctx, TxItems{Puts: puts, Deletes: deletes})
+}
+
+// TxUpdate is an update inside a TransactWrite. If Condition is set and doesn't hold,
+// the whole transaction is cancelled. UpdatedAt is not stamped (unlike Update), so
+// bookkeeping such as counters doesn't look like an edit of the item.
+type TxUpdate struct {
+	PK, SK    string
+	Update    expression.UpdateBuilder
+	Condition *expression.ConditionBuilder
+}
+
+// TxCheck asserts a condition on an item the transaction doesn't otherwise write
+type TxCheck struct {
+	PK, SK    string
+	Condition expression.ConditionBuilder
+}
+
+// TxPut is a put inside a TransactWrite that cancels the whole transaction unless
+// Condition holds for the stored item, e.g. replacing a contact only if its UpdatedAt is
+// still the one that was read. Unlike a TxUpdate it goes through field encryption.
+type TxPut struct {
+	Item      BaseModel
+	Condition expression.ConditionBuilder
+}
+
+// TxItems are the writes a TransactWrite applies all-or-nothing
+type TxItems struct {
+	Puts            []BaseModel
+	Creates         []BaseModel // Puts that cancel the transaction if the item already exists
+	ConditionalPuts []TxPut
+	Deletes         []map[string]string
+	Updates         []TxUpdate
+	Checks          []TxCheck
+}
+
+// TransactWrite applies puts, creates, conditional puts, deletes, conditional updates and
+// condition checks in one DynamoDB transaction. If any condition fails nothing is written
+// and the error wraps ErrConditionFailed.
+func (r *GenericRepository) TransactWrite(ctx context.Context, tx TxItems) error {
+	count := len(tx.Puts) + len(tx.Creates) + len(tx.ConditionalPuts) + len(tx.Deletes) + len(tx.Updates) + len(tx.Checks)
+	ctx, done := r.observe(ctx, "TransactWriteItems", "", "", attribute.Int("db.dynamodb.item_count", count))
+	defer done()
+
+	transactItems := make([]types.TransactWriteItem, 0, count)
 
 	// Add put transactions
-	for _, item := range puts {
-		av, err := attributevalue.MarshalMap(item)
+	for _, item := range tx.Puts {
+		av, err := r.marshalItem(ctx, item)
 		if err != nil {
 			return fmt.Errorf("failed to marshal item: %w", err)
 		}
 
 		transactItems = append(transactItems, types.TransactWriteItem{
 			Put: &types.Put{
-				TableName: aws.String(r.tableName),
+				TableName: aws.String(r.table(ctx)),
 				Item:      av,
 			},
 		})
 	}
 
+	// Add create transactions (puts that must not overwrite)
+	for _, item := range tx.Creates {
+		av, err := r.marshalItem(ctx, item)
+		if err != nil {
+			return fmt.Errorf("failed to marshal item: %w", err)
+		}
+		expr, err := expression.NewBuilder().WithCondition(expression.AttributeNotExists(expression.Name("PK"))).Build()
+		if err != nil {
+			return fmt.Errorf("failed to build expression: %w", err)
+		}
+
+		transactItems = append(transactItems, types.TransactWriteItem{
+			Put: &types.Put{
+				TableName:                aws.String(r.table(ctx)),
+				Item:                     av,
+				ExpressionAttributeNames: expr.Names(),
+				ConditionExpression:      expr.Condition(),
+			},
+		})
+	}
+
+	// Add conditional put transactions
+	for _, put := range tx.ConditionalPuts {
+		av, err := r.marshalItem(ctx, put.Item)
+		if err != nil {
+			return fmt.Errorf("failed to marshal item: %w", err)
+		}
+		expr, err := expression.NewBuilder().WithCondition(put.Condition).Build()
+		if err != nil {
+			return fmt.Errorf("failed to build expression: %w", err)
+		}
+
+		transactItems = append(transactItems, types.TransactWriteItem{
+			Put: &types.Put{
+				TableName:                 aws.String(r.table(ctx)),
+				Item:                      av,
+				ExpressionAttributeNames:  expr.Names(),
+				ExpressionAttributeValues: expr.Values(),
+				ConditionExpression:       expr.Condition(),
+			},
+		})
+	}
+
 	// Add delete transactions
-	for _, key := range deletes {
+	for _, key := range tx.Deletes {
 		transactItems = append(transactItems, types.TransactWriteItem{
 			Delete: &types.Delete{
-				TableName: aws.String(r.tableName),
+				TableName: aws.String(r.table(ctx)),
 				Key: map[string]types.AttributeValue{
 					"PK": &types.AttributeValueMemberS{Value: key["PK"]},
 					"SK": &types.AttributeValueMemberS{Value: key["SK"]},
@@ -421,14 +1508,254 @@ func (r *GenericRepository) Transaction(ctx context.Context, puts []BaseModel, d
 		})
 	}
 
+	// Add update transactions
+	for _, u := range tx.Updates {
+		builder := expression.NewBuilder().WithUpdate(u.Update)
+		if u.Condition != nil {
+			builder = builder.WithCondition(*u.Condition)
+		}
+		expr, err := builder.Build()
+		if err != nil {
+			return fmt.Errorf("failed to build expression: %w", err)
+		}
+
+		transactItems = append(transactItems, types.TransactWriteItem{
+			Update: &types.Update{
+				TableName: aws.String(r.table(ctx)),
+				Key: map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: u.PK},
+					"SK": &types.AttributeValueMemberS{Value: u.SK},
+				},
+				ExpressionAttributeNames:  expr.Names(),
+				ExpressionAttributeValues: expr.Values(),
+				UpdateExpression:          expr.Update(),
+				ConditionExpression:       expr.Condition(),
+			},
+		})
+	}
+
+	// Add condition checks
+	for _, check := range tx.Checks {
+		expr, err := expression.NewBuilder().WithCondition(check.Condition).Build()
+		if err != nil {
+			return fmt.Errorf("failed to build expression: %w", err)
+		}
+
+		transactItems = append(transactItems, types.TransactWriteItem{
+			ConditionCheck: &types.ConditionCheck{
+				TableName: aws.String(r.table(ctx)),
+				Key: map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: check.PK},
+					"SK": &types.AttributeValueMemberS{Value: check.SK},
+				},
+				ExpressionAttributeNames:  expr.Names(),
+				ExpressionAttributeValues: expr.Values(),
+				ConditionExpression:       expr.Condition(),
+			},
+		})
+	}
+
 	input := &dynamodb.TransactWriteItemsInput{
 		TransactItems: transactItems,
 	}
 
 	_, err := r.client.TransactWriteItems(ctx, input)
 	if err != nil {
+		var tce *types.TransactionCanceledException
+		if errors.As(err, &tce) {
+			for i, reason := range tce.CancellationReasons {
+				if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+					return fmt.Errorf("%w: transaction item %d", ErrConditionFailed, i)
+				}
+			}
+		}
+		return fmt.Errorf("failed to execute transaction: %w", err)
+	}
+
+	return nil
+}
+
+// TransactUpdate applies the same attribute updates to every key in one transaction.
+// Every item must already exist; if any doesn't (or any write fails) nothing is applied.
+// Callers with more than MaxTransactWriteItems keys must chunk.
+func (r *GenericRepository) TransactUpdate(ctx context.Context, keys []map[string]string, updates map[string]interface{}) error {
+	ctx, done := r.observe(ctx, "TransactWriteItems", "", "", attribute.Int("db.dynamodb.item_count", len(keys)))
+	defer done()
+
+	if len(keys) == 0 {
+		return nil
+	}
+	if len(keys) > MaxTransactWriteItems {
+		return &TransactionTooLargeError{Items: len(keys), Limit: MaxTransactWriteItems}
+	}
+
+	// Add updated_at timestamp
+	updates["UpdatedAt"] = clock.Now()
+	sealed, err := r.encryptUpdates(ctx, updates)
+	if err != nil {
+		return err
+	}
+
+	// Build update expression (shared by every item)
+	update := expression.UpdateBuilder{}
+	for key, value := range sealed {
+		update = update.Set(expression.Name(key), expression.Value(value))
+	}
+
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	transactItems := make([]types.TransactWriteItem, 0, len(keys))
+	for _, key := range keys {
+		transactItems = append(transactItems, types.TransactWriteItem{
+			Update: &types.Update{
+				TableName: aws.String(r.table(ctx)),
+				Key: map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: key["PK"]},
+					"SK": &types.AttributeValueMemberS{Value: key["SK"]},
+				},
+				ExpressionAttributeNames:  expr.Names(),
+				ExpressionAttributeValues: expr.Values(),
+				UpdateExpression:          expr.Update(),
+				ConditionExpression:       aws.String("attribute_exists(PK)"),
+			},
+		})
+	}
+
+	input := &dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	}
+
+	_, err = r.client.TransactWriteItems(ctx, input)
+	if err != nil {
+		var tce *types.TransactionCanceledException
+		if errors.As(err, &tce) {
+			for _, reason := range tce.CancellationReasons {
+				if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+					return ErrNotFound
+				}
+			}
+		}
 		return fmt.Errorf("failed to execute transaction: %w", err)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// consumedCapacityMode returns the ReturnConsumedCapacity setting for a request
+func (r *GenericRepository) consumedCapacityMode() types.ReturnConsumedCapacity {
+	if r.returnConsumedCapacity {
+		return types.ReturnConsumedCapacityTotal
+	}
+	return types.ReturnConsumedCapacityNone
+}
+
+// logConsumedCapacity logs the capacity a call consumed when reporting is enabled
+func (r *GenericRepository) logConsumedCapacity(ctx context.Context, operation string, cc *types.ConsumedCapacity) {
+	if !r.returnConsumedCapacity || cc == nil {
+		return
+	}
+	requestid.Logf(ctx, "DynamoDB capacity: op=%s table=%s units=%.1f",
+		operation, r.table(ctx), aws.ToFloat64(cc.CapacityUnits))
+}
+
+// observe wraps one DynamoDB call in a client span (table, operation and key as
+// attributes) and returns a func that ends it and applies the slow-query log:
+//
+//	ctx, done := r.observe(ctx, "GetItem", pk, sk)
+//	defer done()
+func (r *GenericRepository) observe(ctx context.Context, operation, pk, sk string, attrs ...attribute.KeyValue) (context.Context, func()) {
+	start := time.Now()
+	attrs = append(attrs,
+		attribute.String("db.system", "dynamodb"),
+		attribute.String("db.operation", operation),
+		attribute.StringSlice("aws.dynamodb.table_names", []string{r.table(ctx)}),
+		attribute.String("db.dynamodb.pk", pk),
+		attribute.String("db.dynamodb.sk", sk),
+	)
+	ctx, span := tracing.Start(ctx, "DynamoDB "+operation,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+
+	return ctx, func() {
+		span.End()
+		r.logSlowQuery(ctx, operation, pk, sk, start)
+	}
+}
+
+// logSlowQuery warns when a call started at start exceeded the slow-query threshold
+func (r *GenericRepository) logSlowQuery(ctx context.Context, operation, pk, sk string, start time.Time) {
+	if r.slowQueryThreshold <= 0 {
+		return
+	}
+	if elapsed := time.Since(start); elapsed > r.slowQueryThreshold {
+		requestid.Logf(ctx, "Warning: slow DynamoDB call: op=%s table=%s pk=%q sk=%q duration=%s",
+			operation, r.table(ctx), pk, sk, elapsed.Round(time.Millisecond))
+	}
+}
+
+// marshalItem converts a model to attribute values, encrypting designated attributes.
+// Every write marshals through here, so it is also where item sizes are measured.
+func (r *GenericRepository) marshalItem(ctx context.Context, item BaseModel) (map[string]types.AttributeValue, error) {
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.encryptor.EncryptItem(ctx, av); err != nil {
+		return nil, err
+	}
+	r.recordItemSize(ctx, item, av)
+	return av, nil
+}
+
+// unmarshalItem decrypts designated attributes of a fetched item and decodes it into result
+func (r *GenericRepository) unmarshalItem(ctx context.Context, item map[string]types.AttributeValue, result interface{}) error {
+	if err := r.encryptor.DecryptItems(ctx, item); err != nil {
+		return err
+	}
+	r.checkAttributes(ctx, []map[string]types.AttributeValue{item}, result)
+	return attributevalue.UnmarshalMap(item, result)
+}
+
+// unmarshalItems is unmarshalItem for a page of items
+func (r *GenericRepository) unmarshalItems(ctx context.Context, items []map[string]types.AttributeValue, resultSlice interface{}) error {
+	if err := r.encryptor.DecryptItems(ctx, items...); err != nil {
+		return err
+	}
+	r.checkAttributes(ctx, items, resultSlice)
+	return attributevalue.UnmarshalListOfMaps(items, resultSlice)
+}
+
+// encryptUpdates returns updates with designated attributes sealed. The caller's map is
+// left alone since services reuse it to patch cached copies.
+func (r *GenericRepository) encryptUpdates(ctx context.Context, updates map[string]interface{}) (map[string]interface{}, error) {
+	if r.encryptor == nil {
+		return updates, nil
+	}
+	var sealed map[string]interface{}
+	for key, value := range updates {
+		if !r.encryptor.Encrypts(key) {
+			continue
+		}
+		av, err := attributevalue.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", key, err)
+		}
+		encrypted, err := r.encryptor.EncryptValue(ctx, av)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt %s: %w", key, err)
+		}
+		if sealed == nil {
+			sealed = make(map[string]interface{}, len(updates))
+			for k, v := range updates {
+				sealed[k] = v
+			}
+		}
+		sealed[key] = encrypted
+	}
+	if sealed == nil {
+		return updates, nil
+	}
+	return sealed, nil
+}
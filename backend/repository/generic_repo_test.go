@@ -0,0 +1,1251 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/redis/go-redis/v9"
+	"hub-control-plane/backend/requestid"
+	"hub-control-plane/backend/tenant"
+)
+
+// fakeCounterTable is a one-item DynamoDB endpoint that understands just enough of
+// UpdateItem to exercise Increment: it evaluates the condition expression against the
+// stored counter and only applies the update when the condition holds, like DynamoDB.
+type fakeCounterTable struct {
+	exists  bool
+	counter *int64 // nil = attribute not set
+}
+
+type attributeValueJSON struct {
+	S string `json:",omitempty"`
+	N string `json:",omitempty"`
+}
+
+type updateItemRequest struct {
+	UpdateExpression          string
+	ConditionExpression       string
+	ExpressionAttributeNames  map[string]string
+	ExpressionAttributeValues map[string]attributeValueJSON
+}
+
+func (f *fakeCounterTable) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if target := req.Header.Get("X-Amz-Target"); target != "DynamoDB_20120810.UpdateItem" {
+		http.Error(w, "unsupported operation "+target, http.StatusBadRequest)
+		return
+	}
+
+	var in updateItemRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	if !f.evaluate(in) {
+		w.WriteHeader(http.StatusBadRequest)
+		body := map[string]interface{}{
+			"__type":  "com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException",
+			"message": "The conditional request failed",
+		}
+		if f.exists {
+			body["Item"] = f.item()
+		}
+		json.NewEncoder(w).Encode(body)
+		return
+	}
+
+	// Increment always issues SET #n = if_not_exists(#n, :zero) + :amount
+	amount := f.updateAmount(in)
+	current := int64(0)
+	if f.counter != nil {
+		current = *f.counter
+	}
+	next := current + amount
+	f.counter = &next
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"Attributes": map[string]attributeValueJSON{"Count": {N: strconv.FormatInt(next, 10)}},
+	})
+}
+
+func (f *fakeCounterTable) item() map[string]attributeValueJSON {
+	item := map[string]attributeValueJSON{"PK": {S: "COUNTER#1"}, "SK": {S: "METADATA"}}
+	if f.counter != nil {
+		item["Count"] = attributeValueJSON{N: strconv.FormatInt(*f.counter, 10)}
+	}
+	return item
+}
+
+// updateAmount returns the value added by the "+ :amount" in the update expression
+func (f *fakeCounterTable) updateAmount(in updateItemRequest) int64 {
+	_, placeholder, _ := strings.Cut(in.UpdateExpression, "+")
+	n, _ := strconv.ParseInt(in.ExpressionAttributeValues[strings.TrimSpace(placeholder)].N, 10, 64)
+	return n
+}
+
+// evaluate handles the subset of condition syntax expression.Builder emits for
+// Increment: parentheses, AND/OR, attribute_exists/attribute_not_exists, >= and <=
+func (f *fakeCounterTable) evaluate(in updateItemRequest) bool {
+	if in.ConditionExpression == "" {
+		return true
+	}
+	replacer := strings.NewReplacer("(", " ( ", ")", " ) ")
+	tokens := strings.Fields(replacer.Replace(in.ConditionExpression))
+	pos := 0
+
+	value := func(token string) (int64, bool) {
+		if strings.HasPrefix(token, ":") {
+			n, _ := strconv.ParseInt(in.ExpressionAttributeValues[token].N, 10, 64)
+			return n, true
+		}
+		if in.ExpressionAttributeNames[token] == "Count" && f.counter != nil {
+			return *f.counter, true
+		}
+		return 0, false
+	}
+	has := func(token string) bool {
+		switch in.ExpressionAttributeNames[token] {
+		case "PK":
+			return f.exists
+		case "Count":
+			return f.exists && f.counter != nil
+		}
+		return false
+	}
+
+	var parseOr func() bool
+	parsePrimary := func() bool {
+		switch tok := tokens[pos]; tok {
+		case "(":
+			pos++
+			v := parseOr()
+			pos++ // ")"
+			return v
+		case "attribute_exists", "attribute_not_exists":
+			name := tokens[pos+2]
+			pos += 4 // fn ( name )
+			if tok == "attribute_exists" {
+				return has(name)
+			}
+			return !has(name)
+		default:
+			left, lok := value(tok)
+			op := tokens[pos+1]
+			right, rok := value(tokens[pos+2])
+			pos += 3
+			if !lok || !rok {
+				return false
+			}
+			if op == ">=" {
+				return left >= right
+			}
+			return left <= right
+		}
+	}
+	parseAnd := func() bool {
+		v := parsePrimary()
+		for pos < len(tokens) && tokens[pos] == "AND" {
+			pos++
+			v = parsePrimary() && v
+		}
+		return v
+	}
+	parseOr = func() bool {
+		v := parseAnd()
+		for pos < len(tokens) && tokens[pos] == "OR" {
+			pos++
+			v = parseAnd() || v
+		}
+		return v
+	}
+	return parseOr()
+}
+
+func newCounterRepo(t *testing.T, table *fakeCounterTable) *GenericRepository {
+	t.Helper()
+	srv := httptest.NewServer(table)
+	t.Cleanup(srv.Close)
+
+	return NewGenericRepository(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(srv.URL),
+	}, "test-table")
+}
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func TestIncrement_DecrementBelowZeroRejected(t *testing.T) {
+	table := &fakeCounterTable{exists: true, counter: int64Ptr(1)}
+	repo := newCounterRepo(t, table)
+	ctx := context.Background()
+	bounds := &IncrementBounds{Min: int64Ptr(0)}
+
+	got, err := repo.Increment(ctx, "COUNTER#1", "METADATA", "Count", -1, bounds)
+	if err != nil {
+		t.Fatalf("first decrement: %v", err)
+	}
+	if got != 0 {
+		t.Errorf("after first decrement = %d, want 0", got)
+	}
+
+	_, err = repo.Increment(ctx, "COUNTER#1", "METADATA", "Count", -1, bounds)
+	if !errors.Is(err, ErrBoundExceeded) {
+		t.Fatalf("second decrement err = %v, want ErrBoundExceeded", err)
+	}
+	if *table.counter != 0 {
+		t.Errorf("counter = %d after rejected decrement, want 0", *table.counter)
+	}
+}
+
+func TestIncrement_Bounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		counter *int64
+		amount  int64
+		bounds  *IncrementBounds
+		wantErr error
+		want    int64
+	}{
+		{"unbounded", int64Ptr(5), 10, nil, nil, 15},
+		{"within max", int64Ptr(5), 5, &IncrementBounds{Max: int64Ptr(10)}, nil, 10},
+		{"over max", int64Ptr(5), 6, &IncrementBounds{Max: int64Ptr(10)}, ErrBoundExceeded, 5},
+		{"missing attribute counts as zero", nil, 3, &IncrementBounds{Min: int64Ptr(0), Max: int64Ptr(3)}, nil, 3},
+		{"missing attribute below min", nil, -1, &IncrementBounds{Min: int64Ptr(0)}, ErrBoundExceeded, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := &fakeCounterTable{exists: true, counter: tt.counter}
+			repo := newCounterRepo(t, table)
+
+			got, err := repo.Increment(context.Background(), "COUNTER#1", "METADATA", "Count", tt.amount, tt.bounds)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if table.counter != nil && *table.counter != tt.want {
+					t.Errorf("counter changed to %d on rejected increment", *table.counter)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Increment = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIncrement_MissingItem(t *testing.T) {
+	repo := newCounterRepo(t, &fakeCounterTable{})
+
+	_, err := repo.Increment(context.Background(), "COUNTER#1", "METADATA", "Count", 1, nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLogSlowQuery(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	repo := &GenericRepository{tableName: "test-table"}
+	ctx := requestid.WithID(context.Background(), "req-1")
+
+	repo.logSlowQuery(ctx, "GetItem", "USER#1", "METADATA", time.Now().Add(-time.Second))
+	if buf.Len() != 0 {
+		t.Fatalf("logged with threshold disabled: %s", buf.String())
+	}
+
+	repo.SetSlowQueryThreshold(200 * time.Millisecond)
+	repo.logSlowQuery(ctx, "GetItem", "USER#1", "METADATA", time.Now())
+	if buf.Len() != 0 {
+		t.Fatalf("logged a fast call: %s", buf.String())
+	}
+
+	repo.logSlowQuery(ctx, "GetItem", "USER#1", "METADATA", time.Now().Add(-time.Second))
+	for _, want := range []string{"[req=req-1]", "op=GetItem", `pk="USER#1"`, `sk="METADATA"`, "duration=1s"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("log %q missing %q", buf.String(), want)
+		}
+	}
+}
+
+func TestItemSize(t *testing.T) {
+	phones := []types.AttributeValue{&types.AttributeValueMemberS{Value: "555"}}
+	settings := map[string]types.AttributeValue{"x": &types.AttributeValueMemberNULL{Value: true}}
+	item := map[string]types.AttributeValue{
+		"PK":       &types.AttributeValueMemberS{Value: "USER#1"},
+		"Count":    &types.AttributeValueMemberN{Value: "42"},
+		"Active":   &types.AttributeValueMemberBOOL{Value: true},
+		"Tags":     &types.AttributeValueMemberSS{Value: []string{"a", "bc"}},
+		"Phones":   &types.AttributeValueMemberL{Value: phones},
+		"Settings": &types.AttributeValueMemberM{Value: settings},
+	}
+	// name + value; a list or map adds 3 plus 1 per element
+	want := (2 + 6) + (5 + 2) + (6 + 1) + (4 + 3) + (6 + 3 + 1 + 3) + (8 + 3 + 1 + 1 + 1)
+	if got := ItemSize(item); got != want {
+		t.Errorf("ItemSize = %d, want %d", got, want)
+	}
+}
+
+func TestRecordItemSize_WarnsNearLimit(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	repo := &GenericRepository{tableName: "test-table"}
+	ctx := requestid.WithID(context.Background(), "req-1")
+	item := &keyedItem{PK: "USER#1", SK: "CONTACT#1"}
+	large := map[string]types.AttributeValue{"Notes": &types.AttributeValueMemberS{Value: strings.Repeat("x", 350*1024)}}
+
+	repo.recordItemSize(ctx, item, large)
+	if buf.Len() != 0 {
+		t.Fatalf("logged with metrics disabled: %s", buf.String())
+	}
+
+	repo.EnableItemSizeMetrics(true)
+	repo.recordItemSize(ctx, item, map[string]types.AttributeValue{"Notes": &types.AttributeValueMemberS{Value: "short"}})
+	if buf.Len() != 0 {
+		t.Fatalf("logged a small item: %s", buf.String())
+	}
+
+	repo.recordItemSize(ctx, item, large)
+	for _, want := range []string{"[req=req-1]", "large DynamoDB item", "USER#1/CONTACT#1", "limit 409600"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("log %q missing %q", buf.String(), want)
+		}
+	}
+}
+
+func TestCheckAttributes_LogsDrift(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	type contact struct {
+		PK      string `dynamodbav:"PK"`
+		UserID  string `dynamodbav:"UserID"`
+		Name    string `dynamodbav:"Name"`
+		Company string `dynamodbav:"Company,omitempty"`
+	}
+	item := map[string]types.AttributeValue{
+		"PK":     &types.AttributeValueMemberS{Value: "USER#1"},
+		"SK":     &types.AttributeValueMemberS{Value: "CONTACT#2"},
+		"userid": &types.AttributeValueMemberS{Value: "1"},
+	}
+	repo := &GenericRepository{}
+	ctx := context.Background()
+
+	var result contact
+	repo.checkAttributes(ctx, []map[string]types.AttributeValue{item}, &result)
+	if buf.Len() != 0 {
+		t.Fatalf("logged with validation disabled: %s", buf.String())
+	}
+
+	repo.EnableAttributeValidation(true)
+	var results []*contact
+	repo.checkAttributes(ctx, []map[string]types.AttributeValue{item}, &results)
+	for _, want := range []string{"USER#1/CONTACT#2", "Name", "UserID (found userid)"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("log %q missing %q", buf.String(), want)
+		}
+	}
+	if strings.Contains(buf.String(), "Company") {
+		t.Errorf("omitempty attribute reported: %s", buf.String())
+	}
+}
+
+// countPages answers COUNT queries with one page per entry, chaining them with
+// LastEvaluatedKey, and records each request it receives. With items set, each page
+// also holds that many items, keyed SK <page>-<n>.
+type countPages struct {
+	counts   []int
+	items    bool
+	requests []map[string]interface{}
+}
+
+func (f *countPages) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var in map[string]interface{}
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	page := len(f.requests)
+	f.requests = append(f.requests, in)
+
+	out := map[string]interface{}{"Count": f.counts[page], "ScannedCount": 10}
+	if in["ReturnConsumedCapacity"] == "TOTAL" {
+		out["ConsumedCapacity"] = map[string]interface{}{"TableName": in["TableName"], "CapacityUnits": 1.5}
+	}
+	if f.items {
+		items := make([]map[string]attributeValueJSON, f.counts[page])
+		for n := range items {
+			items[n] = map[string]attributeValueJSON{"PK": {S: "USER#1"}, "SK": {S: strconv.Itoa(page) + "-" + strconv.Itoa(n)}}
+		}
+		out["Items"] = items
+	}
+	if page+1 < len(f.counts) {
+		out["LastEvaluatedKey"] = map[string]attributeValueJSON{"PK": {S: "USER#1"}, "SK": {S: strconv.Itoa(page)}}
+	}
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	json.NewEncoder(w).Encode(out)
+}
+
+func TestCheckConflict(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	read := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	concurrent := read.Add(time.Second)
+	written := read.Add(2 * time.Second)
+	stored := func(t time.Time) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{"UpdatedAt": &types.AttributeValueMemberS{Value: t.Format(time.RFC3339Nano)}}
+	}
+
+	repo := &GenericRepository{tableName: "test-table"}
+	ctx := WithReadTracking(context.Background())
+	repo.recordRead(ctx, "USER#1", "CONTACT#1", stored(read))
+	repo.checkConflict(ctx, "USER#1", "CONTACT#1", stored(concurrent), written)
+	if buf.Len() != 0 {
+		t.Fatalf("logged with conflict logging disabled: %s", buf.String())
+	}
+
+	repo.EnableConflictLogging(true)
+	repo.recordRead(ctx, "USER#1", "CONTACT#1", stored(read))
+	repo.checkConflict(ctx, "USER#1", "CONTACT#1", stored(read), written)
+	if buf.Len() != 0 {
+		t.Fatalf("logged an update of the version that was read: %s", buf.String())
+	}
+
+	// The first update became the version this request knows, so only a write after it conflicts
+	repo.checkConflict(ctx, "USER#1", "CONTACT#1", stored(written), written.Add(time.Second))
+	if buf.Len() != 0 {
+		t.Fatalf("logged this request's own earlier update: %s", buf.String())
+	}
+
+	repo.recordRead(ctx, "USER#1", "CONTACT#1", stored(read))
+	repo.checkConflict(ctx, "USER#1", "CONTACT#1", stored(concurrent), written)
+	if !strings.Contains(buf.String(), "write conflict: USER#1/CONTACT#1") {
+		t.Errorf("concurrent write not logged: %q", buf.String())
+	}
+
+	buf.Reset()
+	repo.checkConflict(ctx, "USER#1", "CONTACT#2", stored(concurrent), written)
+	repo.checkConflict(context.Background(), "USER#1", "CONTACT#1", stored(concurrent), written)
+	if buf.Len() != 0 {
+		t.Errorf("logged an update without a preceding read: %s", buf.String())
+	}
+}
+
+func TestCountWithFilter(t *testing.T) {
+	table := &countPages{counts: []int{3, 4}}
+	srv := httptest.NewServer(table)
+	t.Cleanup(srv.Close)
+	repo := NewGenericRepository(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(srv.URL),
+	}, "test-table")
+
+	filter := expression.Name("IsFavorite").Equal(expression.Value(true))
+	got, err := repo.CountWithFilter(context.Background(), "USER#1", "CONTACT#", filter)
+	if err != nil {
+		t.Fatalf("CountWithFilter: %v", err)
+	}
+	if got != 7 {
+		t.Errorf("count = %d, want 7 (summed over both pages)", got)
+	}
+	if len(table.requests) != 2 {
+		t.Fatalf("sent %d queries, want 2", len(table.requests))
+	}
+	first := table.requests[0]
+	if first["Select"] != "COUNT" {
+		t.Errorf("Select = %v, want COUNT", first["Select"])
+	}
+	if first["FilterExpression"] == nil {
+		t.Error("query has no FilterExpression")
+	}
+	if table.requests[1]["ExclusiveStartKey"] == nil {
+		t.Error("second page did not continue from LastEvaluatedKey")
+	}
+}
+
+func TestQueryPage_Projection(t *testing.T) {
+	table := &countPages{counts: []int{0}}
+	srv := httptest.NewServer(table)
+	t.Cleanup(srv.Close)
+	repo := NewGenericRepository(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(srv.URL),
+	}, "test-table")
+
+	var page []map[string]interface{}
+	if _, err := repo.QueryPage(context.Background(), "USER#1", "CONTACT#", 10, nil, &page, "ID", "Name"); err != nil {
+		t.Fatalf("QueryPage: %v", err)
+	}
+
+	req := table.requests[0]
+	if req["Limit"] != float64(10) {
+		t.Errorf("Limit = %v, want 10", req["Limit"])
+	}
+	if req["ProjectionExpression"] == nil {
+		t.Fatal("query has no ProjectionExpression")
+	}
+	projected := map[string]bool{}
+	for _, name := range req["ExpressionAttributeNames"].(map[string]interface{}) {
+		projected[name.(string)] = true
+	}
+	for _, want := range []string{"PK", "SK", "ID", "Name"} {
+		if !projected[want] {
+			t.Errorf("projection %v missing %s", projected, want)
+		}
+	}
+}
+
+// cancelledTransaction rejects every TransactWriteItems call the way DynamoDB does when
+// the item at failIndex fails its condition, and records the request
+type cancelledTransaction struct {
+	failIndex int
+	request   map[string]interface{}
+}
+
+func (f *cancelledTransaction) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := json.NewDecoder(req.Body).Decode(&f.request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	items := f.request["TransactItems"].([]interface{})
+	reasons := make([]map[string]string, len(items))
+	for i := range reasons {
+		reasons[i] = map[string]string{"Code": "None"}
+	}
+	reasons[f.failIndex] = map[string]string{"Code": "ConditionalCheckFailed", "Message": "The conditional request failed"}
+
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"__type":              "com.amazonaws.dynamodb.v20120810#TransactionCanceledException",
+		"message":             "Transaction cancelled",
+		"CancellationReasons": reasons,
+	})
+}
+
+func TestTransactWrite_ConditionFailed(t *testing.T) {
+	table := &cancelledTransaction{failIndex: 2}
+	srv := httptest.NewServer(table)
+	t.Cleanup(srv.Close)
+	repo := NewGenericRepository(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(srv.URL),
+	}, "test-table")
+
+	exists := expression.AttributeExists(expression.Name("PK"))
+	err := repo.TransactWrite(context.Background(), TxItems{
+		ConditionalPuts: []TxPut{{
+			Item:      &keyedItem{PK: "USER#1", SK: "CONTACT#2", ID: "2"},
+			Condition: expression.Name("UpdatedAt").Equal(expression.Value("2024-03-01T12:00:00Z")),
+		}},
+		Deletes: []map[string]string{{"PK": "USER#1", "SK": "CONTACT#1"}},
+		Updates: []TxUpdate{{
+			PK:        "USER#1",
+			SK:        "METADATA",
+			Update:    expression.Add(expression.Name("ContactCount"), expression.Value(-1)),
+			Condition: &exists,
+		}},
+		Checks: []TxCheck{{PK: "USER#2", SK: "METADATA", Condition: exists}},
+	})
+	if !errors.Is(err, ErrConditionFailed) {
+		t.Fatalf("err = %v, want ErrConditionFailed", err)
+	}
+
+	items := table.request["TransactItems"].([]interface{})
+	if len(items) != 4 {
+		t.Fatalf("sent %d transaction items, want 4", len(items))
+	}
+	put, ok := items[0].(map[string]interface{})["Put"].(map[string]interface{})
+	if !ok || put["ConditionExpression"] == nil || put["ExpressionAttributeValues"] == nil {
+		t.Errorf("conditional put item = %v, want a condition with values", items[0])
+	}
+	update, ok := items[2].(map[string]interface{})["Update"].(map[string]interface{})
+	if !ok || update["ConditionExpression"] == nil || update["UpdateExpression"] == nil {
+		t.Errorf("update item = %v, want update and condition expressions", items[2])
+	}
+	if _, ok := items[3].(map[string]interface{})["ConditionCheck"]; !ok {
+		t.Errorf("fourth item = %v, want a ConditionCheck", items[3])
+	}
+}
+
+// existingItem fails every PutItem call's condition, as if the item were already stored,
+// and records the request. With stored set, the failure carries it as the old item
+// (unless omitOld) and GetItem returns it.
+type existingItem struct {
+	request map[string]interface{}
+	stored  map[string]attributeValueJSON
+	omitOld bool
+	gets    int
+}
+
+func (f *existingItem) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := json.NewDecoder(req.Body).Decode(&f.request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	if strings.HasSuffix(req.Header.Get("X-Amz-Target"), ".GetItem") {
+		f.gets++
+		json.NewEncoder(w).Encode(map[string]interface{}{"Item": f.stored})
+		return
+	}
+	failure := map[string]interface{}{
+		"__type":  "com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException",
+		"message": "The conditional request failed",
+	}
+	if f.stored != nil && !f.omitOld {
+		failure["Item"] = f.stored
+	}
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(failure)
+}
+
+func TestPutIfAbsentOrGet_Exists(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		omitOld  bool
+		wantGets int
+	}{
+		{"old item returned with the failure", false, 0},
+		{"old item fetched with a Get", true, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			table := &existingItem{
+				stored:  map[string]attributeValueJSON{"PK": {S: "USER#1"}, "SK": {S: "CONTACT#1"}, "id": {S: "stored"}},
+				omitOld: tc.omitOld,
+			}
+			srv := httptest.NewServer(table)
+			t.Cleanup(srv.Close)
+			repo := NewGenericRepository(aws.Config{
+				Region:       "us-east-1",
+				Credentials:  aws.AnonymousCredentials{},
+				BaseEndpoint: aws.String(srv.URL),
+			}, "test-table")
+
+			var got keyedItem
+			created, err := repo.PutIfAbsentOrGet(context.Background(), &keyedItem{PK: "USER#1", SK: "CONTACT#1", ID: "new"}, &got)
+			if err != nil {
+				t.Fatalf("PutIfAbsentOrGet: %v", err)
+			}
+			if created {
+				t.Error("created = true, want false for an existing item")
+			}
+			if got.ID != "stored" {
+				t.Errorf("result ID = %q, want the stored item's", got.ID)
+			}
+			if table.gets != tc.wantGets {
+				t.Errorf("sent %d GetItem calls, want %d", table.gets, tc.wantGets)
+			}
+		})
+	}
+}
+
+// deletedOnce serves DeleteItem as if the item existed for the first call only,
+// returning it as the old item, and records the requests
+type deletedOnce struct {
+	requests []map[string]interface{}
+}
+
+func (f *deletedOnce) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var in map[string]interface{}
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.requests = append(f.requests, in)
+
+	out := map[string]interface{}{}
+	if len(f.requests) == 1 {
+		out["Attributes"] = map[string]attributeValueJSON{"PK": {S: "USER#1"}, "SK": {S: "FAV#1"}}
+	}
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	json.NewEncoder(w).Encode(out)
+}
+
+func TestDeleteIfExists(t *testing.T) {
+	table := &deletedOnce{}
+	srv := httptest.NewServer(table)
+	t.Cleanup(srv.Close)
+	repo := NewGenericRepository(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(srv.URL),
+	}, "test-table")
+
+	for i, want := range []bool{true, false} {
+		existed, err := repo.DeleteIfExists(context.Background(), "USER#1", "FAV#1")
+		if err != nil {
+			t.Fatalf("delete %d: %v", i+1, err)
+		}
+		if existed != want {
+			t.Errorf("delete %d: existed = %v, want %v", i+1, existed, want)
+		}
+	}
+	for i, req := range table.requests {
+		if req["ConditionExpression"] != nil || req["ReturnValues"] != "ALL_OLD" {
+			t.Errorf("request %d: ConditionExpression = %v, ReturnValues = %v; want no condition and ALL_OLD", i+1, req["ConditionExpression"], req["ReturnValues"])
+		}
+	}
+}
+
+// keyedItem is a minimal BaseModel for tables keyed on an attribute other than PK
+type keyedItem struct {
+	PK, SK string
+	ID     string `dynamodbav:"id"`
+}
+
+func (i *keyedItem) GetPK() string         { return i.PK }
+func (i *keyedItem) GetSK() string         { return i.SK }
+func (i *keyedItem) SetPK(pk string)       { i.PK = pk }
+func (i *keyedItem) SetSK(sk string)       { i.SK = sk }
+func (i *keyedItem) GetEntityType() string { return "CONTACT" }
+
+func TestPutIfNotExistsOn(t *testing.T) {
+	table := &existingItem{}
+	srv := httptest.NewServer(table)
+	t.Cleanup(srv.Close)
+	repo := NewGenericRepository(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(srv.URL),
+	}, "test-table")
+
+	item := &keyedItem{PK: "CONTACT#1", SK: "METADATA", ID: "1"}
+	for _, tc := range []struct {
+		name string
+		put  func() error
+		want string
+	}{
+		{"default PK", func() error { return repo.PutIfNotExists(context.Background(), item) }, "PK"},
+		{"custom attribute", func() error { return repo.PutIfNotExistsOn(context.Background(), item, "id") }, "id"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.put(); !errors.Is(err, ErrAlreadyExists) {
+				t.Fatalf("err = %v, want ErrAlreadyExists", err)
+			}
+			names, _ := table.request["ExpressionAttributeNames"].(map[string]interface{})
+			if table.request["ConditionExpression"] != "attribute_not_exists(#key)" || names["#key"] != tc.want {
+				t.Errorf("condition = %v with names %v, want attribute_not_exists(%s)", table.request["ConditionExpression"], names, tc.want)
+			}
+		})
+	}
+
+	if err := repo.PutIfNotExistsOn(context.Background(), item, ""); err == nil {
+		t.Error("empty key attribute: want an error")
+	}
+}
+
+func TestTenantTables(t *testing.T) {
+	table := &countPages{counts: []int{0, 0, 0}}
+	srv := httptest.NewServer(table)
+	t.Cleanup(srv.Close)
+	repo := NewGenericRepository(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(srv.URL),
+	}, "test-table")
+
+	acme := tenant.WithID(context.Background(), "acme")
+	var page []map[string]interface{}
+	query := func(ctx context.Context) string {
+		if _, err := repo.QueryPage(ctx, "USER#1", "CONTACT#", 10, nil, &page); err != nil {
+			t.Fatalf("QueryPage: %v", err)
+		}
+		return table.requests[len(table.requests)-1]["TableName"].(string)
+	}
+
+	if got := query(acme); got != "test-table" {
+		t.Errorf("tenant tables off: table = %s, want test-table", got)
+	}
+	repo.EnableTenantTables(true)
+	if got := query(acme); got != "acme-test-table" {
+		t.Errorf("tenant request: table = %s, want acme-test-table", got)
+	}
+	if got := query(context.Background()); got != "test-table" {
+		t.Errorf("request without tenant: table = %s, want test-table", got)
+	}
+}
+
+func TestUnmodifiedSinceBound(t *testing.T) {
+	since := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	bound := unmodifiedSinceBound(since)
+
+	tests := []struct {
+		updatedAt  time.Time
+		unmodified bool
+	}{
+		{since.Add(-time.Hour), true},
+		{since, true},
+		{since.Add(500 * time.Millisecond), true}, // stored ".5Z", same second as the HTTP date
+		{since.Add(999_999_999), true},
+		{since.Add(time.Second), false},
+		{since.Add(time.Second + time.Nanosecond), false},
+	}
+	for _, tt := range tests {
+		stored := tt.updatedAt.Format(time.RFC3339Nano)
+		if got := stored < bound; got != tt.unmodified {
+			t.Errorf("%s < %s = %v, want %v", stored, bound, got, tt.unmodified)
+		}
+	}
+
+	// A non-UTC header time compares the same as its UTC equivalent
+	if got := unmodifiedSinceBound(since.In(time.FixedZone("CET", 3600))); got != bound {
+		t.Errorf("bound in another zone = %s, want %s", got, bound)
+	}
+}
+
+func TestCheckTable(t *testing.T) {
+	describe := func(gsiRange string) string {
+		return `{"Table": {
+			"TableName": "test-table",
+			"TableStatus": "ACTIVE",
+			"AttributeDefinitions": [
+				{"AttributeName": "PK", "AttributeType": "S"},
+				{"AttributeName": "SK", "AttributeType": "S"},
+				{"AttributeName": "GSI1PK", "AttributeType": "S"},
+				{"AttributeName": "GSI1SK", "AttributeType": "S"}
+			],
+			"KeySchema": [
+				{"AttributeName": "PK", "KeyType": "HASH"},
+				{"AttributeName": "SK", "KeyType": "RANGE"}
+			],
+			"GlobalSecondaryIndexes": [{
+				"IndexName": "GSI1",
+				"KeySchema": [
+					{"AttributeName": "GSI1PK", "KeyType": "HASH"},
+					{"AttributeName": "` + gsiRange + `", "KeyType": "RANGE"}
+				]
+			}]
+		}}`
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"matching schema", describe("GSI1SK"), ""},
+		{"wrong index key", describe("CreatedAt"), "index GSI1: key attribute CreatedAt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+				w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(srv.Close)
+			repo := NewGenericRepository(aws.Config{
+				Region:       "us-east-1",
+				Credentials:  aws.AnonymousCredentials{},
+				BaseEndpoint: aws.String(srv.URL),
+			}, "test-table")
+
+			err := repo.CheckTable(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CheckTable: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetryPing(t *testing.T) {
+	ctx := context.Background()
+	failures := 2
+	calls := 0
+	ping := func(context.Context) error {
+		calls++
+		if calls <= failures {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	if err := retryPing(ctx, ping, 3, time.Millisecond); err != nil {
+		t.Fatalf("retryPing: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("pinged %d times, want 3", calls)
+	}
+
+	calls, failures = 0, 10
+	err := retryPing(ctx, ping, 2, time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "after 2 attempts") {
+		t.Errorf("err = %v, want the attempts to run out", err)
+	}
+	if calls != 2 {
+		t.Errorf("pinged %d times, want 2", calls)
+	}
+}
+
+func TestQueryIndex(t *testing.T) {
+	table := &countPages{counts: []int{0, 0}}
+	srv := httptest.NewServer(table)
+	t.Cleanup(srv.Close)
+	repo := NewGenericRepository(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(srv.URL),
+	}, "test-table")
+
+	filter := expression.Name("IsFavorite").Equal(expression.Value(true))
+	var page []map[string]interface{}
+	next, err := repo.QueryIndex(context.Background(), "GSI1",
+		expression.Key("GSI1PK").Equal(expression.Value("CONTACT")),
+		QueryOptions{Limit: 25, ScanForward: aws.Bool(false), Filter: &filter, Projection: []string{"Name"}},
+		&page)
+	if err != nil {
+		t.Fatalf("QueryIndex: %v", err)
+	}
+	if next == nil {
+		t.Fatal("cursor = nil, want the page's LastEvaluatedKey")
+	}
+
+	req := table.requests[0]
+	for field, want := range map[string]interface{}{"IndexName": "GSI1", "Limit": float64(25), "ScanIndexForward": false} {
+		if req[field] != want {
+			t.Errorf("%s = %v, want %v", field, req[field], want)
+		}
+	}
+	for _, field := range []string{"KeyConditionExpression", "FilterExpression", "ProjectionExpression"} {
+		if req[field] == nil {
+			t.Errorf("query has no %s", field)
+		}
+	}
+
+	// The second page resumes from the cursor and, with zero options, sends none of them
+	if _, err := repo.QueryIndex(context.Background(), "", expression.Key("PK").Equal(expression.Value("USER#1")), QueryOptions{StartKey: next}, &page); err != nil {
+		t.Fatalf("QueryIndex: %v", err)
+	}
+	req = table.requests[1]
+	if req["ExclusiveStartKey"] == nil {
+		t.Error("second page has no ExclusiveStartKey")
+	}
+	for _, field := range []string{"IndexName", "Limit", "ScanIndexForward", "FilterExpression", "ProjectionExpression"} {
+		if req[field] != nil {
+			t.Errorf("zero options sent %s = %v", field, req[field])
+		}
+	}
+}
+
+func TestObjectUploads_PresignPut(t *testing.T) {
+	uploads := NewObjectUploads(aws.Config{
+		Region: "eu-west-1",
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+		}),
+	}, "avatars-bucket", 10*time.Minute)
+
+	upload, err := uploads.PresignPut(context.Background(), "avatars/u1/c1/a.png", "image/png", 1234)
+	if err != nil {
+		t.Fatalf("PresignPut: %v", err)
+	}
+	if upload.Method != http.MethodPut || !strings.Contains(upload.UploadURL, "avatars-bucket") || !strings.Contains(upload.UploadURL, "X-Amz-Signature=") {
+		t.Errorf("upload = %s %s, want a signed PUT into the bucket", upload.Method, upload.UploadURL)
+	}
+	// The client has to send exactly these, or S3 rejects the signature
+	if upload.Headers["Content-Type"] != "image/png" || upload.Headers["Content-Length"] != "1234" {
+		t.Errorf("headers = %v, want the signed content type and length", upload.Headers)
+	}
+	if want := "https://avatars-bucket.s3.eu-west-1.amazonaws.com/avatars/u1/c1/a.png"; upload.ObjectURL != want {
+		t.Errorf("ObjectURL = %s, want %s", upload.ObjectURL, want)
+	}
+}
+
+func TestQueryStream(t *testing.T) {
+	table := &countPages{counts: []int{2, 3}, items: true}
+	srv := httptest.NewServer(table)
+	t.Cleanup(srv.Close)
+	repo := NewGenericRepository(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(srv.URL),
+	}, "test-table")
+
+	stream, err := repo.QueryStream(context.Background(), "USER#1", "")
+	if err != nil {
+		t.Fatalf("QueryStream: %v", err)
+	}
+	var sks []string
+	for item := range stream {
+		if item.Err != nil {
+			t.Fatalf("stream error: %v", item.Err)
+		}
+		sks = append(sks, item.Item["SK"].(*types.AttributeValueMemberS).Value)
+	}
+	if got := strings.Join(sks, ","); got != "0-0,0-1,1-0,1-1,1-2" {
+		t.Errorf("streamed %s, want both pages in order", got)
+	}
+
+	// Cancelling stops the producer and closes the channel without reading further pages
+	table.requests = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err = repo.QueryStream(ctx, "USER#1", "")
+	if err != nil {
+		t.Fatalf("QueryStream: %v", err)
+	}
+	<-stream
+	cancel()
+	for range stream {
+	}
+	if len(table.requests) != 1 {
+		t.Errorf("fetched %d pages after cancel, want only the first", len(table.requests))
+	}
+}
+
+func TestQueryWithStats(t *testing.T) {
+	table := &countPages{counts: []int{2, 3}, items: true}
+	srv := httptest.NewServer(table)
+	t.Cleanup(srv.Close)
+	repo := NewGenericRepository(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(srv.URL),
+	}, "test-table")
+
+	filter := expression.Name("IsFavorite").Equal(expression.Value(true))
+	items, stats, err := repo.QueryWithStats(context.Background(), "USER#1", "CONTACT#", &filter)
+	if err != nil {
+		t.Fatalf("QueryWithStats: %v", err)
+	}
+	if len(items) != 5 {
+		t.Errorf("got %d items, want both pages", len(items))
+	}
+	if want := (QueryStats{ConsumedCapacity: 3, ScannedCount: 20, Count: 5, Pages: 2}); stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+	// Capacity is requested even though EnableConsumedCapacity is off
+	for i, req := range table.requests {
+		if req["ReturnConsumedCapacity"] != "TOTAL" || req["FilterExpression"] == nil {
+			t.Errorf("request %d: ReturnConsumedCapacity = %v, FilterExpression = %v", i, req["ReturnConsumedCapacity"], req["FilterExpression"])
+		}
+	}
+}
+
+func TestQueryByEntityTypeBetween_AllPages(t *testing.T) {
+	table := &countPages{counts: []int{2, 3}, items: true}
+	srv := httptest.NewServer(table)
+	t.Cleanup(srv.Close)
+	repo := NewGenericRepository(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(srv.URL),
+	}, "test-table")
+
+	var items []*keyedItem
+	if err := repo.QueryByEntityTypeBetween(context.Background(), "USER", "2024", "2025", &items); err != nil {
+		t.Fatalf("QueryByEntityTypeBetween: %v", err)
+	}
+	if len(items) != 5 {
+		t.Errorf("got %d items, want both pages", len(items))
+	}
+	if len(table.requests) != 2 || table.requests[1]["ExclusiveStartKey"] == nil {
+		t.Errorf("sent %d queries, want the second to continue from LastEvaluatedKey", len(table.requests))
+	}
+	if table.requests[0]["IndexName"] != "GSI1" {
+		t.Errorf("IndexName = %v, want GSI1", table.requests[0]["IndexName"])
+	}
+}
+
+func TestClose_StopsStreams(t *testing.T) {
+	table := &countPages{counts: []int{2, 3}, items: true}
+	srv := httptest.NewServer(table)
+	t.Cleanup(srv.Close)
+	repo := NewGenericRepository(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(srv.URL),
+	}, "test-table")
+
+	stream, err := repo.QueryStream(context.Background(), "USER#1", "")
+	if err != nil {
+		t.Fatalf("QueryStream: %v", err)
+	}
+	<-stream
+
+	closed := make(chan error, 1)
+	go func() { closed <- repo.Close(context.Background()) }()
+	<-repo.closing
+
+	var last StreamItem
+	for item := range stream {
+		last = item
+	}
+	if !errors.Is(last.Err, ErrClosed) {
+		t.Errorf("last stream item = %+v, want ErrClosed", last)
+	}
+	if len(table.requests) != 1 {
+		t.Errorf("fetched %d pages, want the stream to stop after the first", len(table.requests))
+	}
+	if err := <-closed; err != nil {
+		t.Errorf("Close: %v", err)
+	}
+
+	if _, err := repo.QueryStream(context.Background(), "USER#1", ""); !errors.Is(err, ErrClosed) {
+		t.Errorf("QueryStream after Close: err = %v, want ErrClosed", err)
+	}
+	if err := repo.Close(context.Background()); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
+
+func TestRedisKey(t *testing.T) {
+	user, contact := "v"+strconv.Itoa(UserCacheVersion)+":", "v"+strconv.Itoa(ContactCacheVersion)+":"
+	ctx := context.Background()
+	for key, want := range map[string]string{
+		"user:1":              user + "user:1",
+		"users:list":          user + "users:list",
+		"contacts:*:user:u1":  contact + "contacts:*:user:u1",
+		"contact:u1:c1":       contact + "contact:u1:c1",
+		"lock:trash-purge":    "lock:trash-purge",
+		"userland:not-a-user": "userland:not-a-user",
+	} {
+		if got := redisKey(ctx, key); got != want {
+			t.Errorf("redisKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+
+	// The version sits inside the tenant namespace
+	if got, want := redisKey(tenant.WithID(ctx, "acme"), "user:1"), "t:acme:"+user+"user:1"; got != want {
+		t.Errorf("tenant key = %q, want %q", got, want)
+	}
+}
+
+// recordingTransactions records each TransactWrite and fails the call at index failAt
+type recordingTransactions struct {
+	SingleTableRepository
+	calls  []TxItems
+	failAt int
+}
+
+func (f *recordingTransactions) TransactWrite(ctx context.Context, tx TxItems) error {
+	f.calls = append(f.calls, tx)
+	if len(f.calls)-1 == f.failAt {
+		return ErrConditionFailed
+	}
+	return nil
+}
+
+func TestUnitOfWork_Chunks(t *testing.T) {
+	ctx := context.Background()
+	repo := &recordingTransactions{failAt: -1}
+	uow := NewUnitOfWork(repo)
+	if err := uow.Commit(ctx); err != nil || len(repo.calls) != 0 {
+		t.Fatalf("empty commit: err = %v, %d transactions; want neither", err, len(repo.calls))
+	}
+
+	for i := 0; i < MaxTransactWriteItems; i++ {
+		uow.Delete("USER#1", "CONTACT#"+strconv.Itoa(i))
+	}
+	uow.Update(TxUpdate{PK: "USER#1", SK: "METADATA", Update: expression.Add(expression.Name("ContactCount"), expression.Value(-MaxTransactWriteItems))})
+	if uow.Len() != MaxTransactWriteItems+1 {
+		t.Fatalf("Len = %d, want %d", uow.Len(), MaxTransactWriteItems+1)
+	}
+	if err := uow.Commit(ctx); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if len(repo.calls) != 2 || len(repo.calls[0].Deletes) != MaxTransactWriteItems || len(repo.calls[1].Updates) != 1 {
+		t.Fatalf("committed %d transactions, want a full chunk of deletes then the update", len(repo.calls))
+	}
+	if uow.Len() != 0 {
+		t.Error("Commit didn't empty the unit")
+	}
+
+	// A failed later chunk says how much was already applied and keeps the cause
+	repo.calls, repo.failAt = nil, 1
+	for i := 0; i <= MaxTransactWriteItems; i++ {
+		uow.Delete("USER#1", "CONTACT#"+strconv.Itoa(i))
+	}
+	err := uow.Commit(ctx)
+	if !errors.Is(err, ErrConditionFailed) || !strings.Contains(err.Error(), "after 1 were committed") {
+		t.Errorf("err = %v, want ErrConditionFailed noting the committed chunk", err)
+	}
+
+	// A smaller chunk size splits sooner; strict units that don't fit are refused whole
+	repo.calls, repo.failAt = nil, -1
+	uow.SetMaxItems(2)
+	for i := 0; i < 3; i++ {
+		uow.Delete("USER#1", "CONTACT#"+strconv.Itoa(i))
+	}
+	if err := uow.Commit(ctx); err != nil || len(repo.calls) != 2 {
+		t.Fatalf("3 writes in chunks of 2: err = %v, %d transactions; want 2", err, len(repo.calls))
+	}
+	repo.calls = nil
+	uow.SetStrict(true)
+	for i := 0; i < 3; i++ {
+		uow.Delete("USER#1", "CONTACT#"+strconv.Itoa(i))
+	}
+	var tooLarge *TransactionTooLargeError
+	if err := uow.Commit(ctx); !errors.As(err, &tooLarge) || tooLarge.Limit != 2 || len(repo.calls) != 0 {
+		t.Errorf("strict unit over the limit: err = %v, %d transactions; want TransactionTooLargeError and no writes", err, len(repo.calls))
+	}
+}
+
+func TestNewRedisClient_Modes(t *testing.T) {
+	addrs := []string{"redis-1:6379", "redis-2:6379"}
+	for _, tc := range []struct {
+		opts RedisOptions
+		want string
+	}{
+		{RedisOptions{Addresses: addrs}, "*redis.Client"},
+		{RedisOptions{Mode: RedisModeSingle, Addresses: addrs}, "*redis.Client"},
+		{RedisOptions{Mode: RedisModeCluster, Addresses: addrs}, "*redis.ClusterClient"},
+		// A failover client is a *redis.Client that asks the sentinels for the master
+		{RedisOptions{Mode: RedisModeSentinel, Addresses: addrs, MasterName: "primary"}, "*redis.Client"},
+	} {
+		client, err := newRedisClient(tc.opts)
+		if err != nil {
+			t.Fatalf("mode %q: %v", tc.opts.Mode, err)
+		}
+		if got := fmt.Sprintf("%T", client); got != tc.want {
+			t.Errorf("mode %q built %s, want %s", tc.opts.Mode, got, tc.want)
+		}
+		if tc.opts.Mode == RedisModeSentinel {
+			if got := client.(*redis.Client).Options().Addr; got != "FailoverClient" {
+				t.Errorf("sentinel client Addr = %q, want a failover client", got)
+			}
+		}
+		client.Close()
+	}
+
+	for _, opts := range []RedisOptions{
+		{Mode: RedisModeSentinel, Addresses: addrs},
+		{Mode: "replica", Addresses: addrs},
+		{Mode: RedisModeCluster},
+	} {
+		if _, err := newRedisClient(opts); err == nil {
+			t.Errorf("mode %q with %d addresses: no error", opts.Mode, len(opts.Addresses))
+		}
+	}
+}
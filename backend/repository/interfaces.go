@@ -2,10 +2,19 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"hub-control-plane/backend/models"
 )
 
 // UserRepository defines the interface for user data operations
+//
+// Deprecated: it describes the per-entity DynamoDB repository the single-table design
+// replaced and nothing implements it. Use SingleTableRepository (*GenericRepository),
+// whose QueryPage/QueryIndex page with a start key and return the next one.
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *models.User) error
 	GetUser(ctx context.Context, id string) (*models.User, error)
@@ -15,6 +24,8 @@ type UserRepository interface {
 }
 
 // ContactRepository defines the interface for contact data operations
+//
+// Deprecated: nothing implements it; use SingleTableRepository.
 type ContactRepository interface {
 	CreateContact(ctx context.Context, contact *models.Contact) error
 	GetContact(ctx context.Context, id string) (*models.Contact, error)
@@ -26,6 +37,9 @@ type ContactRepository interface {
 }
 
 // UserCache defines the interface for user caching operations
+//
+// Deprecated: the service caches through Cache (RedisAdapter); only RedisCache's legacy
+// models.User helpers still satisfy it.
 type UserCache interface {
 	GetUser(ctx context.Context, id string) (*models.User, error)
 	SetUser(ctx context.Context, user *models.User) error
@@ -36,6 +50,8 @@ type UserCache interface {
 }
 
 // ContactCache defines the interface for contact caching operations
+//
+// Deprecated: nothing implements it; use Cache.
 type ContactCache interface {
 	GetContact(ctx context.Context, id string) (*models.Contact, error)
 	SetContact(ctx context.Context, contact *models.Contact) error
@@ -49,4 +65,73 @@ type ContactCache interface {
 	SetUserContactList(ctx context.Context, userID string, contacts []*models.Contact) error
 	GetFavoriteContactList(ctx context.Context, userID string) ([]*models.Contact, error)
 	SetFavoriteContactList(ctx context.Context, userID string, contacts []*models.Contact) error
-}
\ No newline at end of file
+}
+
+// UploadPresigner hands out URLs clients upload objects to directly.
+// *ObjectUploads implements it over S3.
+type UploadPresigner interface {
+	PresignPut(ctx context.Context, key, contentType string, size int64) (*PresignedUpload, error)
+}
+
+// SingleTableRepository defines the single-table operations the service layer uses.
+// *GenericRepository implements it; tests can substitute an in-memory fake.
+type SingleTableRepository interface {
+	Put(ctx context.Context, item BaseModel) error
+	PutIfNotExists(ctx context.Context, item BaseModel) error
+	PutIfAbsentOrGet(ctx context.Context, item BaseModel, result BaseModel) (created bool, err error)
+	Get(ctx context.Context, pk, sk string, result BaseModel) error
+	Update(ctx context.Context, pk, sk string, updates map[string]interface{}) error
+	UpdateIfUnmodifiedSince(ctx context.Context, pk, sk string, updates map[string]interface{}, since time.Time) error
+	Upsert(ctx context.Context, pk, sk string, updates map[string]interface{}) error
+	Touch(ctx context.Context, pk, sk, attribute string) error
+	Increment(ctx context.Context, pk, sk, attribute string, amount int64, bounds *IncrementBounds) (int64, error)
+	SetIfMissing(ctx context.Context, pk, sk string, values map[string]interface{}) error
+	Delete(ctx context.Context, pk, sk string) error
+	DeleteIfExists(ctx context.Context, pk, sk string) (existed bool, err error)
+	Query(ctx context.Context, pk string, skPrefix string, resultSlice interface{}) error
+	QueryItems(ctx context.Context, pk string, skPrefix string) ([]map[string]types.AttributeValue, error)
+	QueryPage(ctx context.Context, pk string, skPrefix string, limit int, startKey map[string]types.AttributeValue, resultSlice interface{}, projection ...string) (map[string]types.AttributeValue, error)
+	QueryIndex(ctx context.Context, indexName string, keyCond expression.KeyConditionBuilder, opts QueryOptions, resultSlice interface{}) (map[string]types.AttributeValue, error)
+	Count(ctx context.Context, pk string, skPrefix string) (int, error)
+	CountWithFilter(ctx context.Context, pk string, skPrefix string, filter expression.ConditionBuilder) (int, error)
+	QueryPages(ctx context.Context, pk string, skPrefix string) (*QueryPager, error)
+	QueryStream(ctx context.Context, pk string, skPrefix string) (<-chan StreamItem, error)
+	QueryByEntityType(ctx context.Context, entityType string, resultSlice interface{}) error
+	QueryByEntityTypeBetween(ctx context.Context, entityType, fromSK, toSK string, resultSlice interface{}) error
+	QueryByEntityTypePages(ctx context.Context, entityType string) (*QueryPager, error)
+	QueryWithFilter(ctx context.Context, pk string, skPrefix string, filterCondition expression.ConditionBuilder, resultSlice interface{}) error
+	QueryWithStats(ctx context.Context, pk string, skPrefix string, filter *expression.ConditionBuilder) ([]map[string]types.AttributeValue, QueryStats, error)
+	Scan(ctx context.Context, filter expression.ConditionBuilder, pageSize int, fn func(items []map[string]types.AttributeValue) error) error
+	BatchGet(ctx context.Context, keys []map[string]string, resultSlice interface{}) error
+	BatchWrite(ctx context.Context, putItems []BaseModel, deleteKeys []map[string]string) error
+	Transaction(ctx context.Context, puts []BaseModel, deletes []map[string]string) error
+	TransactWrite(ctx context.Context, tx TxItems) error
+	TransactUpdate(ctx context.Context, keys []map[string]string, updates map[string]interface{}) error
+}
+
+var _ SingleTableRepository = (*GenericRepository)(nil)
+
+// ErrCacheMiss is returned by Cache.Get when the key doesn't exist
+var ErrCacheMiss = errors.New("cache miss")
+
+// Cache is the minimal key/value cache the service layer needs.
+// RedisAdapter implements it over a Redis client of any mode; tests can use an in-memory map.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	// GetMany reads keys in one round trip; found maps each existing key to its value and
+	// misses lists the rest, in the order given
+	GetMany(ctx context.Context, keys []string) (found map[string][]byte, misses []string, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Del(ctx context.Context, keys ...string) error
+	// DelPattern removes every key matching a glob pattern and returns how many were deleted
+	DelPattern(ctx context.Context, pattern string) (int, error)
+	// Expire resets key's TTL without touching its value; a missing key is not an error
+	Expire(ctx context.Context, key string, ttl time.Duration) error
+}
+
+// Locker hands out short-lived distributed locks so periodic jobs run on one replica
+// at a time. ok is false when another holder has the lock. The lock expires after ttl
+// even if unlock is never called, so a crashed holder can't wedge the job.
+type Locker interface {
+	TryLock(ctx context.Context, key string, ttl time.Duration) (unlock func(context.Context) error, ok bool, err error)
+}
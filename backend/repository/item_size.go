@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"hub-control-plane/backend/requestid"
+)
+
+// MaxItemSize is DynamoDB's limit on the size of one item, in bytes
+const MaxItemSize = 400 * 1024
+
+// itemSizeWarnFraction of MaxItemSize is where a written item gets a warning
+const itemSizeWarnFraction = 0.8
+
+// EnableItemSizeMetrics turns on instrumentation of written item sizes: every item
+// marshaled for a write is measured, recorded in the dynamodb.item.size histogram (by
+// entity type) and on the call's span, and logged as a warning once it passes 80% of
+// MaxItemSize, so a runaway Notes field or Tags slice shows up before writes fail.
+// The histogram goes to the global OpenTelemetry meter provider. Costs a walk of every
+// written item, so it is off by default.
+func (r *GenericRepository) EnableItemSizeMetrics(enabled bool) {
+	if !enabled {
+		r.itemSizes = nil
+		return
+	}
+	// A failed registration still returns a usable (no-op) instrument
+	r.itemSizes, _ = otel.Meter("hub-control-plane/backend").Int64Histogram("dynamodb.item.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Size of items written to DynamoDB"))
+}
+
+// recordItemSize measures an item about to be written
+func (r *GenericRepository) recordItemSize(ctx context.Context, item BaseModel, av map[string]types.AttributeValue) {
+	if r.itemSizes == nil {
+		return
+	}
+	size := ItemSize(av)
+	r.itemSizes.Record(ctx, int64(size), metric.WithAttributes(
+		attribute.String("db.dynamodb.entity_type", item.GetEntityType())))
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("db.dynamodb.item_size", size))
+
+	if size >= int(MaxItemSize*itemSizeWarnFraction) {
+		requestid.Logf(ctx, "Warning: large DynamoDB item: %s/%s is %d bytes (limit %d)",
+			item.GetPK(), item.GetSK(), size, MaxItemSize)
+	}
+}
+
+// ItemSize estimates the size DynamoDB counts against MaxItemSize: the UTF-8 length of
+// every attribute name plus the size of its value. Numbers are counted by their digits,
+// which slightly overstates DynamoDB's packed encoding.
+func ItemSize(item map[string]types.AttributeValue) int {
+	size := 0
+	for name, value := range item {
+		size += len(name) + attributeSize(value)
+	}
+	return size
+}
+
+// attributeSize is the size of one value; lists and maps add 3 bytes of overhead plus 1
+// per element, as DynamoDB documents
+func attributeSize(value types.AttributeValue) int {
+	switch v := value.(type) {
+	case *types.AttributeValueMemberS:
+		return len(v.Value)
+	case *types.AttributeValueMemberN:
+		return len(v.Value)
+	case *types.AttributeValueMemberB:
+		return len(v.Value)
+	case *types.AttributeValueMemberBOOL, *types.AttributeValueMemberNULL:
+		return 1
+	case *types.AttributeValueMemberSS:
+		size := 0
+		for _, s := range v.Value {
+			size += len(s)
+		}
+		return size
+	case *types.AttributeValueMemberNS:
+		size := 0
+		for _, n := range v.Value {
+			size += len(n)
+		}
+		return size
+	case *types.AttributeValueMemberBS:
+		size := 0
+		for _, b := range v.Value {
+			size += len(b)
+		}
+		return size
+	case *types.AttributeValueMemberL:
+		size := 3
+		for _, element := range v.Value {
+			size += 1 + attributeSize(element)
+		}
+		return size
+	case *types.AttributeValueMemberM:
+		return 3 + len(v.Value) + ItemSize(v.Value)
+	}
+	return 0
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrClosed is returned for background work requested after Close
+var ErrClosed = errors.New("repository closed")
+
+// Close ends the repository's background work at shutdown: open QueryStreams stop
+// before their next item, each ending with an ErrClosed item, and Close waits for
+// their goroutines to exit or ctx to end. QueryStream fails with ErrClosed afterwards;
+// plain reads and writes still work. Closing twice is a no-op.
+//
+// The DynamoDB client itself needs no closing; this is the hook for anything the
+// repository runs in the background (streams today, buffered writes later).
+func (r *GenericRepository) Close(ctx context.Context) error {
+	r.lifecycleMu.Lock()
+	if r.closing == nil {
+		r.closing = make(chan struct{})
+	}
+	if !r.closed {
+		r.closed = true
+		close(r.closing)
+	}
+	r.lifecycleMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// startWorker registers a background goroutine that Close must wait for. It returns
+// the channel closed when the repository is closing, or ErrClosed if it already is.
+// The caller must call r.workers.Done when the goroutine exits.
+func (r *GenericRepository) startWorker() (<-chan struct{}, error) {
+	r.lifecycleMu.Lock()
+	defer r.lifecycleMu.Unlock()
+
+	if r.closed {
+		return nil, ErrClosed
+	}
+	if r.closing == nil {
+		r.closing = make(chan struct{})
+	}
+	r.workers.Add(1)
+	return r.closing, nil
+}
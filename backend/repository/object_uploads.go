@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3PresignAPI is the subset of the S3 presign client used for direct uploads
+type S3PresignAPI interface {
+	PresignPutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error)
+}
+
+// PresignedUpload is a URL a client can upload one object to, and the headers it must
+// send with the upload for the signature to match
+type PresignedUpload struct {
+	UploadURL string            `json:"upload_url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ObjectURL string            `json:"object_url"` // Where the object can be read once uploaded
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// ObjectUploads presigns direct client uploads into one S3 bucket, so large binaries
+// never pass through this service or land in DynamoDB
+type ObjectUploads struct {
+	presigner S3PresignAPI
+	bucket    string
+	region    string
+	expiry    time.Duration
+}
+
+// NewObjectUploads presigns uploads into bucket; each URL is valid for expiry
+func NewObjectUploads(awsConfig aws.Config, bucket string, expiry time.Duration) *ObjectUploads {
+	return &ObjectUploads{
+		presigner: s3.NewPresignClient(s3.NewFromConfig(awsConfig)),
+		bucket:    bucket,
+		region:    awsConfig.Region,
+		expiry:    expiry,
+	}
+}
+
+// PresignPut returns a PUT URL for key. Content-Type and Content-Length are part of the
+// signature, so S3 rejects an upload of any other type or size.
+func (u *ObjectUploads) PresignPut(ctx context.Context, key, contentType string, size int64) (*PresignedUpload, error) {
+	req, err := u.presigner.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(u.bucket),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	}, s3.WithPresignExpires(u.expiry))
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign upload of %s: %w", key, err)
+	}
+
+	headers := make(map[string]string, len(req.SignedHeader))
+	for name, values := range req.SignedHeader {
+		if !strings.EqualFold(name, "Host") {
+			headers[http.CanonicalHeaderKey(name)] = strings.Join(values, ",")
+		}
+	}
+
+	return &PresignedUpload{
+		UploadURL: req.URL,
+		Method:    req.Method,
+		Headers:   headers,
+		ObjectURL: fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", u.bucket, u.region, key),
+		ExpiresAt: time.Now().Add(u.expiry).UTC(),
+	}, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// QueryOptions tunes a QueryIndex call. The zero value reads one page (up to DynamoDB's
+// 1 MB limit) of full items in ascending sort key order.
+type QueryOptions struct {
+	Limit       int                             // Items evaluated per call (0 = no limit beyond 1 MB)
+	StartKey    map[string]types.AttributeValue // LastEvaluatedKey of the previous page (nil = first page)
+	ScanForward *bool                           // Sort key order; nil = ascending
+	Filter      *expression.ConditionBuilder    // Applied after the read, so it doesn't save RCU
+	Projection  []string                        // Attributes to return besides PK and SK (empty = all)
+}
+
+// QueryIndex queries indexName (or the table itself when indexName is empty) with
+// keyCond and unmarshals one page into resultSlice. It returns the page's
+// LastEvaluatedKey, which is nil once there is nothing left to read; pass it back as
+// StartKey for the next page. Like QueryPage, a Limit page can come back short (or
+// empty) when a Filter drops items, and projected fields outside Projection unmarshal
+// to their zero values.
+func (r *GenericRepository) QueryIndex(ctx context.Context, indexName string, keyCond expression.KeyConditionBuilder, opts QueryOptions, resultSlice interface{}) (map[string]types.AttributeValue, error) {
+	operation := "Query"
+	if indexName != "" {
+		operation += " " + indexName
+	}
+	ctx, done := r.observe(ctx, operation, "", "", attribute.Int("db.dynamodb.limit", opts.Limit))
+	defer done()
+
+	builder := expression.NewBuilder().WithKeyCondition(keyCond)
+	if opts.Filter != nil {
+		builder = builder.WithFilter(*opts.Filter)
+	}
+	if len(opts.Projection) > 0 {
+		names := expression.NamesList(expression.Name("PK"), expression.Name("SK"))
+		for _, name := range opts.Projection {
+			names = names.AddNames(expression.Name(name))
+		}
+		builder = builder.WithProjection(names)
+	}
+
+	expr, err := builder.Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table(ctx)),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ExclusiveStartKey:         opts.StartKey,
+		ScanIndexForward:          opts.ScanForward,
+		ReturnConsumedCapacity:    r.consumedCapacityMode(),
+	}
+	if indexName != "" {
+		input.IndexName = aws.String(indexName)
+	}
+	if opts.Limit > 0 {
+		input.Limit = aws.Int32(int32(opts.Limit))
+	}
+
+	output, err := r.client.Query(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", operation, err)
+	}
+	r.logConsumedCapacity(ctx, operation, output.ConsumedCapacity)
+
+	if len(opts.Projection) > 0 {
+		// Projected items lack attributes by design, so skip the schema-drift check
+		if err := r.encryptor.DecryptItems(ctx, output.Items...); err != nil {
+			return nil, err
+		}
+		err = attributevalue.UnmarshalListOfMaps(output.Items, resultSlice)
+	} else {
+		err = r.unmarshalItems(ctx, output.Items, resultSlice)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal items: %w", err)
+	}
+
+	return output.LastEvaluatedKey, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"hub-control-plane/backend/requestid"
+)
+
+// QueryStats is what a query cost. ScannedCount is the items DynamoDB read and Count
+// the ones that passed the filter; scanned far above returned means the filter, not
+// the key, is doing the selecting, and every scanned item is paid for in RCU.
+type QueryStats struct {
+	ConsumedCapacity float64 `json:"consumed_capacity"` // Read capacity units
+	ScannedCount     int     `json:"scanned_count"`
+	Count            int     `json:"count"`
+	Pages            int     `json:"pages"`
+}
+
+// QueryWithStats is QueryItems with an optional filter that also reports what the query
+// cost, summed over every page. Consumed capacity is always requested for it, whether or
+// not EnableConsumedCapacity is on. The stats are set on the call's span as well.
+func (r *GenericRepository) QueryWithStats(ctx context.Context, pk string, skPrefix string, filter *expression.ConditionBuilder) ([]map[string]types.AttributeValue, QueryStats, error) {
+	var stats QueryStats
+	ctx, done := r.observe(ctx, "Query", pk, skPrefix)
+	defer done()
+
+	keyCondition := expression.Key("PK").Equal(expression.Value(pk))
+	if skPrefix != "" {
+		keyCondition = keyCondition.And(expression.Key("SK").BeginsWith(skPrefix))
+	}
+	builder := expression.NewBuilder().WithKeyCondition(keyCondition)
+	if filter != nil {
+		builder = builder.WithFilter(*filter)
+	}
+	expr, err := builder.Build()
+	if err != nil {
+		return nil, stats, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table(ctx)),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ReturnConsumedCapacity:    types.ReturnConsumedCapacityTotal,
+	}
+
+	var items []map[string]types.AttributeValue
+	paginator := dynamodb.NewQueryPaginator(r.client, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, stats, fmt.Errorf("failed to query items: %w", err)
+		}
+		stats.Pages++
+		stats.ScannedCount += int(output.ScannedCount)
+		stats.Count += int(output.Count)
+		if output.ConsumedCapacity != nil {
+			stats.ConsumedCapacity += aws.ToFloat64(output.ConsumedCapacity.CapacityUnits)
+		}
+		items = append(items, output.Items...)
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Float64("aws.dynamodb.consumed_capacity", stats.ConsumedCapacity),
+		attribute.Int("aws.dynamodb.scanned_count", stats.ScannedCount),
+		attribute.Int("aws.dynamodb.count", stats.Count),
+	)
+	if r.returnConsumedCapacity {
+		requestid.Logf(ctx, "DynamoDB capacity: op=Query table=%s units=%.1f scanned=%d returned=%d",
+			r.table(ctx), stats.ConsumedCapacity, stats.ScannedCount, stats.Count)
+	}
+
+	if err := r.encryptor.DecryptItems(ctx, items...); err != nil {
+		return nil, stats, err
+	}
+	return items, stats, nil
+}
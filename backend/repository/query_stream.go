@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// StreamItem is one result of QueryStream: a decrypted item, or the error that ended
+// the stream (always the last value sent)
+type StreamItem struct {
+	Item map[string]types.AttributeValue
+	Err  error
+}
+
+// QueryStream sends every item with this PK (and optionally SK prefix) on the returned
+// channel, fetching pages as the consumer drains it. The channel is unbuffered, so at
+// most one page is held in memory and a slow consumer slows the reads down with it.
+// It is closed after the last item, after an error, or once ctx is done; consumers that
+// stop early must cancel ctx so the producer goroutine exits. Close stops the stream
+// before its next item with an ErrClosed error.
+func (r *GenericRepository) QueryStream(ctx context.Context, pk string, skPrefix string) (<-chan StreamItem, error) {
+	pager, err := r.QueryPages(ctx, pk, skPrefix)
+	if err != nil {
+		return nil, err
+	}
+	closing, err := r.startWorker()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan StreamItem)
+	go func() {
+		defer r.workers.Done()
+		defer close(out)
+		for pager.HasMorePages() {
+			select {
+			case <-closing:
+				sendStreamItem(ctx, nil, out, StreamItem{Err: ErrClosed})
+				return
+			default:
+			}
+
+			items, err := pager.nextItems(ctx)
+			if err == nil {
+				err = r.encryptor.DecryptItems(ctx, items...)
+			}
+			if err != nil {
+				sendStreamItem(ctx, nil, out, StreamItem{Err: err})
+				return
+			}
+			for _, item := range items {
+				if !sendStreamItem(ctx, closing, out, StreamItem{Item: item}) {
+					select {
+					case <-closing:
+						sendStreamItem(ctx, nil, out, StreamItem{Err: ErrClosed})
+					default:
+					}
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// sendStreamItem sends item unless ctx is done or closing is closed first, and reports
+// whether it was sent. A nil closing never fires.
+func sendStreamItem(ctx context.Context, closing <-chan struct{}, out chan<- StreamItem, item StreamItem) bool {
+	select {
+	case out <- item:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-closing:
+		return false
+	}
+}
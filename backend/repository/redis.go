@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
     "github.com/redis/go-redis/v9"
@@ -11,21 +12,22 @@ import (
 )
 
 type RedisCache struct {
-	client *redis.Client
+	client redis.UniversalClient
 	ttl    time.Duration
 }
 
-func NewRedisCache(address, password string) *RedisCache {
-	client := redis.NewClient(&redis.Options{
-		Addr:     address,
-		Password: password,
-		DB:       0, // use default DB
-	})
+// NewRedisCache connects to Redis in the mode opts selects (single node, cluster or
+// sentinel). It fails only on bad options; the connection is made lazily.
+func NewRedisCache(opts RedisOptions) (*RedisCache, error) {
+	client, err := newRedisClient(opts)
+	if err != nil {
+		return nil, err
+	}
 
 	return &RedisCache{
 		client: client,
 		ttl:    5 * time.Minute, // default TTL
-	}
+	}, nil
 }
 
 // Ping checks if Redis is connected
@@ -138,6 +140,141 @@ func (c *RedisCache) Close() error {
 }
 
 // GetClient returns the underlying Redis client for sharing
-func (c *RedisCache) GetClient() *redis.Client {
+func (c *RedisCache) GetClient() redis.UniversalClient {
 	return c.client
-}
\ No newline at end of file
+}
+
+// RedisAdapter adapts a Redis client (of any mode) to the Cache interface.
+// Keys (and patterns) are namespaced per tenant with tenant.CacheKey, so tenants
+// sharing a Redis never see each other's entries, and carry their type's cache schema
+// version (see UserCacheVersion).
+type RedisAdapter struct {
+	client redis.UniversalClient
+}
+
+// NewRedisAdapter wraps a Redis client as a Cache
+func NewRedisAdapter(client redis.UniversalClient) *RedisAdapter {
+	return &RedisAdapter{client: client}
+}
+
+// Get returns the raw value for key, or ErrCacheMiss
+func (a *RedisAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	val, err := a.client.Get(ctx, redisKey(ctx, key)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrCacheMiss
+	}
+	return val, err
+}
+
+// GetMany reads keys with one MGET. A cluster gets pipelined GETs instead, because MGET
+// fails with CROSSSLOT when the keys hash to different slots.
+func (a *RedisAdapter) GetMany(ctx context.Context, keys []string) (map[string][]byte, []string, error) {
+	found := make(map[string][]byte, len(keys))
+	if len(keys) == 0 {
+		return found, nil, nil
+	}
+	namespaced := make([]string, len(keys))
+	for i, key := range keys {
+		namespaced[i] = redisKey(ctx, key)
+	}
+
+	values := make([]interface{}, len(keys))
+	if _, ok := a.client.(*redis.ClusterClient); ok {
+		pipe := a.client.Pipeline()
+		cmds := make([]*redis.StringCmd, len(namespaced))
+		for i, key := range namespaced {
+			cmds[i] = pipe.Get(ctx, key)
+		}
+		if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+			return nil, nil, err
+		}
+		for i, cmd := range cmds {
+			if cmd.Err() == nil {
+				values[i] = cmd.Val()
+			}
+		}
+	} else {
+		var err error
+		if values, err = a.client.MGet(ctx, namespaced...).Result(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var misses []string
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			found[keys[i]] = []byte(s)
+		} else {
+			misses = append(misses, keys[i])
+		}
+	}
+	return found, misses, nil
+}
+
+// Set stores value under key with the given TTL
+func (a *RedisAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return a.client.Set(ctx, redisKey(ctx, key), value, ttl).Err()
+}
+
+// Del removes the given keys
+func (a *RedisAdapter) Del(ctx context.Context, keys ...string) error {
+	namespaced := make([]string, len(keys))
+	for i, key := range keys {
+		namespaced[i] = redisKey(ctx, key)
+	}
+	_, err := delKeys(ctx, a.client, namespaced)
+	return err
+}
+
+// Expire resets key's TTL with EXPIRE
+func (a *RedisAdapter) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return a.client.Expire(ctx, redisKey(ctx, key), ttl).Err()
+}
+
+// scanBatchSize is the COUNT hint passed to SCAN when deleting by pattern
+const scanBatchSize = 100
+
+// DelPattern removes every key matching pattern.
+// Uses SCAN rather than KEYS so large keyspaces don't block Redis. A cluster is
+// scanned master by master, since SCAN only walks the node it is sent to.
+func (a *RedisAdapter) DelPattern(ctx context.Context, pattern string) (int, error) {
+	pattern = redisKey(ctx, pattern)
+	cluster, ok := a.client.(*redis.ClusterClient)
+	if !ok {
+		return scanDelete(ctx, a.client, pattern)
+	}
+
+	var mu sync.Mutex
+	deleted := 0
+	err := cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+		n, err := scanDelete(ctx, node, pattern)
+		mu.Lock()
+		deleted += n
+		mu.Unlock()
+		return err
+	})
+	return deleted, err
+}
+
+// scanDelete is DelPattern over the keyspace of one client
+func scanDelete(ctx context.Context, client redis.Cmdable, pattern string) (int, error) {
+	deleted := 0
+	var cursor uint64
+	for {
+		keys, next, err := client.Scan(ctx, cursor, pattern, scanBatchSize).Result()
+		if err != nil {
+			return deleted, err
+		}
+		n, err := delKeys(ctx, client, keys)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+		if next == 0 {
+			return deleted, nil
+		}
+		cursor = next
+	}
+}
+
+var _ Cache = (*RedisAdapter)(nil)
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis deployment modes accepted by RedisOptions.Mode
+const (
+	RedisModeSingle   = "single"   // One node at Addresses[0]
+	RedisModeCluster  = "cluster"  // Redis Cluster; Addresses seed the slot map
+	RedisModeSentinel = "sentinel" // Sentinel-managed failover; Addresses are the sentinels
+)
+
+// RedisOptions selects and configures the Redis client
+type RedisOptions struct {
+	Mode       string   // RedisModeSingle (default), RedisModeCluster or RedisModeSentinel
+	Addresses  []string // host:port of the node, the cluster seeds or the sentinels
+	MasterName string   // Sentinel master name (sentinel mode only)
+	Password   string
+}
+
+// newRedisClient builds the client for opts.Mode. Every mode returns a
+// redis.UniversalClient, so the cache, session store and locker don't care which.
+func newRedisClient(opts RedisOptions) (redis.UniversalClient, error) {
+	if len(opts.Addresses) == 0 {
+		return nil, fmt.Errorf("redis %s mode needs at least one address", opts.Mode)
+	}
+	switch opts.Mode {
+	case "", RedisModeSingle:
+		return redis.NewClient(&redis.Options{
+			Addr:     opts.Addresses[0],
+			Password: opts.Password,
+			DB:       0, // use default DB
+		}), nil
+	case RedisModeCluster:
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:    opts.Addresses,
+			Password: opts.Password,
+		}), nil
+	case RedisModeSentinel:
+		if opts.MasterName == "" {
+			return nil, fmt.Errorf("redis sentinel mode needs a master name")
+		}
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:    opts.MasterName,
+			SentinelAddrs: opts.Addresses,
+			Password:      opts.Password,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown redis mode %q (want %s, %s or %s)", opts.Mode, RedisModeSingle, RedisModeCluster, RedisModeSentinel)
+	}
+}
+
+// delKeys deletes keys one DEL per key in a single pipeline. A multi-key DEL fails with
+// CROSSSLOT on a cluster when the keys hash to different slots; pipelined single-key
+// DELs are routed per key and still cost one round trip per node.
+func delKeys(ctx context.Context, client redis.Cmdable, keys []string) (int, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	pipe := client.Pipeline()
+	cmds := make([]*redis.IntCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.Del(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, cmd := range cmds {
+		deleted += int(cmd.Val())
+	}
+	return deleted, nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// releaseScript deletes the lock only if it still holds our token, so a holder whose
+// lock expired can't release the lock another replica has since acquired
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisLocker implements Locker with SET NX PX on a single Redis key
+type RedisLocker struct {
+	client redis.UniversalClient
+}
+
+// NewRedisLocker creates a Locker over a Redis client
+func NewRedisLocker(client redis.UniversalClient) *RedisLocker {
+	return &RedisLocker{client: client}
+}
+
+// TryLock acquires key for ttl without waiting
+func (l *RedisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, bool, error) {
+	token := uuid.New().String()
+
+	ok, err := l.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+	if !ok {
+		return nil, false, nil
+	}
+
+	unlock := func(ctx context.Context) error {
+		if err := releaseScript.Run(ctx, l.client, []string{key}, token).Err(); err != nil {
+			return fmt.Errorf("failed to release lock %s: %w", key, err)
+		}
+		return nil
+	}
+	return unlock, true, nil
+}
+
+var _ Locker = (*RedisLocker)(nil)
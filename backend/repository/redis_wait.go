@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hub-control-plane/backend/requestid"
+)
+
+// maxStartupBackoff caps the doubling delay between startup pings
+const maxStartupBackoff = 30 * time.Second
+
+// WaitUntilReachable pings Redis up to attempts times, sleeping backoff after the first
+// failure and doubling it (up to 30s) after each one after that. It returns nil as soon
+// as a ping succeeds, else the last ping error. Meant for startup, when Redis may still
+// be coming up alongside this service.
+func (c *RedisCache) WaitUntilReachable(ctx context.Context, attempts int, backoff time.Duration) error {
+	return retryPing(ctx, c.Ping, attempts, backoff)
+}
+
+// retryPing is WaitUntilReachable over any ping function
+func retryPing(ctx context.Context, ping func(context.Context) error, attempts int, backoff time.Duration) error {
+	attempts = max(attempts, 1)
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = ping(ctx); err == nil {
+			return nil
+		}
+		if attempt == attempts {
+			break
+		}
+
+		requestid.Logf(ctx, "Redis not reachable (attempt %d/%d): %v; retrying in %s", attempt, attempts, err, backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxStartupBackoff)
+	}
+	return fmt.Errorf("redis not reachable after %d attempts: %w", attempts, err)
+}
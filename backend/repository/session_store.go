@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrSessionNotFound is returned for tokens that are unknown, expired or revoked
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionStore keeps revocable auth sessions in Redis.
+// Tokens are opaque random strings; only their SHA-256 is stored, so a Redis dump
+// doesn't leak usable tokens. Keys:
+//
+//	session:<hash>         -> [<tenant>:]<user ID>, expires after the session TTL
+//	sessions:user:<userID> -> set of that user's session hashes (for RevokeAll)
+type SessionStore struct {
+	client redis.UniversalClient
+	ttl    time.Duration
+}
+
+// NewSessionStore creates a session store whose sessions live for ttl
+func NewSessionStore(client redis.UniversalClient, ttl time.Duration) *SessionStore {
+	return &SessionStore{client: client, ttl: ttl}
+}
+
+// Create starts a session for userID and returns its bearer token. A non-empty tenantID
+// makes requests on the session act for that tenant (see package tenant).
+func (s *SessionStore) Create(ctx context.Context, userID, tenantID string) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate session token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	hash := hashToken(token)
+
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, sessionKey(hash), sessionValue(userID, tenantID), s.ttl)
+	pipe.SAdd(ctx, userSessionsKey(userID), hash)
+	pipe.Expire(ctx, userSessionsKey(userID), s.ttl) // outlives every session in it
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return token, nil
+}
+
+// Validate returns the user (and tenant, "" if none) a token belongs to, or ErrSessionNotFound
+func (s *SessionStore) Validate(ctx context.Context, token string) (userID, tenantID string, err error) {
+	value, err := s.client.Get(ctx, sessionKey(hashToken(token))).Result()
+	if err == redis.Nil {
+		return "", "", ErrSessionNotFound
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to validate session: %w", err)
+	}
+	userID, tenantID = parseSessionValue(value)
+	return userID, tenantID, nil
+}
+
+// Revoke ends a single session. Revoking an unknown token is not an error.
+func (s *SessionStore) Revoke(ctx context.Context, token string) error {
+	hash := hashToken(token)
+
+	value, err := s.client.GetDel(ctx, sessionKey(hash)).Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	userID, _ := parseSessionValue(value)
+	if err := s.client.SRem(ctx, userSessionsKey(userID), hash).Err(); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// RevokeAll ends every session of a user ("log out everywhere") and returns how many there were
+func (s *SessionStore) RevokeAll(ctx context.Context, userID string) (int, error) {
+	hashes, err := s.client.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	keys := make([]string, 0, len(hashes)+1)
+	for _, hash := range hashes {
+		keys = append(keys, sessionKey(hash))
+	}
+	keys = append(keys, userSessionsKey(userID))
+
+	// Some hashes may belong to sessions that already expired
+	deleted, err := delKeys(ctx, s.client, keys)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	if len(hashes) > 0 {
+		deleted-- // the set itself
+	}
+	return deleted, nil
+}
+
+// sessionValue is what session:<hash> stores. Sessions without a tenant store the bare
+// user ID, as sessions did before tenants existed.
+func sessionValue(userID, tenantID string) string {
+	if tenantID == "" {
+		return userID
+	}
+	return tenantID + ":" + userID
+}
+
+// parseSessionValue splits a stored session value; tenant IDs never contain ':'
+func parseSessionValue(value string) (userID, tenantID string) {
+	if tenantID, userID, ok := strings.Cut(value, ":"); ok {
+		return userID, tenantID
+	}
+	return value, ""
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func sessionKey(hash string) string {
+	return "session:" + hash
+}
+
+func userSessionsKey(userID string) string {
+	return "sessions:user:" + userID
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// CheckTable verifies that the configured table exists, is ACTIVE and has the key schema
+// this repository writes: string PK (hash) and SK (range), plus GSI1 keyed on string
+// GSI1PK (hash) and GSI1SK (range). Tenant tables are not checked.
+func (r *GenericRepository) CheckTable(ctx context.Context) error {
+	output, err := r.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(r.tableName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe table %s: %w", r.tableName, err)
+	}
+	table := output.Table
+
+	if table.TableStatus != types.TableStatusActive {
+		return fmt.Errorf("table %s is %s, not ACTIVE", r.tableName, table.TableStatus)
+	}
+
+	attributeTypes := make(map[string]types.ScalarAttributeType, len(table.AttributeDefinitions))
+	for _, def := range table.AttributeDefinitions {
+		attributeTypes[aws.ToString(def.AttributeName)] = def.AttributeType
+	}
+	if err := checkKeySchema(table.KeySchema, attributeTypes, "PK", "SK"); err != nil {
+		return fmt.Errorf("table %s: %w", r.tableName, err)
+	}
+
+	for _, index := range table.GlobalSecondaryIndexes {
+		if aws.ToString(index.IndexName) == "GSI1" {
+			if err := checkKeySchema(index.KeySchema, attributeTypes, "GSI1PK", "GSI1SK"); err != nil {
+				return fmt.Errorf("table %s index GSI1: %w", r.tableName, err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("table %s has no GSI1 index", r.tableName)
+}
+
+// checkKeySchema checks that schema is exactly hash (S) + rangeKey (S)
+func checkKeySchema(schema []types.KeySchemaElement, attributeTypes map[string]types.ScalarAttributeType, hash, rangeKey string) error {
+	want := map[string]types.KeyType{hash: types.KeyTypeHash, rangeKey: types.KeyTypeRange}
+	if len(schema) != len(want) {
+		return fmt.Errorf("key schema has %d attributes, want %s (HASH) and %s (RANGE)", len(schema), hash, rangeKey)
+	}
+	for _, element := range schema {
+		name := aws.ToString(element.AttributeName)
+		if want[name] != element.KeyType {
+			return fmt.Errorf("key attribute %s is %s, want %s (HASH) and %s (RANGE)", name, element.KeyType, hash, rangeKey)
+		}
+		if attributeTypes[name] != types.ScalarAttributeTypeS {
+			return fmt.Errorf("key attribute %s has type %q, want S", name, attributeTypes[name])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// MaxTransactWriteItems is DynamoDB's limit on the items in one TransactWriteItems call
+const MaxTransactWriteItems = 100
+
+// TransactionTooLargeError is returned when a batch that must be atomic needs more than
+// one transaction
+type TransactionTooLargeError struct {
+	Items int // Writes in the batch
+	Limit int // Writes one transaction may hold
+}
+
+func (e *TransactionTooLargeError) Error() string {
+	return fmt.Sprintf("atomic batch has %d items, but one transaction holds at most %d", e.Items, e.Limit)
+}
+
+// ClampTransactionItems returns n limited to 1..MaxTransactWriteItems, with 0 (or less)
+// meaning MaxTransactWriteItems
+func ClampTransactionItems(n int) int {
+	if n <= 0 || n > MaxTransactWriteItems {
+		return MaxTransactWriteItems
+	}
+	return n
+}
+
+// UnitOfWork collects the writes of one multi-item operation and commits them with
+// TransactWrite, so a service method can state what changes instead of coordinating
+// the calls itself. Up to MaxItems writes commit all-or-nothing. Larger units are split
+// into chunks of MaxItems, committed in the order the writes were added, and a failed
+// chunk leaves the earlier ones applied; units that must not be split are marked
+// Strict, which makes Commit refuse them instead. A UnitOfWork is not safe for
+// concurrent use and is meant to live for one request.
+type UnitOfWork struct {
+	repo     SingleTableRepository
+	writes   []func(tx *TxItems)
+	maxItems int
+	strict   bool
+}
+
+// NewUnitOfWork starts an empty unit of work that commits through repo in chunks of
+// MaxTransactWriteItems
+func NewUnitOfWork(repo SingleTableRepository) *UnitOfWork {
+	return &UnitOfWork{repo: repo, maxItems: MaxTransactWriteItems}
+}
+
+// SetMaxItems sets the chunk size, clamped with ClampTransactionItems
+func (u *UnitOfWork) SetMaxItems(n int) *UnitOfWork {
+	u.maxItems = ClampTransactionItems(n)
+	return u
+}
+
+// SetStrict makes Commit fail with *TransactionTooLargeError, writing nothing, when the
+// unit doesn't fit in one transaction
+func (u *UnitOfWork) SetStrict(strict bool) *UnitOfWork {
+	u.strict = strict
+	return u
+}
+
+// Put writes item, replacing any existing item with its key
+func (u *UnitOfWork) Put(item BaseModel) {
+	u.add(func(tx *TxItems) { tx.Puts = append(tx.Puts, item) })
+}
+
+// Create writes item, cancelling the transaction if an item with its key exists
+func (u *UnitOfWork) Create(item BaseModel) {
+	u.add(func(tx *TxItems) { tx.Creates = append(tx.Creates, item) })
+}
+
+// Delete removes the item with this key
+func (u *UnitOfWork) Delete(pk, sk string) {
+	u.add(func(tx *TxItems) { tx.Deletes = append(tx.Deletes, map[string]string{"PK": pk, "SK": sk}) })
+}
+
+// Update applies a (possibly conditional) update expression
+func (u *UnitOfWork) Update(update TxUpdate) {
+	u.add(func(tx *TxItems) { tx.Updates = append(tx.Updates, update) })
+}
+
+// Check asserts a condition on an item the unit doesn't otherwise write
+func (u *UnitOfWork) Check(check TxCheck) {
+	u.add(func(tx *TxItems) { tx.Checks = append(tx.Checks, check) })
+}
+
+// Len returns the number of writes collected so far
+func (u *UnitOfWork) Len() int {
+	return len(u.writes)
+}
+
+// add records one write; Commit decides which transaction it lands in
+func (u *UnitOfWork) add(write func(tx *TxItems)) {
+	u.writes = append(u.writes, write)
+}
+
+// Commit applies the collected writes and empties the unit. An empty unit commits
+// nothing. Errors from TransactWrite are wrapped, so errors.Is(err, ErrConditionFailed)
+// still reports a failed condition.
+func (u *UnitOfWork) Commit(ctx context.Context) error {
+	writes := u.writes
+	u.writes = nil
+
+	if u.strict && len(writes) > u.maxItems {
+		return &TransactionTooLargeError{Items: len(writes), Limit: u.maxItems}
+	}
+
+	chunks := (len(writes) + u.maxItems - 1) / u.maxItems
+	for i := 0; i < chunks; i++ {
+		var tx TxItems
+		for _, write := range writes[i*u.maxItems : min((i+1)*u.maxItems, len(writes))] {
+			write(&tx)
+		}
+		if err := u.repo.TransactWrite(ctx, tx); err != nil {
+			if i == 0 {
+				return err
+			}
+			return fmt.Errorf("chunk %d of %d failed after %d were committed: %w", i+1, chunks, i, err)
+		}
+	}
+	return nil
+}
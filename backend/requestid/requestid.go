@@ -0,0 +1,33 @@
+package requestid
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// Header is the HTTP header a request ID is read from and echoed back on
+const Header = "X-Request-ID"
+
+type contextKey struct{}
+
+// WithID returns a copy of ctx carrying the request ID
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID stored in ctx, or "" if there is none
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Logf logs like log.Printf, prefixed with the request ID when ctx carries one,
+// so every cache/DB line for a single request can be grepped together
+func Logf(ctx context.Context, format string, args ...interface{}) {
+	if id := FromContext(ctx); id != "" {
+		log.Printf("[req=%s] %s", id, fmt.Sprintf(format, args...))
+		return
+	}
+	log.Printf(format, args...)
+}
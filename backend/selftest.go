@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"hub-control-plane/backend/config"
+	"hub-control-plane/backend/featureflag"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/service"
+)
+
+// selfTestTimeout bounds each dependency check so an unreachable host fails fast
+const selfTestTimeout = 5 * time.Second
+
+// selfTestCheck is one row of the --selftest report
+type selfTestCheck struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// selfTestChecks returns the checks --selftest runs, in order: configuration, AWS
+// credentials/region, the DynamoDB table and its key schema, and Redis. A dependency
+// whose setup failed reports that instead of being skipped, so every row has a result.
+func selfTestChecks(cfg *config.Config) []selfTestCheck {
+	var repo *repository.GenericRepository
+	awsErr := errors.New("AWS config not loaded")
+
+	return []selfTestCheck{
+		{"config", func(ctx context.Context) error {
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+			for _, value := range []string{cfg.CacheStrategyUser, cfg.CacheStrategyContact} {
+				if _, err := service.ParseCacheStrategy(value); err != nil {
+					return err
+				}
+			}
+			_, err := featureflag.ParseDefaults(service.DefaultFlags, cfg.FeatureFlags)
+			return err
+		}},
+		{"aws", func(ctx context.Context) error {
+			awsConfig, err := config.NewAWSConfig(cfg.AWSRegion)
+			if err != nil {
+				awsErr = err
+				return err
+			}
+			repo = repository.NewGenericRepository(awsConfig, cfg.DynamoDBTableName)
+			return nil
+		}},
+		{"dynamodb", func(ctx context.Context) error {
+			if repo == nil {
+				return awsErr
+			}
+			return repo.CheckTable(ctx)
+		}},
+		{"redis", func(ctx context.Context) error {
+			cache, err := repository.NewRedisCache(redisOptions(cfg))
+			if err != nil {
+				return err
+			}
+			defer cache.GetClient().Close()
+			if err := cache.Ping(ctx); err != nil {
+				return fmt.Errorf("failed to ping %v: %w", cfg.RedisNodes(), err)
+			}
+			return nil
+		}},
+	}
+}
+
+// runSelfTest runs every check and writes a pass/fail table to w. It reports whether
+// all of them passed.
+func runSelfTest(ctx context.Context, w io.Writer, checks []selfTestCheck) bool {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "CHECK\tRESULT\tDETAIL")
+
+	passed := true
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, selfTestTimeout)
+		start := time.Now()
+		err := check.run(checkCtx)
+		cancel()
+
+		if err != nil {
+			passed = false
+			fmt.Fprintf(table, "%s\tFAIL\t%v\n", check.name, err)
+			continue
+		}
+		fmt.Fprintf(table, "%s\tPASS\t%s\n", check.name, time.Since(start).Round(time.Millisecond))
+	}
+
+	table.Flush()
+	return passed
+}
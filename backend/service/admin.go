@@ -0,0 +1,18 @@
+package service
+
+import "context"
+
+type adminKey struct{}
+
+// WithAdmin marks ctx as acting for an operator who presented the admin token.
+// Transports without route-level guards (GraphQL) check it with IsAdmin before
+// running admin operations.
+func WithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey{}, true)
+}
+
+// IsAdmin reports whether ctx was marked with WithAdmin
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+	return admin
+}
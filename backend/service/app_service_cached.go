@@ -2,195 +2,550 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/google/uuid"
-	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+	"hub-control-plane/backend/clock"
 	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/pagination"
 	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/requestid"
+	"hub-control-plane/backend/tracing"
+	"hub-control-plane/backend/validation"
+	"hub-control-plane/backend/webhook"
 )
 
+// ErrContactExists is returned by CreateContact when duplicates are rejected
+// and the user already has a contact with the same email
+var ErrContactExists = errors.New("contact with this email already exists")
+
+// ErrUserNotFound is returned when the user a request names doesn't exist
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrSameOwner is returned by MoveContact when the target user already owns the contact
+var ErrSameOwner = errors.New("contact already belongs to that user")
+
+// UserHasContactsError is returned by DeleteUser when the user still owns contacts.
+// Deleting only the METADATA item would orphan them, so callers must cascade instead.
+type UserHasContactsError struct {
+	ContactCount int
+}
+
+func (e *UserHasContactsError) Error() string {
+	return fmt.Sprintf("user still has %d contacts; delete them first or use cascade", e.ContactCount)
+}
+
 // AppServiceWithCache provides business logic with integrated caching
 type AppServiceWithCache struct {
-	repo  *repository.GenericRepository
-	cache *redis.Client
-	ttl   time.Duration
+	repo  repository.SingleTableRepository
+	cache repository.Cache
+
+	// Adjustable at runtime by admins, so guarded
+	ttlsMu sync.RWMutex
+	ttls   CacheTTLs
+
+	// List results above either limit are served uncached (0 = no limit)
+	maxCacheItems int
+	maxCacheBytes int
+
+	// Per-entity list cache strategy (missing = CacheAside)
+	cacheStrategies map[string]CacheStrategy
+
+	// Contact lifecycle notifications (nil = disabled)
+	webhooks *webhook.Dispatcher
+
+	// Invalidate users:list when a single-user read sees a newer copy than the list holds
+	readRepair bool
+
+	// How long soft-deleted contacts can be restored
+	trashRetention time.Duration
+
+	// Whether CloneContact keeps the source's favorite flag
+	cloneKeepsFavorite bool
+
+	// How long past their TTL stale-while-revalidate keys are served (0 = off)
+	staleWindow time.Duration
+	refreshes   singleflight.Group // one background refresh per stale key
+
+	// Contact email domains for users without their own allowlist (empty = any)
+	emailDomains []string
+
+	// Reset user/contact entries to their full TTL on every cache hit
+	slidingExpiry bool
+
+	// Direct contact avatar uploads (nil = disabled)
+	avatars        repository.UploadPresigner
+	avatarMaxBytes int64
+
+	// Feature flags for optional behaviours (nil = all on)
+	flags FlagChecker
+
+	// Writes per DynamoDB transaction when multi-item writes are chunked
+	maxTxItems int
+
+	// Format new cache entries are written in (nil = JSON)
+	cacheCodec CacheCodec
+
+	// Background enrichment of new contacts (nil = disabled)
+	enrichments *enrichmentPool
+
+	// Index ListUserContacts reads instead of the table ("" = the table)
+	contactListIndex string
 }
 
 // NewAppServiceWithCache creates a new application service with caching
-func NewAppServiceWithCache(repo *repository.GenericRepository, cache *redis.Client) *AppServiceWithCache {
+func NewAppServiceWithCache(repo repository.SingleTableRepository, cache repository.Cache) *AppServiceWithCache {
 	return &AppServiceWithCache{
 		repo:  repo,
 		cache: cache,
-		ttl:   5 * time.Minute, // Default cache TTL
+		ttls:  DefaultCacheTTLs,
+
+		trashRetention: DefaultTrashRetention,
+		avatarMaxBytes: DefaultAvatarMaxBytes,
+		maxTxItems:     repository.MaxTransactWriteItems,
+	}
+}
+
+// CacheTTLs sets how long each kind of cache entry lives
+type CacheTTLs struct {
+	User      time.Duration // user:<id> and contact:<userID>:<id>
+	List      time.Duration // users:list and contacts:<view>:user:<id>
+	Dashboard time.Duration // dashboard:<id>; shorter since it aggregates several entities
+}
+
+// DefaultCacheTTLs are used until SetCacheTTLs is called
+var DefaultCacheTTLs = CacheTTLs{
+	User:      5 * time.Minute,
+	List:      5 * time.Minute,
+	Dashboard: 2 * time.Minute,
+}
+
+// SetCacheTTLs overrides the TTL per cache key type; zero fields keep their current value.
+// Safe to call while serving; entries already cached keep the TTL they were written with.
+func (s *AppServiceWithCache) SetCacheTTLs(ttls CacheTTLs) {
+	s.ttlsMu.Lock()
+	defer s.ttlsMu.Unlock()
+
+	if ttls.User > 0 {
+		s.ttls.User = ttls.User
 	}
+	if ttls.List > 0 {
+		s.ttls.List = ttls.List
+	}
+	if ttls.Dashboard > 0 {
+		s.ttls.Dashboard = ttls.Dashboard
+	}
+}
+
+// CacheTTLs returns the TTLs currently applied to new cache entries
+func (s *AppServiceWithCache) CacheTTLs() CacheTTLs {
+	s.ttlsMu.RLock()
+	defer s.ttlsMu.RUnlock()
+	return s.ttls
+}
+
+// SetCacheLimits caps the size of list results that get cached. A list with more
+// than maxItems entries, or larger than maxBytes once marshalled, is skipped so one
+// huge tenant can't bloat Redis for everyone. Zero disables the respective check.
+func (s *AppServiceWithCache) SetCacheLimits(maxItems, maxBytes int) {
+	s.maxCacheItems = maxItems
+	s.maxCacheBytes = maxBytes
+}
+
+// SetMaxTransactionItems sets how many writes go into one DynamoDB transaction when bulk
+// writes are chunked (0 or more than DynamoDB's 100 means 100). Smaller chunks fail
+// less as a whole but make more of the batch non-atomic. Single-entity writes that
+// must land together, like a contact and its owner's count, are never chunked.
+func (s *AppServiceWithCache) SetMaxTransactionItems(n int) {
+	s.maxTxItems = repository.ClampTransactionItems(n)
+}
+
+// SetWebhooks enables contact lifecycle webhooks. Events are dispatched
+// asynchronously after each successful create/update/delete.
+func (s *AppServiceWithCache) SetWebhooks(dispatcher *webhook.Dispatcher) {
+	s.webhooks = dispatcher
 }
 
 // ============================================================================
 // USER OPERATIONS WITH CACHING
 // ============================================================================
 
-// CreateUser creates a new user
-// Flow: Save to DB → Cache individual → Invalidate list cache
+// CreateUser creates a new user. Its email is reserved by an EMAIL# sentinel written in
+// the same transaction, so a duplicate email (ignoring case) fails with ErrEmailTaken.
+// Flow: Save user + email sentinel to DB → Cache individual → Invalidate or patch list cache (see CacheStrategy)
 func (s *AppServiceWithCache) CreateUser(ctx context.Context, email, firstName, lastName string) (*models.UserEntity, error) {
+	ctx, span := tracing.Start(ctx, "AppService.CreateUser")
+	defer span.End()
+
 	userID := uuid.New().String()
 	user := models.NewUser(userID, email, firstName, lastName)
 
-	// 1. Save to DynamoDB
-	if err := s.repo.PutIfNotExists(ctx, user); err != nil {
-		if errors.Is(err, repository.ErrAlreadyExists) {
-			return nil, errors.New("user already exists")
+	// 1. Save to DynamoDB together with the email sentinel; either both are written or neither
+	user.SetTimestamps()
+	tx := repository.TxItems{Creates: []repository.BaseModel{user, models.NewUserEmailIndex(email, userID)}}
+	if err := s.repo.TransactWrite(ctx, tx); err != nil {
+		if errors.Is(err, repository.ErrConditionFailed) {
+			// The ID is fresh, so the sentinel is what already existed
+			return nil, ErrEmailTaken
 		}
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
 	// 2. Cache the individual user
 	if err := s.cacheUser(ctx, user); err != nil {
-		log.Printf("Warning: failed to cache user: %v", err)
+		requestid.Logf(ctx, "Warning: failed to cache user: %v", err)
 	}
 
-	// 3. Invalidate the user list cache
-	if err := s.invalidateUserListCache(ctx); err != nil {
-		log.Printf("Warning: failed to invalidate user list cache: %v", err)
+	// 3. Invalidate (cache-aside) or patch (write-through) the user list cache
+	if err := s.refreshUserListCache(ctx, user); err != nil {
+		requestid.Logf(ctx, "Warning: failed to refresh user list cache: %v", err)
 	}
 
-	log.Printf("Created user: %s (%s)", userID, email)
+	requestid.Logf(ctx, "Created user: %s (%s)", userID, email)
 	return user, nil
 }
 
 // GetUser retrieves a user by ID with caching
-// Flow: Check cache → If miss, get from DB → Cache it → Return
+// Flow: Check cache (stale → serve + refresh in background) → If miss, get from DB → Cache it → Return
 func (s *AppServiceWithCache) GetUser(ctx context.Context, userID string) (*models.UserEntity, error) {
+	ctx, span := tracing.Start(ctx, "AppService.GetUser")
+	defer span.End()
+
 	cacheKey := fmt.Sprintf("user:%s", userID)
 
 	// 1. Try to get from cache
-	cached, err := s.cache.Get(ctx, cacheKey).Result()
+	cached, stale, err := s.cacheGet(ctx, cacheKey)
 	if err == nil {
 		// Cache HIT!
-		log.Printf("Cache HIT for user: %s", userID)
+		requestid.Logf(ctx, "Cache HIT for user: %s", userID)
 		var user models.UserEntity
-		if err := json.Unmarshal([]byte(cached), &user); err == nil {
+		if err := decodeCached(cached, &user); err == nil {
+			if stale {
+				s.revalidate(ctx, cacheKey, func(ctx context.Context) error {
+					_, err := s.loadUser(ctx, userID)
+					return err
+				})
+			}
+			s.slideExpiry(ctx, cacheKey)
+			s.scheduleUserListRepair(ctx, &user)
 			return &user, nil
 		}
 	}
 
 	// 2. Cache MISS - get from DynamoDB
-	log.Printf("Cache MISS for user: %s", userID)
-	user := &models.UserEntity{}
-	pk := fmt.Sprintf("USER#%s", userID)
-	sk := "METADATA"
+	requestid.Logf(ctx, "Cache MISS for user: %s", userID)
+	if IsCacheOnly(ctx) {
+		return nil, ErrNotCached
+	}
+	user, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := s.repo.Get(ctx, pk, sk, user); err != nil {
+	// 3. Drop the cached list if it's lagging behind this copy
+	s.scheduleUserListRepair(ctx, user)
+
+	return user, nil
+}
+
+// loadUser reads a user from DynamoDB and caches it
+func (s *AppServiceWithCache) loadUser(ctx context.Context, userID string) (*models.UserEntity, error) {
+	user := &models.UserEntity{}
+	if err := s.repo.Get(ctx, fmt.Sprintf("USER#%s", userID), "METADATA", user); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	// 3. Cache the result
 	if err := s.cacheUser(ctx, user); err != nil {
-		log.Printf("Warning: failed to cache user: %v", err)
+		requestid.Logf(ctx, "Warning: failed to cache user: %v", err)
 	}
-
 	return user, nil
 }
 
-// UpdateUser updates user information
-// Flow: Update in DB → Update cache → Invalidate list cache
+// UpdateUser updates user information. Changing the email moves its EMAIL# sentinel and
+// fails with ErrEmailTaken if another user holds the new one.
+// Flow: Reserve new email → Update in DB → Release old (or, on failure, new) email → Update cache → Invalidate or patch list cache (see CacheStrategy)
 func (s *AppServiceWithCache) UpdateUser(ctx context.Context, userID string, updates map[string]interface{}) (*models.UserEntity, error) {
+	return s.updateUser(ctx, userID, updates, time.Time{})
+}
+
+// updateUser is UpdateUser, conditional on the user being unmodified since since unless it is zero
+func (s *AppServiceWithCache) updateUser(ctx context.Context, userID string, updates map[string]interface{}, since time.Time) (*models.UserEntity, error) {
+	ctx, span := tracing.Start(ctx, "AppService.UpdateUser")
+	defer span.End()
+
+	if err := validation.UpdateFields(updates); err != nil {
+		return nil, err
+	}
+
 	pk := fmt.Sprintf("USER#%s", userID)
 	sk := "METADATA"
 
-	// 1. Update in DynamoDB
-	if err := s.repo.Update(ctx, pk, sk, updates); err != nil {
+	// 1. Reserve a changed email before anyone else can take it
+	emailDone, err := s.reserveChangedEmail(ctx, userID, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Update in DynamoDB, then release whichever email the user no longer holds
+	err = s.applyUpdate(ctx, pk, sk, updates, since)
+	emailDone(ctx, err == nil)
+	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, errors.New("user not found")
 		}
+		if errors.Is(err, ErrPreconditionFailed) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
-	// 2. Get the updated user
-	user, err := s.GetUser(ctx, userID)
-	if err != nil {
-		return nil, err
+	// 3. Get the updated user (straight from DB - the cached copy is now stale)
+	user := &models.UserEntity{}
+	if err := s.repo.Get(ctx, pk, sk, user); err != nil {
+		return nil, fmt.Errorf("failed to get updated user: %w", err)
 	}
 
-	// 3. Update cache (GetUser already cached it, but let's be explicit)
+	// 4. Update cache
 	if err := s.cacheUser(ctx, user); err != nil {
-		log.Printf("Warning: failed to update cache: %v", err)
+		requestid.Logf(ctx, "Warning: failed to update cache: %v", err)
 	}
 
-	// 4. Invalidate the user list cache
-	if err := s.invalidateUserListCache(ctx); err != nil {
-		log.Printf("Warning: failed to invalidate user list cache: %v", err)
+	// 5. Invalidate (cache-aside) or patch (write-through) the user list cache
+	if err := s.refreshUserListCache(ctx, user); err != nil {
+		requestid.Logf(ctx, "Warning: failed to refresh user list cache: %v", err)
 	}
 
-	log.Printf("Updated user: %s", userID)
+	requestid.Logf(ctx, "Updated user: %s", userID)
 	return user, nil
 }
 
-// DeleteUser deletes a user
-// Flow: Delete from DB → Delete from cache → Invalidate list cache
+// DeleteUser deletes a user that has no contacts
+// Flow: Check for contacts → Delete user + email sentinel from DB → Delete from cache → Invalidate list cache
+// Returns *UserHasContactsError if any contacts remain; use DeleteUserCascade to purge them too.
+// A missing user yields an error wrapping repository.ErrNotFound.
 func (s *AppServiceWithCache) DeleteUser(ctx context.Context, userID string) error {
+	ctx, span := tracing.Start(ctx, "AppService.DeleteUser")
+	defer span.End()
+
 	pk := fmt.Sprintf("USER#%s", userID)
 	sk := "METADATA"
 
-	// 1. Delete from DynamoDB
-	if err := s.repo.Delete(ctx, pk, sk); err != nil {
+	// 1. Refuse to orphan contacts (count every page in DB, not the list cache, so the check is exact)
+	count, err := s.repo.Count(ctx, pk, "CONTACT#")
+	if err != nil {
+		return fmt.Errorf("failed to check user contacts: %w", err)
+	}
+	if count > 0 {
+		return &UserHasContactsError{ContactCount: count}
+	}
+
+	// 2. Delete from DynamoDB together with the user's email sentinel
+	user := &models.UserEntity{}
+	if err := s.repo.Get(ctx, pk, sk, user); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			return errors.New("user not found")
+			return fmt.Errorf("user not found: %w", repository.ErrNotFound)
 		}
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
+	deletes := []map[string]string{{"PK": pk, "SK": sk}}
+	emailKey, ok, err := s.userEmailKey(ctx, user.Email, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	if ok {
+		deletes = append(deletes, emailKey)
+	}
+	if err := s.repo.Transaction(ctx, nil, deletes); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
 
-	// 2. Delete from cache
+	// 3. Delete from cache
 	cacheKey := fmt.Sprintf("user:%s", userID)
-	if err := s.cache.Del(ctx, cacheKey).Err(); err != nil {
-		log.Printf("Warning: failed to delete from cache: %v", err)
+	if err := s.cache.Del(ctx, cacheKey); err != nil {
+		requestid.Logf(ctx, "Warning: failed to delete from cache: %v", err)
 	}
 
-	// 3. Invalidate the user list cache
+	// 4. Invalidate the user list cache
 	if err := s.invalidateUserListCache(ctx); err != nil {
-		log.Printf("Warning: failed to invalidate user list cache: %v", err)
+		requestid.Logf(ctx, "Warning: failed to invalidate user list cache: %v", err)
+	}
+
+	requestid.Logf(ctx, "Deleted user: %s", userID)
+	return nil
+}
+
+// DeleteUserCascade purges a user and every item under their partition (contacts,
+// favorites index, and anything added later), e.g. for a GDPR account deletion
+// Flow: Page through USER#<id> → Batch delete all keys + email sentinel → Invalidate every related cache
+// Returns the number of items deleted.
+func (s *AppServiceWithCache) DeleteUserCascade(ctx context.Context, userID string) (int, error) {
+	ctx, span := tracing.Start(ctx, "AppService.DeleteUserCascade")
+	defer span.End()
+
+	pk := fmt.Sprintf("USER#%s", userID)
+
+	// 1. Collect every key under the user's partition
+	pager, err := s.repo.QueryPages(ctx, pk, "")
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge user: %w", err)
+	}
+
+	var keys []map[string]string
+	var contactIDs []string
+	for pager.HasMorePages() {
+		var page []struct {
+			PK string `dynamodbav:"PK"`
+			SK string `dynamodbav:"SK"`
+		}
+		if err := pager.NextPage(ctx, &page); err != nil {
+			return 0, fmt.Errorf("failed to purge user: %w", err)
+		}
+		for _, item := range page {
+			keys = append(keys, map[string]string{"PK": item.PK, "SK": item.SK})
+			if id, ok := strings.CutPrefix(item.SK, "CONTACT#"); ok {
+				contactIDs = append(contactIDs, id)
+			}
+		}
+	}
+
+	if len(keys) == 0 {
+		return 0, fmt.Errorf("user not found: %w", repository.ErrNotFound)
+	}
+
+	// 2. The email sentinel lives outside the partition, so add it by the user's email
+	user := &models.UserEntity{}
+	if err := s.repo.Get(ctx, pk, "METADATA", user); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return 0, fmt.Errorf("failed to purge user: %w", err)
+	}
+	if user.Email != "" {
+		emailKey, ok, err := s.userEmailKey(ctx, user.Email, userID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to purge user: %w", err)
+		}
+		if ok {
+			keys = append(keys, emailKey)
+		}
+	}
+
+	// 3. Delete everything in batches of 25
+	if err := s.repo.BatchWrite(ctx, nil, keys); err != nil {
+		return 0, fmt.Errorf("failed to purge user: %w", err)
+	}
+
+	// 4. Invalidate the user, their contacts, and every list they appeared in
+	cacheKeys := []string{
+		fmt.Sprintf("user:%s", userID),
+		"users:list",
+		fmt.Sprintf("dashboard:%s", userID),
+	}
+	for _, contactID := range contactIDs {
+		cacheKeys = append(cacheKeys, fmt.Sprintf("contact:%s:%s", userID, contactID))
+	}
+	if err := s.cache.Del(ctx, cacheKeys...); err != nil {
+		requestid.Logf(ctx, "Warning: failed to invalidate caches for purged user %s: %v", userID, err)
+	}
+	if err := s.invalidateUserContactCaches(ctx, userID); err != nil {
+		requestid.Logf(ctx, "Warning: failed to invalidate contact lists for purged user %s: %v", userID, err)
+	}
+	if _, err := s.cache.DelPattern(ctx, "contacts:list:*"); err != nil {
+		requestid.Logf(ctx, "Warning: failed to invalidate contact list pages for purged user %s: %v", userID, err)
+	}
+
+	// 5. Notify webhooks for each removed contact
+	for _, contactID := range contactIDs {
+		s.webhooks.Dispatch(webhook.NewEvent(webhook.ContactDeleted, userID, contactID, nil))
+	}
+
+	requestid.Logf(ctx, "Purged user: %s (%d items, %d contacts)", userID, len(keys), len(contactIDs))
+	return len(keys), nil
+}
+
+// TouchUser records that a user was active by setting LastActiveAt
+// Flow: Targeted update in DB only - no cache invalidation
+// Activity is not worth evicting the user list for; cached copies catch up on TTL.
+// Callers are expected to throttle (see handlers.ActivityTracker).
+func (s *AppServiceWithCache) TouchUser(ctx context.Context, userID string) error {
+	ctx, span := tracing.Start(ctx, "AppService.TouchUser")
+	defer span.End()
+
+	pk := fmt.Sprintf("USER#%s", userID)
+	sk := "METADATA"
+
+	if err := s.repo.Touch(ctx, pk, sk, "LastActiveAt"); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errors.New("user not found")
+		}
+		return fmt.Errorf("failed to touch user: %w", err)
 	}
 
-	log.Printf("Deleted user: %s", userID)
 	return nil
 }
 
 // ListAllUsers returns all users with list caching
 // Flow: Check list cache → If miss, query DB → Cache list → Return
 func (s *AppServiceWithCache) ListAllUsers(ctx context.Context) ([]*models.UserEntity, error) {
+	ctx, span := tracing.Start(ctx, "AppService.ListAllUsers")
+	defer span.End()
+
 	cacheKey := "users:list"
 
 	// 1. Try to get from cache
-	cached, err := s.cache.Get(ctx, cacheKey).Result()
+	cached, err := s.cache.Get(ctx, cacheKey)
 	if err == nil {
 		// Cache HIT!
-		log.Printf("Cache HIT for user list")
+		requestid.Logf(ctx, "Cache HIT for user list")
 		var users []*models.UserEntity
-		if err := json.Unmarshal([]byte(cached), &users); err == nil {
+		if err := decodeCached(cached, &users); err == nil {
 			return users, nil
 		}
 	}
 
 	// 2. Cache MISS - query DynamoDB
-	log.Printf("Cache MISS for user list")
+	requestid.Logf(ctx, "Cache MISS for user list")
+	if IsCacheOnly(ctx) {
+		return nil, ErrNotCached
+	}
 	var users []*models.UserEntity
 	if err := s.repo.QueryByEntityType(ctx, "USER", &users); err != nil {
 		return nil, fmt.Errorf("failed to list users: %w", err)
 	}
 
-	// 3. Cache the list
-	if data, err := json.Marshal(users); err == nil {
-		if err := s.cache.Set(ctx, cacheKey, data, s.ttl).Err(); err != nil {
-			log.Printf("Warning: failed to cache user list: %v", err)
-		}
+	// 3. Cache the list (unless it's too big to be worth it)
+	s.cacheList(ctx, cacheKey, len(users), users)
+
+	return users, nil
+}
+
+// ListUsersByCreatedDate returns users created between from and to (inclusive), oldest
+// first. Ranges are too varied to cache, so this always reads GSI1.
+// Flow: Get GSI1SK bounds → Query GSI1 BETWEEN → Return
+func (s *AppServiceWithCache) ListUsersByCreatedDate(ctx context.Context, from, to time.Time) ([]*models.UserEntity, error) {
+	ctx, span := tracing.Start(ctx, "AppService.ListUsersByCreatedDate")
+	defer span.End()
+
+	if IsCacheOnly(ctx) {
+		return nil, ErrNotCached
+	}
+
+	lower, upper := models.UserCreatedRange(from, to)
+	var users []*models.UserEntity
+	if err := s.repo.QueryByEntityTypeBetween(ctx, "USER", lower, upper, &users); err != nil {
+		return nil, fmt.Errorf("failed to list users by created date: %w", err)
 	}
 
 	return users, nil
@@ -200,49 +555,224 @@ func (s *AppServiceWithCache) ListAllUsers(ctx context.Context) ([]*models.UserE
 // CONTACT OPERATIONS WITH CACHING
 // ============================================================================
 
+// CreateContactInput is everything CreateContact and CreateContactWithID need to
+// create one contact
+type CreateContactInput struct {
+	UserID string
+	ContactInput
+	RejectDuplicates bool // Fail with ErrContactExists if the user has a contact with this email
+}
+
 // CreateContact creates a new contact for a user
-// Flow: Save to DB → Cache individual → Invalidate user's contact list cache
-func (s *AppServiceWithCache) CreateContact(ctx context.Context, userID, name, email, phone, company string, isFavorite bool) (*models.ContactEntity, error) {
-	contactID := uuid.New().String()
-	contact := models.NewContact(contactID, userID, name, email, phone, company, isFavorite)
+// Flow: Check email domain → (Optional) duplicate check → Transaction (put contact + favorites index, bump user's ContactCount) → Cache individual → Invalidate user's contact list cache
+// Duplicates are allowed unless RejectDuplicates is set, in which case an existing
+// contact with the same email for this user yields ErrContactExists.
+func (s *AppServiceWithCache) CreateContact(ctx context.Context, in CreateContactInput) (*models.ContactEntity, error) {
+	ctx, span := tracing.Start(ctx, "AppService.CreateContact")
+	defer span.End()
+
+	if err := s.checkContactEmailDomain(ctx, in.UserID, in.Email); err != nil {
+		return nil, err
+	}
+
+	if in.RejectDuplicates && in.Email != "" {
+		exists, err := s.contactEmailExists(ctx, in.UserID, in.Email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check for duplicate contact: %w", err)
+		}
+		if exists {
+			return nil, ErrContactExists
+		}
+	}
 
-	// 1. Save to DynamoDB
-	if err := s.repo.Put(ctx, contact); err != nil {
+	contactID := uuid.New().String()
+	contact := in.newContact(contactID, in.UserID)
+
+	// 1. Save to DynamoDB together with the favorites index item (if any) and the
+	//    owner's ContactCount, so the count can't drift; a missing user cancels it all.
+	//    At most three writes, so this ignores SetMaxTransactionItems and never splits.
+	contact.SetTimestamps()
+	uow := repository.NewUnitOfWork(s.repo).SetStrict(true)
+	uow.Put(contact)
+	if in.IsFavorite {
+		uow.Put(models.NewFavoriteContactIndex(contact))
+	}
+	uow.Update(contactCountUpdate(in.UserID, 1))
+	if err := uow.Commit(ctx); err != nil {
+		if errors.Is(err, repository.ErrConditionFailed) {
+			return nil, fmt.Errorf("user not found: %w", repository.ErrNotFound)
+		}
 		return nil, fmt.Errorf("failed to create contact: %w", err)
 	}
+	s.dropCachedUser(ctx, in.UserID)
 
 	// 2. Cache the individual contact
 	if err := s.cacheContact(ctx, contact); err != nil {
-		log.Printf("Warning: failed to cache contact: %v", err)
+		requestid.Logf(ctx, "Warning: failed to cache contact: %v", err)
 	}
 
-	// 3. Invalidate user's contact list caches
-	if err := s.invalidateUserContactCaches(ctx, userID); err != nil {
-		log.Printf("Warning: failed to invalidate contact caches: %v", err)
+	// 3. Invalidate (cache-aside) or patch (write-through) user's contact list caches
+	if err := s.refreshUserContactCaches(ctx, contact); err != nil {
+		requestid.Logf(ctx, "Warning: failed to refresh contact caches: %v", err)
 	}
 
-	log.Printf("Created contact: %s for user: %s", contactID, userID)
+	// 4. Notify webhooks and queue enrichment
+	s.webhooks.Dispatch(webhook.NewEvent(webhook.ContactCreated, in.UserID, contactID, contact))
+	s.queueEnrichment(ctx, contact)
+
+	requestid.Logf(ctx, "Created contact: %s for user: %s", contactID, in.UserID)
 	return contact, nil
 }
 
+// CreateContactWithID creates a contact under a client-supplied ID (e.g. derived from an
+// external CRM record) so retries are idempotent: when the user already has a contact with
+// that ID it is returned as stored, with created=false, instead of failing or duplicating.
+// The caller validates the ID format. A missing user yields ErrNotFound and writes nothing.
+// Flow: Check email domain → Check ID → Dedupe (optional) → Transaction (create contact + favorites index, bump user's ContactCount) → Cache → Webhook
+func (s *AppServiceWithCache) CreateContactWithID(ctx context.Context, contactID string, in CreateContactInput) (*models.ContactEntity, bool, error) {
+	ctx, span := tracing.Start(ctx, "AppService.CreateContactWithID")
+	defer span.End()
+
+	if err := s.checkContactEmailDomain(ctx, in.UserID, in.Email); err != nil {
+		return nil, false, err
+	}
+	contact := in.newContact(contactID, in.UserID)
+
+	// 1. A retry must see its own earlier write, not trip the duplicate-email check
+	if in.RejectDuplicates && in.Email != "" {
+		existing := &models.ContactEntity{}
+		err := s.repo.Get(ctx, contact.GetPK(), contact.GetSK(), existing)
+		if err == nil {
+			return s.existingContact(ctx, existing)
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return nil, false, fmt.Errorf("failed to check contact ID: %w", err)
+		}
+
+		exists, err := s.contactEmailExists(ctx, in.UserID, in.Email)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to check for duplicate contact: %w", err)
+		}
+		if exists {
+			return nil, false, ErrContactExists
+		}
+	}
+
+	// 2. Create keyed on the ID in one transaction with the favorites index item and the
+	//    owner's ContactCount, as CreateContact does. A cancellation is either the ID being
+	//    taken (a concurrent retry that won the race, whose contact is returned) or the
+	//    user missing; a Get of the contact tells them apart.
+	contact.SetTimestamps()
+	tx := repository.TxItems{
+		Creates: []repository.BaseModel{contact},
+		Updates: []repository.TxUpdate{contactCountUpdate(in.UserID, 1)},
+	}
+	if in.IsFavorite {
+		tx.Puts = append(tx.Puts, models.NewFavoriteContactIndex(contact))
+	}
+	if err := s.repo.TransactWrite(ctx, tx); err != nil {
+		if !errors.Is(err, repository.ErrConditionFailed) {
+			return nil, false, fmt.Errorf("failed to create contact: %w", err)
+		}
+		existing := &models.ContactEntity{}
+		err := s.repo.Get(ctx, contact.GetPK(), contact.GetSK(), existing)
+		if err == nil {
+			return s.existingContact(ctx, existing)
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, false, fmt.Errorf("user not found: %w", repository.ErrNotFound)
+		}
+		return nil, false, fmt.Errorf("failed to check contact ID: %w", err)
+	}
+	s.dropCachedUser(ctx, in.UserID)
+
+	// 3. Cache the contact and invalidate/patch the user's list caches
+	if err := s.cacheContact(ctx, contact); err != nil {
+		requestid.Logf(ctx, "Warning: failed to cache contact: %v", err)
+	}
+	if err := s.refreshUserContactCaches(ctx, contact); err != nil {
+		requestid.Logf(ctx, "Warning: failed to refresh contact caches: %v", err)
+	}
+
+	// 4. Notify webhooks and queue enrichment (only for the write that actually created it)
+	s.webhooks.Dispatch(webhook.NewEvent(webhook.ContactCreated, in.UserID, contactID, contact))
+	s.queueEnrichment(ctx, contact)
+
+	requestid.Logf(ctx, "Created contact: %s for user: %s (client-supplied ID)", contactID, in.UserID)
+	return contact, true, nil
+}
+
+// existingContact answers a repeated CreateContactWithID. Contacts created before the
+// favorites index existed may lack their FAV# item, so the index is rewritten for favorites.
+func (s *AppServiceWithCache) existingContact(ctx context.Context, contact *models.ContactEntity) (*models.ContactEntity, bool, error) {
+	if contact.IsFavorite {
+		if err := s.syncFavoriteIndex(ctx, contact); err != nil {
+			return nil, false, fmt.Errorf("failed to index favorite contact: %w", err)
+		}
+	}
+	requestid.Logf(ctx, "Contact %s already exists for user %s, returning it", contact.ID, contact.UserID)
+	return contact, false, nil
+}
+
+// contactEmailExists reports whether the user already has a contact with this email,
+// ignoring case and surrounding space the way GetContactByEmail does.
+// Always reads DynamoDB so a stale list cache can't let a duplicate through. Every
+// contact is read and compared here: a filter expression can only match exact case,
+// and nothing at all once Email is in ENCRYPTED_FIELDS.
+func (s *AppServiceWithCache) contactEmailExists(ctx context.Context, userID, email string) (bool, error) {
+	contacts, err := s.queryAllContacts(ctx, fmt.Sprintf("USER#%s", userID), "CONTACT#", nil)
+	if err != nil {
+		return false, err
+	}
+	normalized := strings.ToLower(strings.TrimSpace(email))
+	for _, contact := range contacts {
+		if strings.ToLower(strings.TrimSpace(contact.Email)) == normalized {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// queryAllContacts reads every page of the contacts under pk with the SK prefix (CONTACT#
+// or TRASH#), keeping those filter passes (nil keeps all). Unreadable rows are logged and
+// skipped rather than failing the read.
+func (s *AppServiceWithCache) queryAllContacts(ctx context.Context, pk, skPrefix string, filter *expression.ConditionBuilder) ([]*models.ContactEntity, error) {
+	items, _, err := s.repo.QueryWithStats(ctx, pk, skPrefix, filter)
+	if err != nil {
+		return nil, err
+	}
+	contacts, err := repository.UnmarshalItems[*models.ContactEntity](items)
+	if err != nil {
+		requestid.Logf(ctx, "Warning: skipped unreadable contacts under %s/%s: %v", pk, skPrefix, err)
+	}
+	return contacts, nil
+}
+
 // GetContact retrieves a specific contact with caching
 // Flow: Check cache → If miss, get from DB → Cache it → Return
 func (s *AppServiceWithCache) GetContact(ctx context.Context, userID, contactID string) (*models.ContactEntity, error) {
+	ctx, span := tracing.Start(ctx, "AppService.GetContact")
+	defer span.End()
+
 	cacheKey := fmt.Sprintf("contact:%s:%s", userID, contactID)
 
 	// 1. Try to get from cache
-	cached, err := s.cache.Get(ctx, cacheKey).Result()
+	cached, err := s.cache.Get(ctx, cacheKey)
 	if err == nil {
 		// Cache HIT!
-		log.Printf("Cache HIT for contact: %s", contactID)
+		requestid.Logf(ctx, "Cache HIT for contact: %s", contactID)
 		var contact models.ContactEntity
-		if err := json.Unmarshal([]byte(cached), &contact); err == nil {
+		if err := decodeCached(cached, &contact); err == nil {
+			s.slideExpiry(ctx, cacheKey)
 			return &contact, nil
 		}
 	}
 
 	// 2. Cache MISS - get from DynamoDB
-	log.Printf("Cache MISS for contact: %s", contactID)
+	requestid.Logf(ctx, "Cache MISS for contact: %s", contactID)
+	if IsCacheOnly(ctx) {
+		return nil, ErrNotCached
+	}
 	contact := &models.ContactEntity{}
 	pk := fmt.Sprintf("USER#%s", userID)
 	sk := fmt.Sprintf("CONTACT#%s", contactID)
@@ -256,177 +786,1142 @@ func (s *AppServiceWithCache) GetContact(ctx context.Context, userID, contactID
 
 	// 3. Cache the result
 	if err := s.cacheContact(ctx, contact); err != nil {
-		log.Printf("Warning: failed to cache contact: %v", err)
+		requestid.Logf(ctx, "Warning: failed to cache contact: %v", err)
 	}
 
 	return contact, nil
 }
 
+// MaxContactsByIDs caps how many IDs one GetContactsByIDs call may ask for
+const MaxContactsByIDs = 100
+
+// ErrTooManyIDs is returned by GetContactsByIDs for more than MaxContactsByIDs IDs
+var ErrTooManyIDs = fmt.Errorf("at most %d ids per request", MaxContactsByIDs)
+
+// GetContactsByIDs returns the user's contacts with the given IDs in the same order,
+// with a nil entry for each ID the user has no contact for
+// Flow: Dedupe IDs → GetMany contact:<userID>:<id> (one round trip) → BatchGet the misses → Cache them → Reorder to match ids
+// Cache hits don't slide their expiry, which would cost a round trip per contact.
+func (s *AppServiceWithCache) GetContactsByIDs(ctx context.Context, userID string, ids []string) ([]*models.ContactEntity, error) {
+	ctx, span := tracing.Start(ctx, "AppService.GetContactsByIDs")
+	defer span.End()
+
+	if len(ids) > MaxContactsByIDs {
+		return nil, ErrTooManyIDs
+	}
+
+	// 1. Read every distinct ID from the cache at once
+	cacheKeys := make([]string, 0, len(ids))
+	idByKey := make(map[string]string, len(ids))
+	for _, id := range ids {
+		key := fmt.Sprintf("contact:%s:%s", userID, id)
+		if _, ok := idByKey[key]; !ok {
+			idByKey[key] = id
+			cacheKeys = append(cacheKeys, key)
+		}
+	}
+	cached, misses, err := s.cache.GetMany(ctx, cacheKeys)
+	if err != nil {
+		requestid.Logf(ctx, "Warning: failed to read contacts from cache: %v", err)
+		cached, misses = nil, cacheKeys
+	}
+	byID := make(map[string]*models.ContactEntity, len(cacheKeys))
+	for key, data := range cached {
+		contact := &models.ContactEntity{}
+		if err := decodeCached(data, contact); err != nil {
+			misses = append(misses, key)
+			continue
+		}
+		byID[idByKey[key]] = contact
+	}
+	requestid.Logf(ctx, "Cache HIT for %d of %d contacts", len(cacheKeys)-len(misses), len(cacheKeys))
+
+	// 2. Only the misses go to DynamoDB, in one BatchGet (which rejects duplicate keys)
+	if len(misses) > 0 {
+		if IsCacheOnly(ctx) {
+			return nil, ErrNotCached
+		}
+		pk := fmt.Sprintf("USER#%s", userID)
+		keys := make([]map[string]string, 0, len(misses))
+		for _, key := range misses {
+			keys = append(keys, map[string]string{"PK": pk, "SK": fmt.Sprintf("CONTACT#%s", idByKey[key])})
+		}
+
+		var found []*models.ContactEntity
+		if err := s.repo.BatchGet(ctx, keys, &found); err != nil {
+			return nil, fmt.Errorf("failed to get contacts: %w", err)
+		}
+		for _, contact := range found {
+			byID[contact.ID] = contact
+			if err := s.cacheContact(ctx, contact); err != nil {
+				requestid.Logf(ctx, "Warning: failed to cache contact: %v", err)
+			}
+		}
+	}
+
+	// 3. Results come back in any order
+	contacts := make([]*models.ContactEntity, len(ids))
+	for i, id := range ids {
+		contacts[i] = byID[id]
+	}
+
+	return contacts, nil
+}
+
+// GetContactByEmail returns the user's first contact whose email matches, ignoring case
+// Flow: List user's contacts (cached) → Match normalized email → Return
+// Matching happens in memory because stored emails aren't normalized; if this gets
+// hot for large contact lists, a per-user email GSI would turn it into a key lookup.
+func (s *AppServiceWithCache) GetContactByEmail(ctx context.Context, userID, email string) (*models.ContactEntity, error) {
+	ctx, span := tracing.Start(ctx, "AppService.GetContactByEmail")
+	defer span.End()
+
+	normalized := strings.ToLower(strings.TrimSpace(email))
+
+	contacts, err := s.ListUserContacts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, contact := range contacts {
+		if strings.ToLower(strings.TrimSpace(contact.Email)) == normalized {
+			return contact, nil
+		}
+	}
+
+	return nil, repository.ErrNotFound
+}
+
 // ListUserContacts returns all contacts for a user with caching
 // Flow: Check cache → If miss, query DB → Cache list → Return
 func (s *AppServiceWithCache) ListUserContacts(ctx context.Context, userID string) ([]*models.ContactEntity, error) {
-	cacheKey := fmt.Sprintf("contacts:user:%s", userID)
+	ctx, span := tracing.Start(ctx, "AppService.ListUserContacts")
+	defer span.End()
+
+	cacheKey := s.contactListKey(userID)
 
 	// 1. Try to get from cache
-	cached, err := s.cache.Get(ctx, cacheKey).Result()
+	cached, stale, err := s.cacheGet(ctx, cacheKey)
 	if err == nil {
 		// Cache HIT!
-		log.Printf("Cache HIT for user %s contacts", userID)
+		requestid.Logf(ctx, "Cache HIT for user %s contacts", userID)
 		var contacts []*models.ContactEntity
-		if err := json.Unmarshal([]byte(cached), &contacts); err == nil {
+		if err := decodeCached(cached, &contacts); err == nil {
+			if stale {
+				s.revalidate(ctx, cacheKey, func(ctx context.Context) error {
+					_, err := s.loadUserContacts(ctx, userID)
+					return err
+				})
+			}
 			return contacts, nil
 		}
 	}
 
-	// 2. Cache MISS - query DynamoDB
-	log.Printf("Cache MISS for user %s contacts", userID)
-	var contacts []*models.ContactEntity
+	// 2. Cache MISS - query DynamoDB and cache the list
+	requestid.Logf(ctx, "Cache MISS for user %s contacts", userID)
+	if IsCacheOnly(ctx) {
+		return nil, ErrNotCached
+	}
+	return s.loadUserContacts(ctx, userID)
+}
+
+// loadUserContacts reads a user's contacts from DynamoDB and caches the list
+// (unless it's too big to be worth it)
+func (s *AppServiceWithCache) loadUserContacts(ctx context.Context, userID string) ([]*models.ContactEntity, error) {
 	pk := fmt.Sprintf("USER#%s", userID)
+	if s.contactListIndex != "" {
+		contacts, err := s.queryContactListIndex(ctx, pk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list contacts: %w", err)
+		}
+		s.cacheList(ctx, userContactListKey(contactViewProjected, userID), len(contacts), contacts)
+		return contacts, nil
+	}
 
-	if err := s.repo.Query(ctx, pk, "CONTACT#", &contacts); err != nil {
+	items, err := s.repo.QueryItems(ctx, pk, "CONTACT#")
+	if err != nil {
 		return nil, fmt.Errorf("failed to list contacts: %w", err)
 	}
 
-	// 3. Cache the list
-	if data, err := json.Marshal(contacts); err == nil {
-		if err := s.cache.Set(ctx, cacheKey, data, s.ttl).Err(); err != nil {
-			log.Printf("Warning: failed to cache contact list: %v", err)
-		}
+	// A corrupt row is logged and skipped rather than failing the whole list
+	contacts, err := repository.UnmarshalItems[*models.ContactEntity](items)
+	if err != nil {
+		requestid.Logf(ctx, "Warning: skipped unreadable contacts for user %s: %v", userID, err)
 	}
 
+	s.cacheList(ctx, userContactListKey(contactViewAll, userID), len(contacts), contacts)
 	return contacts, nil
 }
 
 // ListFavoriteContacts returns only favorite contacts for a user with caching
-// Flow: Check cache → If miss, query DB with filter → Cache list → Return
+// Flow: Check cache → If miss, query FAV# index items (or filter CONTACT# items when favorites_index is off) → Cache list → Return
 func (s *AppServiceWithCache) ListFavoriteContacts(ctx context.Context, userID string) ([]*models.ContactEntity, error) {
-	cacheKey := fmt.Sprintf("contacts:favorites:%s", userID)
+	ctx, span := tracing.Start(ctx, "AppService.ListFavoriteContacts")
+	defer span.End()
+
+	cacheKey := userContactListKey(contactViewFavorites, userID)
 
 	// 1. Try to get from cache
-	cached, err := s.cache.Get(ctx, cacheKey).Result()
+	cached, err := s.cache.Get(ctx, cacheKey)
 	if err == nil {
 		// Cache HIT!
-		log.Printf("Cache HIT for user %s favorites", userID)
+		requestid.Logf(ctx, "Cache HIT for user %s favorites", userID)
 		var contacts []*models.ContactEntity
-		if err := json.Unmarshal([]byte(cached), &contacts); err == nil {
+		if err := decodeCached(cached, &contacts); err == nil {
 			return contacts, nil
 		}
 	}
 
-	// 2. Cache MISS - query DynamoDB with filter
-	log.Printf("Cache MISS for user %s favorites", userID)
-	var contacts []*models.ContactEntity
+	// 2. Cache MISS - query the favorites index (reads only favorite items)
+	requestid.Logf(ctx, "Cache MISS for user %s favorites", userID)
+	if IsCacheOnly(ctx) {
+		return nil, ErrNotCached
+	}
 	pk := fmt.Sprintf("USER#%s", userID)
-	filter := expression.Name("IsFavorite").Equal(expression.Value(true))
+	var contacts []*models.ContactEntity
+	if s.flagEnabled(FlagFavoritesIndex) {
+		items, err := s.repo.QueryItems(ctx, pk, "FAV#")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list favorite contacts: %w", err)
+		}
+		contacts, err = repository.UnmarshalItems[*models.ContactEntity](items)
+		if err != nil {
+			requestid.Logf(ctx, "Warning: skipped unreadable favorites for user %s: %v", userID, err)
+		}
+	} else {
+		// Index switched off: filter the contacts themselves (writes still maintain FAV#).
+		// Every contact is read to find the favorites; QueryWithStats puts that cost on the span.
+		filter := expression.Name("IsFavorite").Equal(expression.Value(true))
+		items, _, err := s.repo.QueryWithStats(ctx, pk, "CONTACT#", &filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list favorite contacts: %w", err)
+		}
+		contacts, err = repository.UnmarshalItems[*models.ContactEntity](items)
+		if err != nil {
+			requestid.Logf(ctx, "Warning: skipped unreadable favorites for user %s: %v", userID, err)
+		}
+	}
+
+	// 3. Cache the list (unless it's too big to be worth it)
+	s.cacheList(ctx, cacheKey, len(contacts), contacts)
 
-	if err := s.repo.QueryWithFilter(ctx, pk, "CONTACT#", filter, &contacts); err != nil {
-		return nil, fmt.Errorf("failed to list favorite contacts: %w", err)
+	return contacts, nil
+}
+
+// ListUserContactsPage returns one page of a user's contacts (only favorites if favorites
+// is set) and the cursor for the next page, "" on the last one. Pages are read straight
+// from DynamoDB with Limit, so a user with thousands of contacts is never loaded whole.
+// Pages aren't cached; the cached lists stay the full-list views. A projection limits
+// the attributes read (see GenericRepository.QueryPage); other fields come back zero.
+// Flow: Decode cursor → Query one page → Encode LastEvaluatedKey
+func (s *AppServiceWithCache) ListUserContactsPage(ctx context.Context, userID string, favorites bool, limit int, cursor string, projection ...string) ([]*models.ContactEntity, string, error) {
+	ctx, span := tracing.Start(ctx, "AppService.ListUserContactsPage")
+	defer span.End()
+
+	// Pages are never cached, so cache-only mode has nothing to serve
+	if IsCacheOnly(ctx) {
+		return nil, "", ErrNotCached
 	}
 
-	// 3. Cache the list
-	if data, err := json.Marshal(contacts); err == nil {
-		if err := s.cache.Set(ctx, cacheKey, data, s.ttl).Err(); err != nil {
-			log.Printf("Warning: failed to cache favorites: %v", err)
-		}
+	pk := fmt.Sprintf("USER#%s", userID)
+	skPrefix := "CONTACT#"
+	if favorites {
+		skPrefix = "FAV#"
 	}
 
-	return contacts, nil
+	// 1. A cursor only resumes the listing it came from (same user and view)
+	startKey, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if startKey != nil && !cursorWithin(startKey, pk, skPrefix) {
+		return nil, "", pagination.ErrInvalidCursor
+	}
+
+	// 2. Read one page
+	var contacts []*models.ContactEntity
+	lastKey, err := s.repo.QueryPage(ctx, pk, skPrefix, pagination.ClampLimit(limit), startKey, &contacts, projection...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list contacts page: %w", err)
+	}
+
+	return contacts, pagination.EncodeCursor(lastKey), nil
+}
+
+// cursorWithin reports whether a decoded cursor points into the pk/skPrefix range
+func cursorWithin(key map[string]types.AttributeValue, pk, skPrefix string) bool {
+	keyPK, ok := key["PK"].(*types.AttributeValueMemberS)
+	if !ok || keyPK.Value != pk {
+		return false
+	}
+	keySK, ok := key["SK"].(*types.AttributeValueMemberS)
+	return ok && strings.HasPrefix(keySK.Value, skPrefix)
+}
+
+// CountFavoriteContacts returns how many favorites a user has without loading them.
+// Uses the sparse FAV# index rather than CountWithFilter on IsFavorite: a filtered count
+// still reads (and bills) every contact, the index only reads the favorites.
+// Flow: COUNT query over the FAV# index items (no cache - the count is cheap)
+func (s *AppServiceWithCache) CountFavoriteContacts(ctx context.Context, userID string) (int, error) {
+	ctx, span := tracing.Start(ctx, "AppService.CountFavoriteContacts")
+	defer span.End()
+
+	if IsCacheOnly(ctx) {
+		return 0, ErrNotCached
+	}
+
+	pk := fmt.Sprintf("USER#%s", userID)
+
+	count, err := s.repo.Count(ctx, pk, "FAV#")
+	if err != nil {
+		return 0, fmt.Errorf("failed to count favorite contacts: %w", err)
+	}
+
+	return count, nil
 }
 
 // UpdateContact updates contact information
-// Flow: Update in DB → Update cache → Invalidate list caches
+// Flow: Update in DB → Sync favorites index → Update cache → Invalidate list caches
 func (s *AppServiceWithCache) UpdateContact(ctx context.Context, userID, contactID string, updates map[string]interface{}) (*models.ContactEntity, error) {
+	return s.updateContact(ctx, userID, contactID, updates, time.Time{})
+}
+
+// updateContact is UpdateContact, conditional on the contact being unmodified since since unless it is zero
+func (s *AppServiceWithCache) updateContact(ctx context.Context, userID, contactID string, updates map[string]interface{}, since time.Time) (*models.ContactEntity, error) {
+	ctx, span := tracing.Start(ctx, "AppService.UpdateContact")
+	defer span.End()
+
+	if err := validation.UpdateFields(updates); err != nil {
+		return nil, err
+	}
+	if err := s.checkUpdatedEmailDomain(ctx, userID, updates); err != nil {
+		return nil, err
+	}
+
 	pk := fmt.Sprintf("USER#%s", userID)
 	sk := fmt.Sprintf("CONTACT#%s", contactID)
 
 	// 1. Update in DynamoDB
-	if err := s.repo.Update(ctx, pk, sk, updates); err != nil {
+	if err := s.applyUpdate(ctx, pk, sk, updates, since); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			return nil, errors.New("contact not found")
+			return nil, fmt.Errorf("contact not found: %w", err)
+		}
+		if errors.Is(err, ErrPreconditionFailed) {
+			return nil, err
 		}
 		return nil, fmt.Errorf("failed to update contact: %w", err)
 	}
 
-	// 2. Get the updated contact
-	contact, err := s.GetContact(ctx, userID, contactID)
-	if err != nil {
-		return nil, err
+	// 2. Get the updated contact (straight from DB - the cached copy is now stale)
+	contact := &models.ContactEntity{}
+	if err := s.repo.Get(ctx, pk, sk, contact); err != nil {
+		return nil, fmt.Errorf("failed to get updated contact: %w", err)
 	}
 
-	// 3. Update cache (GetContact already cached it)
+	// 3. Keep the favorites index in sync with the new state. The update has landed, so a
+	//    failure here is only logged; the next update rewrites the copy and Reindex
+	//    recreates a missing one.
+	if err := s.syncFavoriteIndex(ctx, contact); err != nil {
+		requestid.Logf(ctx, "Warning: failed to sync favorites index for contact %s: %v", contactID, err)
+	}
+
+	// 4. Update cache
 	if err := s.cacheContact(ctx, contact); err != nil {
-		log.Printf("Warning: failed to update cache: %v", err)
+		requestid.Logf(ctx, "Warning: failed to update cache: %v", err)
 	}
 
-	// 4. Invalidate list caches
-	if err := s.invalidateUserContactCaches(ctx, userID); err != nil {
-		log.Printf("Warning: failed to invalidate contact caches: %v", err)
+	// 5. Invalidate (cache-aside) or patch (write-through) list caches
+	if err := s.refreshUserContactCaches(ctx, contact); err != nil {
+		requestid.Logf(ctx, "Warning: failed to refresh contact caches: %v", err)
 	}
 
-	log.Printf("Updated contact: %s for user: %s", contactID, userID)
+	// 6. Notify webhooks
+	s.webhooks.Dispatch(webhook.NewEvent(webhook.ContactUpdated, userID, contactID, contact))
+
+	requestid.Logf(ctx, "Updated contact: %s for user: %s", contactID, userID)
 	return contact, nil
 }
 
-// DeleteContact deletes a contact
-// Flow: Delete from DB → Delete from cache → Invalidate list caches
+// BulkUpdateResult reports whether one contact in a bulk update was applied
+type BulkUpdateResult struct {
+	ID      string `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkUpdateContacts applies the same updates to many of a user's contacts
+// Flow: Chunk into transactions (see SetMaxTransactionItems) → Update each chunk atomically → Sync favorites index → Invalidate caches once
+// Each chunk is all-or-nothing; a failed chunk marks all of its IDs unsuccessful
+// while other chunks still apply, so the per-ID results show exactly what changed.
+// With atomic set the batch must fit in one transaction, so it applies entirely or not
+// at all; a larger one fails with *repository.TransactionTooLargeError before any write.
+func (s *AppServiceWithCache) BulkUpdateContacts(ctx context.Context, userID string, ids []string, updates map[string]interface{}, atomic bool) ([]BulkUpdateResult, error) {
+	ctx, span := tracing.Start(ctx, "AppService.BulkUpdateContacts")
+	defer span.End()
+
+	if len(ids) == 0 {
+		return []BulkUpdateResult{}, nil
+	}
+	if err := validation.UpdateFields(updates); err != nil {
+		return nil, err
+	}
+	if err := s.checkUpdatedEmailDomain(ctx, userID, updates); err != nil {
+		return nil, err
+	}
+	if atomic && len(ids) > s.maxTxItems {
+		return nil, &repository.TransactionTooLargeError{Items: len(ids), Limit: s.maxTxItems}
+	}
+
+	pk := fmt.Sprintf("USER#%s", userID)
+	results := make([]BulkUpdateResult, 0, len(ids))
+	var updated []map[string]string
+
+	// 1. Update in DynamoDB, one transaction per chunk
+	for start := 0; start < len(ids); start += s.maxTxItems {
+		end := min(start+s.maxTxItems, len(ids))
+
+		keys := make([]map[string]string, 0, end-start)
+		for _, id := range ids[start:end] {
+			keys = append(keys, map[string]string{"PK": pk, "SK": fmt.Sprintf("CONTACT#%s", id)})
+		}
+
+		// Each chunk gets its own copy since the repository stamps UpdatedAt into the map
+		chunkUpdates := make(map[string]interface{}, len(updates)+1)
+		for k, v := range updates {
+			chunkUpdates[k] = v
+		}
+
+		err := s.repo.TransactUpdate(ctx, keys, chunkUpdates)
+		if err != nil {
+			requestid.Logf(ctx, "Warning: bulk update chunk %d-%d failed for user %s: %v", start, end-1, userID, err)
+			if errors.Is(err, repository.ErrNotFound) {
+				err = errors.New("one or more contacts in this batch not found")
+			}
+		} else {
+			updated = append(updated, keys...)
+		}
+
+		for _, id := range ids[start:end] {
+			result := BulkUpdateResult{ID: id, Success: err == nil}
+			if err != nil {
+				result.Error = err.Error()
+			}
+			results = append(results, result)
+		}
+	}
+
+	if len(updated) == 0 {
+		return results, nil
+	}
+
+	// 2. Re-read updated contacts to refresh favorites index copies and drop stale caches
+	var contacts []*models.ContactEntity
+	if err := s.repo.BatchGet(ctx, updated, &contacts); err != nil {
+		requestid.Logf(ctx, "Warning: failed to reload bulk-updated contacts: %v", err)
+	}
+	for _, contact := range contacts {
+		if err := s.syncFavoriteIndex(ctx, contact); err != nil {
+			requestid.Logf(ctx, "Warning: failed to sync favorites index for contact %s: %v", contact.ID, err)
+		}
+		if err := s.cache.Del(ctx, fmt.Sprintf("contact:%s:%s", userID, contact.ID)); err != nil {
+			requestid.Logf(ctx, "Warning: failed to delete from cache: %v", err)
+		}
+		s.webhooks.Dispatch(webhook.NewEvent(webhook.ContactUpdated, userID, contact.ID, contact))
+	}
+
+	// 3. Invalidate list caches once for the whole batch
+	if err := s.invalidateUserContactCaches(ctx, userID); err != nil {
+		requestid.Logf(ctx, "Warning: failed to invalidate contact caches: %v", err)
+	}
+
+	requestid.Logf(ctx, "Bulk updated %d/%d contacts for user: %s", len(updated), len(ids), userID)
+	return results, nil
+}
+
+// DeleteContact soft-deletes a contact: it moves to the user's trash (see
+// ListDeletedContacts) and can be restored until the trash retention runs out
+// Flow: Load from DB → Transaction (put TRASH# copy, delete CONTACT# + FAV#, drop ContactCount) → Delete from cache → Invalidate list caches
 func (s *AppServiceWithCache) DeleteContact(ctx context.Context, userID, contactID string) error {
+	ctx, span := tracing.Start(ctx, "AppService.DeleteContact")
+	defer span.End()
+
 	pk := fmt.Sprintf("USER#%s", userID)
 	sk := fmt.Sprintf("CONTACT#%s", contactID)
 
-	// 1. Delete from DynamoDB
-	if err := s.repo.Delete(ctx, pk, sk); err != nil {
+	// 1. Load the contact from the DB so the trash copy is current
+	contact := &models.ContactEntity{}
+	if err := s.repo.Get(ctx, pk, sk, contact); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return errors.New("contact not found")
 		}
 		return fmt.Errorf("failed to delete contact: %w", err)
 	}
 
-	// 2. Delete from cache
+	// 2. Move it to the trash, dropping the favorites index item and the owner's
+	//    ContactCount in the same transaction
+	trash := models.NewDeletedContact(contact, clock.Now())
+	tx := repository.TxItems{
+		Puts: []repository.BaseModel{trash},
+		Deletes: []map[string]string{
+			{"PK": pk, "SK": sk},
+			{"PK": pk, "SK": fmt.Sprintf("FAV#%s", contactID)},
+		},
+		Updates: []repository.TxUpdate{contactCountUpdate(userID, -1)},
+	}
+	if err := s.repo.TransactWrite(ctx, tx); err != nil {
+		return fmt.Errorf("failed to delete contact: %w", err)
+	}
+	s.dropCachedUser(ctx, userID)
+
+	// 3. Delete from cache
 	cacheKey := fmt.Sprintf("contact:%s:%s", userID, contactID)
-	if err := s.cache.Del(ctx, cacheKey).Err(); err != nil {
-		log.Printf("Warning: failed to delete from cache: %v", err)
+	if err := s.cache.Del(ctx, cacheKey); err != nil {
+		requestid.Logf(ctx, "Warning: failed to delete from cache: %v", err)
 	}
 
-	// 3. Invalidate list caches
+	// 4. Invalidate list caches
 	if err := s.invalidateUserContactCaches(ctx, userID); err != nil {
-		log.Printf("Warning: failed to invalidate contact caches: %v", err)
+		requestid.Logf(ctx, "Warning: failed to invalidate contact caches: %v", err)
 	}
 
-	log.Printf("Deleted contact: %s for user: %s", contactID, userID)
+	// 5. Notify webhooks
+	s.webhooks.Dispatch(webhook.NewEvent(webhook.ContactDeleted, userID, contactID, nil))
+
+	requestid.Logf(ctx, "Deleted contact: %s for user: %s", contactID, userID)
 	return nil
 }
 
-// ListAllUsers returns all users with list caching
-// Flow: Check list cache → If miss, query DB → Cache list → Return
-func (s *AppServiceWithCache) ListAllContacts(ctx context.Context) ([]*models.ContactEntity, error) {
-	cacheKey := "contacts:list"
+// MoveContact reassigns a contact to another user and records who did it.
+// Flow: Load contact + target user → Transaction (put under new owner, delete old items, move ContactCount) → Invalidate both users' caches → Audit (best effort)
+// The contact keeps its ID; only its partition (owner) changes.
+func (s *AppServiceWithCache) MoveContact(ctx context.Context, fromUserID, contactID, toUserID, actor string) (*models.ContactEntity, error) {
+	ctx, span := tracing.Start(ctx, "AppService.MoveContact")
+	defer span.End()
 
-	// 1. Try to get from cache
-	cached, err := s.cache.Get(ctx, cacheKey).Result()
+	if fromUserID == toUserID {
+		return nil, ErrSameOwner
+	}
+
+	// 1. Load the contact from the DB (not the cache) and make sure the new owner exists
+	fromPK := fmt.Sprintf("USER#%s", fromUserID)
+	contact := &models.ContactEntity{}
+	if err := s.repo.Get(ctx, fromPK, fmt.Sprintf("CONTACT#%s", contactID), contact); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("contact not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get contact: %w", err)
+	}
+	if err := s.repo.Get(ctx, fmt.Sprintf("USER#%s", toUserID), "METADATA", &models.UserEntity{}); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("target user not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get target user: %w", err)
+	}
+
+	// 2. Move the contact (and its favorites index item) in one transaction
+	moved := models.NewContact(contact.ID, toUserID, contact.Name, contact.Email, contact.Phone, contact.Company, contact.JobTitle, contact.Address, contact.Notes, contact.IsFavorite)
+	moved.CreatedAt = contact.CreatedAt
+	moved.AvatarURL = contact.AvatarURL
+	moved.Tags = contact.Tags
+	moved.SetTimestamps()
+
+	puts := []repository.BaseModel{moved}
+	deletes := []map[string]string{{"PK": fromPK, "SK": contact.SK}}
+	if contact.IsFavorite {
+		puts = append(puts, models.NewFavoriteContactIndex(moved))
+		deletes = append(deletes, map[string]string{"PK": fromPK, "SK": fmt.Sprintf("FAV#%s", contactID)})
+	}
+	tx := repository.TxItems{
+		Puts:    puts,
+		Deletes: deletes,
+		Updates: []repository.TxUpdate{contactCountUpdate(fromUserID, -1), contactCountUpdate(toUserID, 1)},
+	}
+	if err := s.repo.TransactWrite(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to move contact: %w", err)
+	}
+	s.dropCachedUser(ctx, fromUserID)
+	s.dropCachedUser(ctx, toUserID)
+
+	// 3. Drop the old owner's copy and refresh both owners' lists
+	if err := s.cache.Del(ctx, fmt.Sprintf("contact:%s:%s", fromUserID, contactID)); err != nil {
+		requestid.Logf(ctx, "Warning: failed to delete from cache: %v", err)
+	}
+	if err := s.invalidateUserContactCaches(ctx, fromUserID); err != nil {
+		requestid.Logf(ctx, "Warning: failed to invalidate contact caches: %v", err)
+	}
+	if err := s.cacheContact(ctx, moved); err != nil {
+		requestid.Logf(ctx, "Warning: failed to cache contact: %v", err)
+	}
+	if err := s.refreshUserContactCaches(ctx, moved); err != nil {
+		requestid.Logf(ctx, "Warning: failed to refresh contact caches: %v", err)
+	}
+
+	// 4. Record the reassignment for support
+	entry := newAuditEntry(EntityContact, contactID, models.AuditContactTransferred, actor)
+	entry.OldOwnerID = fromUserID
+	entry.NewOwnerID = toUserID
+	s.appendAudit(ctx, entry)
+
+	// 5. Notify webhooks: gone from one user, new to the other
+	s.webhooks.Dispatch(webhook.NewEvent(webhook.ContactDeleted, fromUserID, contactID, nil))
+	s.webhooks.Dispatch(webhook.NewEvent(webhook.ContactCreated, toUserID, contactID, moved))
+
+	requestid.Logf(ctx, "Moved contact: %s from user %s to user %s (by %s)", contactID, fromUserID, toUserID, actor)
+	return moved, nil
+}
+
+// ContactListOptions selects one page of the admin contact listing
+type ContactListOptions struct {
+	Limit      int           // Clamped to pagination.MaxPageSize
+	Cursor     string        // next_cursor of the previous page; empty for the first
+	EntityType string        // GSI1 partition to list: CONTACT (default) or CONTACT_TRASH
+	Filter     ContactSearch // Applied by DynamoDB after Limit, so pages can come back short
+}
+
+// ContactListPage is one page of ListAllContacts; NextCursor is empty on the last page
+type ContactListPage struct {
+	Contacts   []*models.ContactEntity `json:"contacts"`
+	NextCursor string                  `json:"next_cursor"`
+}
+
+// contactListEntityTypes are the GSI1 partitions ListAllContacts may page through
+var contactListEntityTypes = map[string]bool{"CONTACT": true, "CONTACT_TRASH": true}
+
+// ListAllContacts returns one page of every user's contacts from GSI1, in GSI1SK
+// (contact ID) order so pages stay stable while contacts are added. Each distinct
+// page (options + cursor) is cached under its own key.
+// Flow: Normalize options → Check page cache → If miss, QueryIndex one page → Cache page → Return
+func (s *AppServiceWithCache) ListAllContacts(ctx context.Context, opts ContactListOptions) (*ContactListPage, error) {
+	ctx, span := tracing.Start(ctx, "AppService.ListAllContacts")
+	defer span.End()
+
+	// 1. Normalize options so equivalent requests share a cache entry
+	opts.Limit = pagination.ClampLimit(opts.Limit)
+	if opts.EntityType == "" {
+		opts.EntityType = "CONTACT"
+	}
+	if !contactListEntityTypes[opts.EntityType] {
+		return nil, &validation.FieldError{Field: "entity", Message: "must be CONTACT or CONTACT_TRASH"}
+	}
+	startKey, err := pagination.DecodeCursor(opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if gsi1pk, ok := startKey["GSI1PK"].(*types.AttributeValueMemberS); startKey != nil && (!ok || gsi1pk.Value != opts.EntityType) {
+		return nil, pagination.ErrInvalidCursor
+	}
+	cacheKey, err := contactListKey(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Try to get from cache
+	cached, err := s.cache.Get(ctx, cacheKey)
 	if err == nil {
 		// Cache HIT!
-		log.Printf("Cache HIT for contact list")
-		var users []*models.ContactEntity
-		if err := json.Unmarshal([]byte(cached), &users); err == nil {
-			return users, nil
+		requestid.Logf(ctx, "Cache HIT for contact list page")
+		var page ContactListPage
+		if err := decodeCached(cached, &page); err == nil {
+			return &page, nil
 		}
 	}
 
-	// 2. Cache MISS - query DynamoDB
-	log.Printf("Cache MISS for contact list")
-	var contacts []*models.ContactEntity
-	if err := s.repo.QueryByEntityType(ctx, "CONTACT", &contacts); err != nil {
+	// 3. Cache MISS - read one page of the index
+	requestid.Logf(ctx, "Cache MISS for contact list page")
+	if IsCacheOnly(ctx) {
+		return nil, ErrNotCached
+	}
+	queryOpts := repository.QueryOptions{Limit: opts.Limit, StartKey: startKey}
+	if cond, ok := opts.Filter.condition(); ok {
+		queryOpts.Filter = &cond
+	}
+	page := &ContactListPage{}
+	keyCond := expression.Key("GSI1PK").Equal(expression.Value(opts.EntityType))
+	lastKey, err := s.repo.QueryIndex(ctx, "GSI1", keyCond, queryOpts, &page.Contacts)
+	if err != nil {
 		return nil, fmt.Errorf("failed to list contacts: %w", err)
 	}
+	page.NextCursor = pagination.EncodeCursor(lastKey)
+
+	// 4. Cache the page (unless it's too big to be worth it)
+	s.cacheList(ctx, cacheKey, len(page.Contacts), page)
+
+	return page, nil
+}
+
+// contactListKey is the cache key of one ListAllContacts page:
+// contacts:list:<sha256 of the normalized options>
+func contactListKey(opts ContactListOptions) (string, error) {
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to build contact list cache key: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return "contacts:list:" + hex.EncodeToString(sum[:]), nil
+}
+
+// ============================================================================
+// BULK IMPORT
+// ============================================================================
 
-	// 3. Cache the list
-	if data, err := json.Marshal(contacts); err == nil {
-		if err := s.cache.Set(ctx, cacheKey, data, s.ttl).Err(); err != nil {
-			log.Printf("Warning: failed to cache contact list: %v", err)
+// ContactInput holds the caller-supplied fields of one contact
+type ContactInput struct {
+	Name       string   `json:"name"`
+	Email      string   `json:"email"`
+	Phone      string   `json:"phone"`
+	Company    string   `json:"company"`
+	JobTitle   string   `json:"job_title"`
+	Address    string   `json:"address"`
+	Notes      string   `json:"notes"`
+	Tags       []string `json:"tags"`
+	IsFavorite bool     `json:"is_favorite"`
+}
+
+// newContact builds the entity for in under the given contact ID and owner
+func (in ContactInput) newContact(id, userID string) *models.ContactEntity {
+	contact := models.NewContact(id, userID, in.Name, in.Email, in.Phone, in.Company, in.JobTitle, in.Address, in.Notes, in.IsFavorite)
+	contact.Tags = in.Tags
+	return contact
+}
+
+// ImportRejection explains why one input row was not imported
+type ImportRejection struct {
+	Index int    `json:"index"`
+	Email string `json:"email,omitempty"`
+	Error string `json:"error"`
+}
+
+// Import conflict strategies: what ImportContacts does with a row whose email matches
+// an existing contact
+const (
+	ImportConflictSkip      = "skip"      // Leave the existing contact untouched (the default)
+	ImportConflictOverwrite = "overwrite" // Replace its fields with the row's
+	ImportConflictMerge     = "merge"     // Fill its empty fields from the row, union tags
+)
+
+// ErrInvalidConflictStrategy is returned by ImportContacts for an unknown strategy
+var ErrInvalidConflictStrategy = errors.New("invalid conflict strategy")
+
+// What happened to one import row
+const (
+	ImportActionCreated     = "created"
+	ImportActionSkipped     = "skipped"
+	ImportActionOverwritten = "overwritten"
+	ImportActionMerged      = "merged"
+	ImportActionRejected    = "rejected"
+)
+
+// ImportItem reports what happened to one input row. ContactID is the contact it
+// created or changed (or, when skipped, the existing contact it matched).
+type ImportItem struct {
+	Index     int    `json:"index"`
+	Email     string `json:"email,omitempty"`
+	Action    string `json:"action"`
+	ContactID string `json:"contact_id,omitempty"`
+}
+
+// ImportResult reports the outcome of a bulk contact import.
+// Imported holds every contact written (created, overwritten or merged) and Rejected
+// every row that wasn't, including skipped conflicts; Items has one entry per row.
+// On a dry run Imported lists the contacts that would have been written.
+type ImportResult struct {
+	DryRun   bool                    `json:"dry_run"`
+	Imported []*models.ContactEntity `json:"imported"`
+	Rejected []ImportRejection       `json:"rejected"`
+	Items    []ImportItem            `json:"items"`
+}
+
+// ImportContacts creates many contacts for a user in one BatchWrite. A row whose email
+// matches an existing contact (ignoring case) is resolved with onConflict
+// (ImportConflictSkip when empty); a row repeating an earlier row's email is always rejected. Overwritten and
+// merged contacts are written one transaction each, and a row whose contact was edited
+// during the import is rejected with ErrContactModified instead of overwriting the edit.
+// Flow: Validate rows → Resolve conflicts (existing) / reject duplicates (within batch) → BatchWrite new → Conditional writes of changed → Refresh caches → Webhooks
+// With dryRun set, everything up to BatchWrite runs and nothing is persisted or invalidated.
+func (s *AppServiceWithCache) ImportContacts(ctx context.Context, userID string, inputs []ContactInput, dryRun bool, onConflict string) (*ImportResult, error) {
+	ctx, span := tracing.Start(ctx, "AppService.ImportContacts")
+	defer span.End()
+
+	switch onConflict {
+	case "":
+		onConflict = ImportConflictSkip
+	case ImportConflictSkip, ImportConflictOverwrite, ImportConflictMerge:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidConflictStrategy, onConflict)
+	}
+
+	result := &ImportResult{
+		DryRun:   dryRun,
+		Imported: make([]*models.ContactEntity, 0, len(inputs)),
+		Rejected: make([]ImportRejection, 0),
+		Items:    make([]ImportItem, 0, len(inputs)),
+	}
+	reject := func(i int, in ContactInput, err error) {
+		result.Rejected = append(result.Rejected, ImportRejection{Index: i, Email: in.Email, Error: err.Error()})
+		result.Items = append(result.Items, ImportItem{Index: i, Email: in.Email, Action: ImportActionRejected})
+	}
+
+	// 1. Load every page of existing contacts once instead of querying per row. Emails
+	//    match ignoring case and surrounding space, as in contactEmailExists.
+	pk := fmt.Sprintf("USER#%s", userID)
+	existing, err := s.queryAllContacts(ctx, pk, "CONTACT#", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load existing contacts: %w", err)
+	}
+
+	byEmail := make(map[string]*models.ContactEntity, len(existing))
+	for _, contact := range existing {
+		if email := normalizeEmail(contact.Email); email != "" && byEmail[email] == nil {
+			byEmail[email] = contact
 		}
 	}
+	seen := make(map[string]bool, len(inputs)) // normalized emails of earlier rows
 
-	return contacts, nil
+	allowed, err := s.emailDomainAllowlist(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Validate each row, then create it or resolve its conflict
+	items := make([]repository.BaseModel, 0, len(inputs))
+	var changes []importChange
+	var creates []*models.ContactEntity
+	for i, in := range inputs {
+		if err := validateContactInput(in, allowed); err != nil {
+			reject(i, in, err)
+			continue
+		}
+		email := normalizeEmail(in.Email)
+		if email != "" && seen[email] {
+			reject(i, in, ErrContactExists)
+			continue
+		}
+		if email != "" {
+			seen[email] = true
+		}
+
+		contact, action := in.newContact(uuid.New().String(), userID), ImportActionCreated
+		current := byEmail[email] // never set for ""
+		if current != nil {
+			switch onConflict {
+			case ImportConflictSkip:
+				result.Rejected = append(result.Rejected, ImportRejection{Index: i, Email: in.Email, Error: ErrContactExists.Error()})
+				result.Items = append(result.Items, ImportItem{Index: i, Email: in.Email, Action: ImportActionSkipped, ContactID: current.ID})
+				continue
+			case ImportConflictOverwrite:
+				contact, action = overwriteContact(current, in), ImportActionOverwritten
+			case ImportConflictMerge:
+				contact, action = mergeContact(current, in), ImportActionMerged
+			}
+		}
+
+		contact.SetTimestamps() // BatchWrite doesn't stamp items the way Put does
+		result.Imported = append(result.Imported, contact)
+		result.Items = append(result.Items, ImportItem{Index: i, Email: in.Email, Action: action, ContactID: contact.ID})
+		if current != nil {
+			changes = append(changes, importChange{item: len(result.Items) - 1, read: current, contact: contact})
+			continue
+		}
+		creates = append(creates, contact)
+		items = append(items, contact)
+		if contact.IsFavorite {
+			items = append(items, models.NewFavoriteContactIndex(contact))
+		}
+	}
+
+	if dryRun || len(result.Imported) == 0 {
+		requestid.Logf(ctx, "Import for user %s: %d valid, %d rejected (dry run: %t)", userID, len(result.Imported), len(result.Rejected), dryRun)
+		return result, nil
+	}
+
+	// 3. Persist new contacts in batches of 25
+	if len(items) > 0 {
+		if err := s.repo.BatchWrite(ctx, items, nil); err != nil {
+			return nil, fmt.Errorf("failed to import contacts: %w", err)
+		}
+		s.adjustContactCount(ctx, userID, int64(len(creates)))
+		for _, contact := range creates {
+			s.webhooks.Dispatch(webhook.NewEvent(webhook.ContactCreated, userID, contact.ID, contact))
+			s.queueEnrichment(ctx, contact)
+		}
+	}
+
+	// 4. Replace each changed contact, with its favorites index item, in a transaction
+	//    of its own that only applies if the contact is still as read. A contact edited
+	//    since is left alone and its row reported as rejected.
+	var changed []*models.ContactEntity
+	for _, change := range changes {
+		if err := s.repo.TransactWrite(ctx, change.tx(pk)); err != nil {
+			requestid.Logf(ctx, "Warning: import row %d not applied to contact %s: %v", result.Items[change.item].Index, change.contact.ID, err)
+			if errors.Is(err, repository.ErrConditionFailed) {
+				err = ErrContactModified
+			}
+			result.fail(change, err)
+			continue
+		}
+		changed = append(changed, change.contact)
+	}
+
+	// 5. Refresh the cached copies of changed contacts, notify webhooks and invalidate
+	//    the list caches
+	for _, contact := range changed {
+		if err := s.cacheContact(ctx, contact); err != nil {
+			requestid.Logf(ctx, "Warning: failed to update cache: %v", err)
+		}
+		s.webhooks.Dispatch(webhook.NewEvent(webhook.ContactUpdated, userID, contact.ID, contact))
+	}
+	if err := s.invalidateUserContactCaches(ctx, userID); err != nil {
+		requestid.Logf(ctx, "Warning: failed to invalidate contact caches: %v", err)
+	}
+
+	requestid.Logf(ctx, "Imported %d contacts for user: %s (%d created, %d %s, %d rejected)",
+		len(result.Imported), userID, len(creates), len(changed), onConflict, len(result.Rejected))
+	return result, nil
+}
+
+// ErrContactModified rejects an import row whose contact was edited while the import ran
+var ErrContactModified = errors.New("contact was modified during the import")
+
+// importChange is an import row that replaces an existing contact
+type importChange struct {
+	item    int                   // Position in ImportResult.Items
+	read    *models.ContactEntity // The contact as the import read it
+	contact *models.ContactEntity // What replaces it
+}
+
+// tx replaces the contact unless its UpdatedAt moved on since it was read, keeping its
+// FAV# index item in step
+func (c importChange) tx(pk string) repository.TxItems {
+	tx := repository.TxItems{ConditionalPuts: []repository.TxPut{{
+		Item:      c.contact,
+		Condition: expression.Name("UpdatedAt").Equal(expression.Value(c.read.UpdatedAt)),
+	}}}
+	switch {
+	case c.contact.IsFavorite:
+		tx.Puts = append(tx.Puts, models.NewFavoriteContactIndex(c.contact))
+	case c.read.IsFavorite:
+		tx.Deletes = append(tx.Deletes, map[string]string{"PK": pk, "SK": "FAV#" + c.contact.ID})
+	}
+	return tx
+}
+
+// fail turns a change that wasn't written into a rejected row
+func (r *ImportResult) fail(change importChange, err error) {
+	item := &r.Items[change.item]
+	item.Action = ImportActionRejected
+	r.Imported = slices.DeleteFunc(r.Imported, func(c *models.ContactEntity) bool { return c == change.contact })
+	r.Rejected = append(r.Rejected, ImportRejection{Index: item.Index, Email: item.Email, Error: err.Error()})
+	slices.SortStableFunc(r.Rejected, func(a, b ImportRejection) int { return a.Index - b.Index })
+}
+
+// overwriteContact returns current with every caller-supplied field replaced by in's.
+// The contact keeps its ID, creation time and avatar.
+func overwriteContact(current *models.ContactEntity, in ContactInput) *models.ContactEntity {
+	contact := in.newContact(current.ID, current.UserID)
+	contact.CreatedAt = current.CreatedAt
+	contact.AvatarURL = current.AvatarURL
+	return contact
+}
+
+// mergeContact returns current with its empty fields filled from in. Tags are the union
+// of both, and the contact is a favorite if either is.
+func mergeContact(current *models.ContactEntity, in ContactInput) *models.ContactEntity {
+	merged := *current
+	for _, field := range []struct {
+		dst *string
+		src string
+	}{
+		{&merged.Name, in.Name},
+		{&merged.Phone, in.Phone},
+		{&merged.Company, in.Company},
+		{&merged.JobTitle, in.JobTitle},
+		{&merged.Address, in.Address},
+		{&merged.Notes, in.Notes},
+	} {
+		if *field.dst == "" {
+			*field.dst = field.src
+		}
+	}
+	merged.Tags = append([]string(nil), current.Tags...)
+	for _, tag := range in.Tags {
+		if !slices.Contains(merged.Tags, tag) {
+			merged.Tags = append(merged.Tags, tag)
+		}
+	}
+	merged.IsFavorite = current.IsFavorite || in.IsFavorite
+	return &merged
+}
+
+// BatchContactInput is one contact in a CreateContacts batch; unlike an import,
+// a batch may create contacts for several users
+type BatchContactInput struct {
+	UserID string
+	ContactInput
+}
+
+// Batch item error codes
+const (
+	BatchErrValidation  = "VALIDATION"   // Input rejected; fix it before retrying
+	BatchErrWriteFailed = "WRITE_FAILED" // Not written (throttled or failed); safe to retry as-is
+)
+
+// BatchItemError identifies one batch input that was not created
+type BatchItemError struct {
+	Index   int    `json:"index"`
+	Code    string `json:"code"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// BatchCreateResult is the outcome of CreateContacts: everything in Contacts was
+// written, and every input that wasn't has an entry in Errors
+type BatchCreateResult struct {
+	Contacts []*models.ContactEntity `json:"contacts"`
+	Errors   []BatchItemError        `json:"errors"`
+}
+
+// CreateContacts creates many contacts in one BatchWrite, reporting failures per input
+// instead of failing the whole batch.
+// Flow: Validate rows → BatchWrite (retries unprocessed items) → Map failed keys back to inputs → Cache + invalidate lists per user
+func (s *AppServiceWithCache) CreateContacts(ctx context.Context, inputs []BatchContactInput) (*BatchCreateResult, error) {
+	ctx, span := tracing.Start(ctx, "AppService.CreateContacts")
+	defer span.End()
+
+	result := &BatchCreateResult{
+		Contacts: make([]*models.ContactEntity, 0, len(inputs)),
+		Errors:   make([]BatchItemError, 0),
+	}
+
+	// 1. Validate each row and build its items
+	contacts := make(map[int]*models.ContactEntity, len(inputs))
+	owner := make(map[string]int) // "PK|SK" of every item -> input index
+	items := make([]repository.BaseModel, 0, len(inputs))
+	allowlists := make(map[string][]string) // per user, loaded once
+	for i, in := range inputs {
+		allowed, ok := allowlists[in.UserID]
+		if !ok {
+			var err error
+			if allowed, err = s.emailDomainAllowlist(ctx, in.UserID); err != nil {
+				return nil, err
+			}
+			allowlists[in.UserID] = allowed
+		}
+		if err := validateContactInput(in.ContactInput, allowed); err != nil {
+			itemErr := BatchItemError{Index: i, Code: BatchErrValidation, Message: err.Error()}
+			var fieldErr *validation.FieldError
+			if errors.As(err, &fieldErr) {
+				itemErr.Field = fieldErr.Field
+				itemErr.Message = fieldErr.Message
+			}
+			result.Errors = append(result.Errors, itemErr)
+			continue
+		}
+
+		contact := in.newContact(uuid.New().String(), in.UserID)
+		contact.SetTimestamps() // BatchWrite doesn't stamp items the way Put does
+		contacts[i] = contact
+		items = append(items, contact)
+		owner[contact.PK+"|"+contact.SK] = i
+		if contact.IsFavorite {
+			fav := models.NewFavoriteContactIndex(contact)
+			items = append(items, fav)
+			owner[fav.PK+"|"+fav.SK] = i
+		}
+	}
+
+	// 2. Persist; only items still unwritten after retries come back as failed
+	failedContacts := make(map[int]bool)
+	failedFavorites := make(map[int]bool)
+	if len(items) > 0 {
+		if err := s.repo.BatchWrite(ctx, items, nil); err != nil {
+			var batchErr *repository.BatchWriteError
+			if !errors.As(err, &batchErr) {
+				return nil, fmt.Errorf("failed to create contacts: %w", err)
+			}
+			requestid.Logf(ctx, "Warning: batch create partially failed: %v", err)
+			for _, key := range batchErr.Keys {
+				i, ok := owner[key["PK"]+"|"+key["SK"]]
+				if !ok {
+					continue
+				}
+				if strings.HasPrefix(key["SK"], "FAV#") {
+					failedFavorites[i] = true
+				} else {
+					failedContacts[i] = true
+				}
+			}
+		}
+	}
+
+	// 3. Sort out partial writes so each input is either fully created or not at all
+	touchedUsers := make(map[string]bool)
+	for i := range inputs {
+		contact, ok := contacts[i]
+		if !ok {
+			continue
+		}
+
+		if failedContacts[i] {
+			if contact.IsFavorite && !failedFavorites[i] {
+				// The favorites copy landed without its contact; drop the orphan
+				if _, err := s.repo.DeleteIfExists(ctx, contact.PK, fmt.Sprintf("FAV#%s", contact.ID)); err != nil {
+					requestid.Logf(ctx, "Warning: failed to remove orphaned favorites index for contact %s: %v", contact.ID, err)
+				}
+			}
+			result.Errors = append(result.Errors, BatchItemError{Index: i, Code: BatchErrWriteFailed, Message: "contact was not written"})
+			continue
+		}
+		if failedFavorites[i] {
+			// The contact exists, so finish it rather than report it as failed
+			if err := s.syncFavoriteIndex(ctx, contact); err != nil {
+				requestid.Logf(ctx, "Warning: failed to write favorites index for contact %s: %v", contact.ID, err)
+			}
+		}
+
+		if err := s.cacheContact(ctx, contact); err != nil {
+			requestid.Logf(ctx, "Warning: failed to cache contact: %v", err)
+		}
+		touchedUsers[contact.UserID] = true
+		result.Contacts = append(result.Contacts, contact)
+		s.webhooks.Dispatch(webhook.NewEvent(webhook.ContactCreated, contact.UserID, contact.ID, contact))
+		s.queueEnrichment(ctx, contact)
+	}
+	sort.Slice(result.Errors, func(a, b int) bool { return result.Errors[a].Index < result.Errors[b].Index })
+
+	// 4. Count the new contacts and invalidate contact list caches once per affected user
+	created := make(map[string]int64, len(touchedUsers))
+	for _, contact := range result.Contacts {
+		created[contact.UserID]++
+	}
+	for userID := range touchedUsers {
+		s.adjustContactCount(ctx, userID, created[userID])
+		if err := s.invalidateUserContactCaches(ctx, userID); err != nil {
+			requestid.Logf(ctx, "Warning: failed to invalidate contact caches: %v", err)
+		}
+	}
+
+	requestid.Logf(ctx, "Batch created %d/%d contacts", len(result.Contacts), len(inputs))
+	return result, nil
+}
+
+// ============================================================================
+// STREAMING (uncached, page at a time)
+// ============================================================================
+
+// UserPages returns a pager over all users straight from DynamoDB.
+// Used for NDJSON streaming where the full list must never be buffered or cached.
+func (s *AppServiceWithCache) UserPages(ctx context.Context) (*repository.QueryPager, error) {
+	return s.repo.QueryByEntityTypePages(ctx, "USER")
+}
+
+// UserContactPages returns a pager over a user's contacts straight from DynamoDB
+func (s *AppServiceWithCache) UserContactPages(ctx context.Context, userID string) (*repository.QueryPager, error) {
+	return s.repo.QueryPages(ctx, fmt.Sprintf("USER#%s", userID), "CONTACT#")
+}
+
+// ContactPages returns a pager over every contact straight from DynamoDB
+func (s *AppServiceWithCache) ContactPages(ctx context.Context) (*repository.QueryPager, error) {
+	return s.repo.QueryByEntityTypePages(ctx, "CONTACT")
 }
 
 // ============================================================================
@@ -436,41 +1931,90 @@ func (s *AppServiceWithCache) ListAllContacts(ctx context.Context) ([]*models.Co
 // cacheUser caches an individual user
 func (s *AppServiceWithCache) cacheUser(ctx context.Context, user *models.UserEntity) error {
 	cacheKey := fmt.Sprintf("user:%s", user.ID)
-	data, err := json.Marshal(user)
+	data, err := s.encodeCached(user)
 	if err != nil {
 		return err
 	}
-	return s.cache.Set(ctx, cacheKey, data, s.ttl).Err()
+	return s.cacheSet(ctx, cacheKey, data, s.CacheTTLs().User)
+}
+
+// cacheList caches a list result, skipping it when it exceeds the configured size limits
+func (s *AppServiceWithCache) cacheList(ctx context.Context, cacheKey string, count int, list interface{}) {
+	if s.maxCacheItems > 0 && count > s.maxCacheItems {
+		requestid.Logf(ctx, "Skipping cache for %s: %d items exceeds limit of %d", cacheKey, count, s.maxCacheItems)
+		return
+	}
+
+	data, err := s.encodeCached(list)
+	if err != nil {
+		requestid.Logf(ctx, "Warning: failed to marshal %s for cache: %v", cacheKey, err)
+		return
+	}
+
+	if s.maxCacheBytes > 0 && len(data) > s.maxCacheBytes {
+		requestid.Logf(ctx, "Skipping cache for %s: %d bytes exceeds limit of %d", cacheKey, len(data), s.maxCacheBytes)
+		return
+	}
+
+	if err := s.cacheSet(ctx, cacheKey, data, s.CacheTTLs().List); err != nil {
+		requestid.Logf(ctx, "Warning: failed to cache %s: %v", cacheKey, err)
+	}
 }
 
 // invalidateUserListCache invalidates the user list cache
 func (s *AppServiceWithCache) invalidateUserListCache(ctx context.Context) error {
-	return s.cache.Del(ctx, "users:list").Err()
+	return s.cache.Del(ctx, "users:list")
 }
 
 // cacheContact caches an individual contact
 func (s *AppServiceWithCache) cacheContact(ctx context.Context, contact *models.ContactEntity) error {
 	cacheKey := fmt.Sprintf("contact:%s:%s", contact.UserID, contact.ID)
-	data, err := json.Marshal(contact)
+	data, err := s.encodeCached(contact)
 	if err != nil {
 		return err
 	}
-	return s.cache.Set(ctx, cacheKey, data, s.ttl).Err()
+	return s.cache.Set(ctx, cacheKey, data, s.CacheTTLs().User)
 }
 
-// invalidateUserContactCaches invalidates all contact caches for a user
-func (s *AppServiceWithCache) invalidateUserContactCaches(ctx context.Context, userID string) error {
-	// Invalidate user's contact list
-	if err := s.cache.Del(ctx, fmt.Sprintf("contacts:user:%s", userID)).Err(); err != nil {
-		return err
+// syncFavoriteIndex writes or removes the FAV#<id> index item to match contact.IsFavorite
+func (s *AppServiceWithCache) syncFavoriteIndex(ctx context.Context, contact *models.ContactEntity) error {
+	if contact.IsFavorite {
+		return s.repo.Put(ctx, models.NewFavoriteContactIndex(contact))
 	}
-	
-	// Invalidate user's favorites list
-	if err := s.cache.Del(ctx, fmt.Sprintf("contacts:favorites:%s", userID)).Err(); err != nil {
-		return err
+
+	pk := fmt.Sprintf("USER#%s", contact.UserID)
+	_, err := s.repo.DeleteIfExists(ctx, pk, fmt.Sprintf("FAV#%s", contact.ID))
+	return err
+}
+
+// Per-user contact list views. Every view is cached under contacts:<view>:user:<id>
+// so invalidateUserContactCaches can clear them all with one pattern.
+const (
+	contactViewAll       = "all"
+	contactViewFavorites = "favorites"
+	contactViewProjected = "projected" // All contacts, as read from the contact list index
+)
+
+// contactListKey returns the cache key ListUserContacts reads. Contacts read from the
+// contact list index carry only the projected attributes, so they get their own key and
+// are never served where full contacts are expected.
+func (s *AppServiceWithCache) contactListKey(userID string) string {
+	if s.contactListIndex != "" {
+		return userContactListKey(contactViewProjected, userID)
 	}
-	
-	return nil
+	return userContactListKey(contactViewAll, userID)
+}
+
+// userContactListKey returns the cache key for one of a user's contact list views
+func userContactListKey(view, userID string) string {
+	return fmt.Sprintf("contacts:%s:user:%s", view, userID)
+}
+
+// invalidateUserContactCaches invalidates all contact list caches for a user.
+// New list views only need a key from userContactListKey to be covered here.
+func (s *AppServiceWithCache) invalidateUserContactCaches(ctx context.Context, userID string) error {
+	_, err := s.cache.DelPattern(ctx, userContactListKey("*", escapeKeyPattern(userID)))
+	return err
 }
 
 // ============================================================================
@@ -480,21 +2024,27 @@ func (s *AppServiceWithCache) invalidateUserContactCaches(ctx context.Context, u
 // GetUserDashboard gets all data for a user with caching
 // Flow: Check cache → If miss, query DB → Cache dashboard → Return
 func (s *AppServiceWithCache) GetUserDashboard(ctx context.Context, userID string) (*UserDashboard, error) {
+	ctx, span := tracing.Start(ctx, "AppService.GetUserDashboard")
+	defer span.End()
+
 	cacheKey := fmt.Sprintf("dashboard:%s", userID)
 
 	// 1. Try to get from cache
-	cached, err := s.cache.Get(ctx, cacheKey).Result()
+	cached, err := s.cache.Get(ctx, cacheKey)
 	if err == nil {
 		// Cache HIT!
-		log.Printf("Cache HIT for user %s dashboard", userID)
+		requestid.Logf(ctx, "Cache HIT for user %s dashboard", userID)
 		var dashboard UserDashboard
-		if err := json.Unmarshal([]byte(cached), &dashboard); err == nil {
+		if err := decodeCached(cached, &dashboard); err == nil {
 			return &dashboard, nil
 		}
 	}
 
 	// 2. Cache MISS - query DynamoDB
-	log.Printf("Cache MISS for user %s dashboard", userID)
+	requestid.Logf(ctx, "Cache MISS for user %s dashboard", userID)
+	if IsCacheOnly(ctx) {
+		return nil, ErrNotCached
+	}
 	pk := fmt.Sprintf("USER#%s", userID)
 	
 	var allItems []map[string]interface{}
@@ -525,10 +2075,9 @@ func (s *AppServiceWithCache) GetUserDashboard(ctx context.Context, userID strin
 	}
 
 	// 3. Cache the dashboard
-	if data, err := json.Marshal(dashboard); err == nil {
-		// Shorter TTL for dashboard since it aggregates multiple entities
-		if err := s.cache.Set(ctx, cacheKey, data, 2*time.Minute).Err(); err != nil {
-			log.Printf("Warning: failed to cache dashboard: %v", err)
+	if data, err := s.encodeCached(dashboard); err == nil {
+		if err := s.cache.Set(ctx, cacheKey, data, s.CacheTTLs().Dashboard); err != nil {
+			requestid.Logf(ctx, "Warning: failed to cache dashboard: %v", err)
 		}
 	}
 
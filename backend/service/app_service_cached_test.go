@@ -0,0 +1,2696 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"hub-control-plane/backend/clock"
+	"hub-control-plane/backend/enrichment"
+	"hub-control-plane/backend/featureflag"
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/pagination"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/validation"
+	"hub-control-plane/backend/webhook"
+)
+
+// fakeRepo is an in-memory single table keyed by PK then SK. Methods the tests
+// don't exercise fall through to the nil embedded interface and panic.
+type fakeRepo struct {
+	repository.SingleTableRepository
+	items map[string]map[string]map[string]types.AttributeValue
+	gets  int // successful Get calls, to tell cache hits from DB reads
+
+	// failBatchPut makes BatchWrite report matching puts as unprocessed
+	failBatchPut func(item repository.BaseModel) bool
+
+	// scanMatch stands in for Scan's filter expression (nil = Reindex's filter)
+	scanMatch func(item map[string]types.AttributeValue) bool
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{items: make(map[string]map[string]map[string]types.AttributeValue)}
+}
+
+func (f *fakeRepo) put(item repository.BaseModel) error {
+	if timestamped, ok := item.(interface{ SetTimestamps() }); ok {
+		timestamped.SetTimestamps()
+	}
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+	if f.items[item.GetPK()] == nil {
+		f.items[item.GetPK()] = make(map[string]map[string]types.AttributeValue)
+	}
+	f.items[item.GetPK()][item.GetSK()] = av
+	return nil
+}
+
+func (f *fakeRepo) Put(ctx context.Context, item repository.BaseModel) error {
+	return f.put(item)
+}
+
+func (f *fakeRepo) PutIfNotExists(ctx context.Context, item repository.BaseModel) error {
+	if _, ok := f.items[item.GetPK()][item.GetSK()]; ok {
+		return repository.ErrAlreadyExists
+	}
+	return f.put(item)
+}
+
+func (f *fakeRepo) PutIfAbsentOrGet(ctx context.Context, item repository.BaseModel, result repository.BaseModel) (bool, error) {
+	if existing, ok := f.items[item.GetPK()][item.GetSK()]; ok {
+		return false, attributevalue.UnmarshalMap(existing, result)
+	}
+	return true, f.put(item)
+}
+
+func (f *fakeRepo) Get(ctx context.Context, pk, sk string, result repository.BaseModel) error {
+	item, ok := f.items[pk][sk]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	f.gets++
+	return attributevalue.UnmarshalMap(item, result)
+}
+
+func (f *fakeRepo) Delete(ctx context.Context, pk, sk string) error {
+	if _, ok := f.items[pk][sk]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(f.items[pk], sk)
+	return nil
+}
+
+func (f *fakeRepo) DeleteIfExists(ctx context.Context, pk, sk string) (bool, error) {
+	_, ok := f.items[pk][sk]
+	delete(f.items[pk], sk)
+	return ok, nil
+}
+
+func (f *fakeRepo) QueryItems(ctx context.Context, pk string, skPrefix string) ([]map[string]types.AttributeValue, error) {
+	sks := make([]string, 0, len(f.items[pk]))
+	for sk := range f.items[pk] {
+		if strings.HasPrefix(sk, skPrefix) {
+			sks = append(sks, sk)
+		}
+	}
+	sort.Strings(sks)
+
+	items := make([]map[string]types.AttributeValue, 0, len(sks))
+	for _, sk := range sks {
+		items = append(items, f.items[pk][sk])
+	}
+	return items, nil
+}
+
+func (f *fakeRepo) Query(ctx context.Context, pk string, skPrefix string, resultSlice interface{}) error {
+	items, _ := f.QueryItems(ctx, pk, skPrefix)
+	return attributevalue.UnmarshalListOfMaps(items, resultSlice)
+}
+
+// QueryPage pages through QueryItems' sorted results, resuming after startKey's SK
+func (f *fakeRepo) QueryPage(ctx context.Context, pk string, skPrefix string, limit int, startKey map[string]types.AttributeValue, resultSlice interface{}, projection ...string) (map[string]types.AttributeValue, error) {
+	items, _ := f.QueryItems(ctx, pk, skPrefix)
+	if startKey != nil {
+		after := startKey["SK"].(*types.AttributeValueMemberS).Value
+		for len(items) > 0 && items[0]["SK"].(*types.AttributeValueMemberS).Value <= after {
+			items = items[1:]
+		}
+	}
+
+	var lastKey map[string]types.AttributeValue
+	if len(items) > limit {
+		items = items[:limit]
+		lastKey = map[string]types.AttributeValue{"PK": items[limit-1]["PK"], "SK": items[limit-1]["SK"]}
+	}
+	return lastKey, attributevalue.UnmarshalListOfMaps(items, resultSlice)
+}
+
+// QueryWithFilter applies only the key condition; callers' filters aren't evaluated,
+// so tests must only store items the filter would have kept
+func (f *fakeRepo) QueryWithFilter(ctx context.Context, pk string, skPrefix string, filter expression.ConditionBuilder, resultSlice interface{}) error {
+	return f.Query(ctx, pk, skPrefix, resultSlice)
+}
+
+func (f *fakeRepo) Count(ctx context.Context, pk string, skPrefix string) (int, error) {
+	items, err := f.QueryItems(ctx, pk, skPrefix)
+	return len(items), err
+}
+
+// QueryWithStats, like QueryWithFilter, ignores the filter; every item counts as scanned and returned
+func (f *fakeRepo) QueryWithStats(ctx context.Context, pk string, skPrefix string, filter *expression.ConditionBuilder) ([]map[string]types.AttributeValue, repository.QueryStats, error) {
+	items, _ := f.QueryItems(ctx, pk, skPrefix)
+	return items, repository.QueryStats{ScannedCount: len(items), Count: len(items), Pages: 1}, nil
+}
+
+// Update overwrites the given attributes of an existing item
+func (f *fakeRepo) Update(ctx context.Context, pk, sk string, updates map[string]interface{}) error {
+	item, ok := f.items[pk][sk]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	updates["UpdatedAt"] = clock.Now()
+	for key, value := range updates {
+		av, err := attributevalue.Marshal(value)
+		if err != nil {
+			return err
+		}
+		item[key] = av
+	}
+	return nil
+}
+
+// UpdateIfUnmodifiedSince applies the repository's rule directly: UpdatedAt, truncated
+// to the second, must not be after since
+func (f *fakeRepo) UpdateIfUnmodifiedSince(ctx context.Context, pk, sk string, updates map[string]interface{}, since time.Time) error {
+	item, ok := f.items[pk][sk]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	var updatedAt time.Time
+	if err := attributevalue.Unmarshal(item["UpdatedAt"], &updatedAt); err != nil {
+		return err
+	}
+	if updatedAt.Truncate(time.Second).After(since) {
+		return repository.ErrConditionFailed
+	}
+	return f.Update(ctx, pk, sk, updates)
+}
+
+// QueryByEntityType scans every partition for items in the entityType GSI1 partition
+func (f *fakeRepo) QueryByEntityType(ctx context.Context, entityType string, resultSlice interface{}) error {
+	return f.QueryByEntityTypeBetween(ctx, entityType, "", "\uffff", resultSlice)
+}
+
+// QueryByEntityTypeBetween scans every partition for matching GSI1 keys, sorted by GSI1SK
+func (f *fakeRepo) QueryByEntityTypeBetween(ctx context.Context, entityType, fromSK, toSK string, resultSlice interface{}) error {
+	gsiSK := func(item map[string]types.AttributeValue) string {
+		return item["GSI1SK"].(*types.AttributeValueMemberS).Value
+	}
+	var items []map[string]types.AttributeValue
+	for _, partition := range f.items {
+		for _, item := range partition {
+			pk, ok := item["GSI1PK"].(*types.AttributeValueMemberS)
+			if !ok || pk.Value != entityType {
+				continue
+			}
+			if sk := gsiSK(item); sk >= fromSK && sk <= toSK {
+				items = append(items, item)
+			}
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return gsiSK(items[i]) < gsiSK(items[j]) })
+	return attributevalue.UnmarshalListOfMaps(items, resultSlice)
+}
+
+// QueryIndex supports only GSI1 with GSI1PK = <value>: it pages by GSI1SK like
+// QueryPage, resuming after startKey. Filters and projections are ignored.
+func (f *fakeRepo) QueryIndex(ctx context.Context, indexName string, keyCond expression.KeyConditionBuilder, opts repository.QueryOptions, resultSlice interface{}) (map[string]types.AttributeValue, error) {
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+	if indexName != "GSI1" || len(expr.Values()) != 1 {
+		return nil, fmt.Errorf("fake QueryIndex can't run %q on %q", *expr.KeyCondition(), indexName)
+	}
+	var entityType string
+	for _, value := range expr.Values() {
+		entityType = value.(*types.AttributeValueMemberS).Value
+	}
+
+	var all []map[string]interface{}
+	if err := f.QueryByEntityType(ctx, entityType, &all); err != nil {
+		return nil, err
+	}
+	items, _ := attributevalue.MarshalList(all)
+	page := make([]map[string]types.AttributeValue, 0, len(items))
+	for _, item := range items {
+		page = append(page, item.(*types.AttributeValueMemberM).Value)
+	}
+	if opts.StartKey != nil {
+		after := opts.StartKey["GSI1SK"].(*types.AttributeValueMemberS).Value
+		for len(page) > 0 && page[0]["GSI1SK"].(*types.AttributeValueMemberS).Value <= after {
+			page = page[1:]
+		}
+	}
+
+	var lastKey map[string]types.AttributeValue
+	if opts.Limit > 0 && len(page) > opts.Limit {
+		page = page[:opts.Limit]
+		last := page[opts.Limit-1]
+		lastKey = map[string]types.AttributeValue{"PK": last["PK"], "SK": last["SK"], "GSI1PK": last["GSI1PK"], "GSI1SK": last["GSI1SK"]}
+	}
+	return lastKey, attributevalue.UnmarshalListOfMaps(page, resultSlice)
+}
+
+// SetIfMissing writes only the attributes the item doesn't have
+func (f *fakeRepo) SetIfMissing(ctx context.Context, pk, sk string, values map[string]interface{}) error {
+	item, ok := f.items[pk][sk]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	for key, value := range values {
+		if _, exists := item[key]; exists {
+			continue
+		}
+		av, err := attributevalue.Marshal(value)
+		if err != nil {
+			return err
+		}
+		item[key] = av
+	}
+	return nil
+}
+
+// Scan can't evaluate filter expressions: it keeps the items scanMatch accepts,
+// defaulting to Reindex's filters - "missing an index attribute or legacy user sort
+// key" when the filter names GSI1PK, every user otherwise - and returns a single page
+func (f *fakeRepo) Scan(ctx context.Context, filter expression.ConditionBuilder, pageSize int, fn func(items []map[string]types.AttributeValue) error) error {
+	match := f.scanMatch
+	if match == nil && !namesAttribute(filter, "GSI1PK") {
+		match = func(item map[string]types.AttributeValue) bool {
+			entityType, _ := item["EntityType"].(*types.AttributeValueMemberS)
+			return entityType != nil && entityType.Value == "USER"
+		}
+	}
+	if match == nil {
+		match = func(item map[string]types.AttributeValue) bool {
+			for _, name := range []string{"GSI1PK", "GSI1SK", "EntityType"} {
+				if _, ok := item[name]; !ok {
+					return true
+				}
+			}
+			sk, _ := item["GSI1SK"].(*types.AttributeValueMemberS)
+			return strings.HasPrefix(sk.Value, "USER#")
+		}
+	}
+
+	var page []map[string]types.AttributeValue
+	for _, partition := range f.items {
+		for _, item := range partition {
+			if match(item) {
+				page = append(page, item)
+			}
+		}
+	}
+	return fn(page)
+}
+
+// namesAttribute reports whether a filter expression refers to the attribute name
+func namesAttribute(filter expression.ConditionBuilder, name string) bool {
+	expr, err := expression.NewBuilder().WithFilter(filter).Build()
+	if err != nil {
+		return false
+	}
+	for _, n := range expr.Names() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeRepo) Transaction(ctx context.Context, puts []repository.BaseModel, deletes []map[string]string) error {
+	return f.TransactWrite(ctx, repository.TxItems{Puts: puts, Deletes: deletes})
+}
+
+// TransactWrite checks every condition before writing anything, like DynamoDB. It can't
+// evaluate condition expressions, so an update's or check's condition means "the item
+// exists", a conditional put's means "its UpdatedAt is the condition's one value" (the
+// only ones callers use; Creates mean it doesn't), and updates may only be SETs or a
+// single ADD of a number.
+func (f *fakeRepo) TransactWrite(ctx context.Context, tx repository.TxItems) error {
+	for i, put := range tx.ConditionalPuts {
+		expr, err := expression.NewBuilder().WithCondition(put.Condition).Build()
+		if err != nil {
+			return err
+		}
+		stored, _ := f.items[put.Item.GetPK()][put.Item.GetSK()]["UpdatedAt"].(*types.AttributeValueMemberS)
+		for _, want := range expr.Values() {
+			if want, _ := want.(*types.AttributeValueMemberS); stored == nil || want == nil || stored.Value != want.Value {
+				return fmt.Errorf("%w: conditional put %d", repository.ErrConditionFailed, i)
+			}
+		}
+	}
+	for i, u := range tx.Updates {
+		if _, ok := f.items[u.PK][u.SK]; u.Condition != nil && !ok {
+			return fmt.Errorf("%w: update %d", repository.ErrConditionFailed, i)
+		}
+	}
+	for i, check := range tx.Checks {
+		if _, ok := f.items[check.PK][check.SK]; !ok {
+			return fmt.Errorf("%w: check %d", repository.ErrConditionFailed, i)
+		}
+	}
+	for i, item := range tx.Creates {
+		if _, ok := f.items[item.GetPK()][item.GetSK()]; ok {
+			return fmt.Errorf("%w: create %d", repository.ErrConditionFailed, i)
+		}
+	}
+
+	for _, item := range append(tx.Puts, tx.Creates...) {
+		if err := f.put(item); err != nil {
+			return err
+		}
+	}
+	for _, put := range tx.ConditionalPuts {
+		if err := f.put(put.Item); err != nil {
+			return err
+		}
+	}
+	for _, key := range tx.Deletes {
+		delete(f.items[key["PK"]], key["SK"])
+	}
+	for _, u := range tx.Updates {
+		expr, err := expression.NewBuilder().WithUpdate(u.Update).Build()
+		if err != nil {
+			return err
+		}
+		if assignments, ok := strings.CutPrefix(strings.TrimSpace(*expr.Update()), "SET "); ok {
+			for _, assignment := range strings.Split(assignments, ", ") {
+				name, value, _ := strings.Cut(assignment, " = ")
+				f.items[u.PK][u.SK][expr.Names()[name]] = expr.Values()[value]
+			}
+			continue
+		}
+		clause := strings.Fields(*expr.Update())
+		if len(clause) != 3 || clause[0] != "ADD" {
+			return fmt.Errorf("fake TransactWrite can't apply %q", *expr.Update())
+		}
+		delta, _ := strconv.ParseInt(expr.Values()[clause[2]].(*types.AttributeValueMemberN).Value, 10, 64)
+		if _, err := f.Increment(ctx, u.PK, u.SK, expr.Names()[clause[1]], delta, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Increment adds amount to a numeric attribute (missing = 0); bounds are ignored
+func (f *fakeRepo) Increment(ctx context.Context, pk, sk, attribute string, amount int64, bounds *repository.IncrementBounds) (int64, error) {
+	item, ok := f.items[pk][sk]
+	if !ok {
+		return 0, repository.ErrNotFound
+	}
+	var current int64
+	if n, ok := item[attribute].(*types.AttributeValueMemberN); ok {
+		current, _ = strconv.ParseInt(n.Value, 10, 64)
+	}
+	item[attribute] = &types.AttributeValueMemberN{Value: strconv.FormatInt(current+amount, 10)}
+	return current + amount, nil
+}
+
+// BatchGet returns found items in reverse key order, since real BatchGetItem results
+// are unordered, and rejects duplicate keys the way DynamoDB does
+func (f *fakeRepo) BatchGet(ctx context.Context, keys []map[string]string, resultSlice interface{}) error {
+	seen := make(map[string]bool, len(keys))
+	var items []map[string]types.AttributeValue
+	for i := len(keys) - 1; i >= 0; i-- {
+		pk, sk := keys[i]["PK"], keys[i]["SK"]
+		if seen[pk+"|"+sk] {
+			return errors.New("provided list of item keys contains duplicates")
+		}
+		seen[pk+"|"+sk] = true
+		if item, ok := f.items[pk][sk]; ok {
+			items = append(items, item)
+		}
+	}
+	return attributevalue.UnmarshalListOfMaps(items, resultSlice)
+}
+
+func (f *fakeRepo) BatchWrite(ctx context.Context, puts []repository.BaseModel, deletes []map[string]string) error {
+	var failed []map[string]string
+	for _, item := range puts {
+		if f.failBatchPut != nil && f.failBatchPut(item) {
+			failed = append(failed, map[string]string{"PK": item.GetPK(), "SK": item.GetSK()})
+			continue
+		}
+		if err := f.put(item); err != nil {
+			return err
+		}
+	}
+	for _, key := range deletes {
+		delete(f.items[key["PK"]], key["SK"])
+	}
+	if len(failed) > 0 {
+		return &repository.BatchWriteError{Keys: failed}
+	}
+	return nil
+}
+
+// fakeCache is an in-memory Cache; TTLs are recorded but never expire
+type fakeCache struct {
+	values map[string][]byte
+	ttls   map[string]time.Duration // TTL of the last Set per key
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{values: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (c *fakeCache) Get(ctx context.Context, key string) ([]byte, error) {
+	val, ok := c.values[key]
+	if !ok {
+		return nil, repository.ErrCacheMiss
+	}
+	return val, nil
+}
+
+func (c *fakeCache) GetMany(ctx context.Context, keys []string) (map[string][]byte, []string, error) {
+	found := make(map[string][]byte, len(keys))
+	var misses []string
+	for _, key := range keys {
+		if val, ok := c.values[key]; ok {
+			found[key] = val
+		} else {
+			misses = append(misses, key)
+		}
+	}
+	return found, misses, nil
+}
+
+func (c *fakeCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.values[key] = value
+	c.ttls[key] = ttl
+	return nil
+}
+
+func (c *fakeCache) Del(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(c.values, key)
+	}
+	return nil
+}
+
+func (c *fakeCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	if _, ok := c.values[key]; ok {
+		c.ttls[key] = ttl
+	}
+	return nil
+}
+
+func (c *fakeCache) DelPattern(ctx context.Context, pattern string) (int, error) {
+	deleted := 0
+	for key := range c.values {
+		if ok, _ := path.Match(pattern, key); ok {
+			delete(c.values, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// newTestService wires the service to the fake repo and an empty in-memory cache
+func newTestService(repo *fakeRepo) *AppServiceWithCache {
+	return NewAppServiceWithCache(repo, newFakeCache())
+}
+
+func TestGetUser_CacheHit(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	cache := newFakeCache()
+	svc := NewAppServiceWithCache(repo, cache)
+
+	// Cached copy differs from the DB so we can tell which one was served
+	cache.values["user:u1"] = []byte(`{"id":"u1","email":"cached@example.com"}`)
+	if err := repo.put(models.NewUser("u1", "db@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := svc.GetUser(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if user.Email != "cached@example.com" {
+		t.Errorf("Email = %q, want cached value", user.Email)
+	}
+	if repo.gets != 0 {
+		t.Errorf("repo Get called %d times on a cache hit, want 0", repo.gets)
+	}
+}
+
+func TestGetUser_CacheMiss(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	cache := newFakeCache()
+	svc := NewAppServiceWithCache(repo, cache)
+
+	if err := repo.put(models.NewUser("u1", "db@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := svc.GetUser(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if user.Email != "db@example.com" {
+		t.Errorf("Email = %q, want DB value", user.Email)
+	}
+	if repo.gets != 1 {
+		t.Errorf("repo Get called %d times on a miss, want 1", repo.gets)
+	}
+	if _, ok := cache.values["user:u1"]; !ok {
+		t.Error("user was not cached after a miss")
+	}
+
+	// Second read is served from cache
+	if _, err := svc.GetUser(ctx, "u1"); err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if repo.gets != 1 {
+		t.Errorf("repo Get called %d times after re-read, want 1", repo.gets)
+	}
+}
+
+func TestCacheOnly_MissDoesNotReadTable(t *testing.T) {
+	ctx := WithCacheOnly(context.Background())
+	repo := newFakeRepo()
+	cache := newFakeCache()
+	svc := NewAppServiceWithCache(repo, cache)
+	if err := repo.put(models.NewUser("u1", "db@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.GetUser(ctx, "u1"); !errors.Is(err, ErrNotCached) {
+		t.Fatalf("GetUser miss: err = %v, want ErrNotCached", err)
+	}
+	if repo.gets != 0 {
+		t.Errorf("cache-only miss read DynamoDB %d times", repo.gets)
+	}
+
+	cache.values["user:u1"] = []byte(`{"id":"u1","email":"cached@example.com"}`)
+	user, err := svc.GetUser(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetUser hit: %v", err)
+	}
+	if user.Email != "cached@example.com" {
+		t.Errorf("Email = %q, want cached value", user.Email)
+	}
+}
+
+func TestUpdates_RejectReservedAttributes(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+
+	// fakeRepo has no Update, so reaching the repository would panic
+	for _, field := range []string{"PK", "pk", "SK", "GSI1PK", "GSI1SK", "EntityType", "CreatedAt"} {
+		updates := map[string]interface{}{"FirstName": "Eve", field: "USER#attacker"}
+
+		if _, err := svc.UpdateUser(ctx, "u1", updates); !errors.Is(err, validation.ErrValidation) {
+			t.Errorf("UpdateUser with %s: err = %v, want ErrValidation", field, err)
+		}
+		if _, err := svc.UpdateContact(ctx, "u1", "c1", updates); !errors.Is(err, validation.ErrValidation) {
+			t.Errorf("UpdateContact with %s: err = %v, want ErrValidation", field, err)
+		}
+		if _, err := svc.BulkUpdateContacts(ctx, "u1", []string{"c1"}, updates, false); !errors.Is(err, validation.ErrValidation) {
+			t.Errorf("BulkUpdateContacts with %s: err = %v, want ErrValidation", field, err)
+		}
+	}
+
+	if _, ok := repo.items["USER#u1"]["METADATA"]; !ok || len(repo.items["USER#attacker"]) != 0 {
+		t.Error("a rejected update still re-keyed the user")
+	}
+}
+
+func TestBulkUpdateContacts_AtomicLimit(t *testing.T) {
+	svc := newTestService(newFakeRepo())
+	svc.SetMaxTransactionItems(2)
+
+	// fakeRepo has no TransactUpdate, so reaching the repository would panic
+	_, err := svc.BulkUpdateContacts(context.Background(), "u1", []string{"c1", "c2", "c3"}, map[string]interface{}{"Company": "Acme"}, true)
+	var tooLarge *repository.TransactionTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Items != 3 || tooLarge.Limit != 2 {
+		t.Errorf("atomic batch over the limit: err = %v, want TransactionTooLargeError{3, 2}", err)
+	}
+}
+
+func TestRenameAndRemoveTag(t *testing.T) {
+	ctx := context.Background()
+	repo := &pagedRepo{newFakeRepo()} // Each contact on its own page
+	cache := newFakeCache()
+	svc := NewAppServiceWithCache(repo, cache)
+	svc.SetMaxTransactionItems(2)
+
+	for id, tags := range map[string][]string{"c1": {"vip", "lead"}, "c2": {"lead", "client"}, "c3": {"other"}} {
+		contact := models.NewContact(id, "u1", id, id+"@example.com", "", "", "", "", "", id == "c1")
+		contact.Tags = tags
+		if err := repo.put(contact); err != nil {
+			t.Fatal(err)
+		}
+		if contact.IsFavorite {
+			if err := repo.put(models.NewFavoriteContactIndex(contact)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if _, err := svc.ListUserContacts(ctx, "u1"); err != nil {
+		t.Fatalf("ListUserContacts: %v", err)
+	}
+
+	tagsOf := func(sk string) []string {
+		contact := &models.ContactEntity{}
+		if err := repo.Get(ctx, "USER#u1", sk, contact); err != nil {
+			t.Fatalf("Get %s: %v", sk, err)
+		}
+		return contact.Tags
+	}
+
+	// c1 is a favorite, so it and its FAV# copy fill a two-item transaction on their own
+	result, err := svc.RenameTag(ctx, "u1", "lead", "client")
+	if err != nil {
+		t.Fatalf("RenameTag: %v", err)
+	}
+	if result.Modified != 2 || result.Failed != 0 {
+		t.Errorf("RenameTag result = %+v, want 2 modified", result)
+	}
+	for sk, want := range map[string][]string{"CONTACT#c1": {"vip", "client"}, "FAV#c1": {"vip", "client"}, "CONTACT#c2": {"client"}, "CONTACT#c3": {"other"}} {
+		if got := tagsOf(sk); !slices.Equal(got, want) {
+			t.Errorf("%s tags = %v, want %v", sk, got, want)
+		}
+	}
+	if _, ok := cache.values["contacts:all:user:u1"]; ok {
+		t.Error("contact list cache was not invalidated")
+	}
+
+	result, err = svc.RemoveTag(ctx, "u1", "vip")
+	if err != nil {
+		t.Fatalf("RemoveTag: %v", err)
+	}
+	if result.Modified != 1 {
+		t.Errorf("RemoveTag result = %+v, want 1 modified", result)
+	}
+	if got := tagsOf("CONTACT#c1"); !slices.Equal(got, []string{"client"}) {
+		t.Errorf("c1 tags = %v, want [client]", got)
+	}
+
+	if _, err := svc.RenameTag(ctx, "u1", "client", "client"); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("rename to the same tag: err = %v, want ErrInvalidTag", err)
+	}
+}
+
+// listIndexRepo serves a user's contacts from a projecting list index, one per page
+type listIndexRepo struct {
+	*fakeRepo
+	indexes []string
+}
+
+func (r *listIndexRepo) QueryIndex(ctx context.Context, indexName string, keyCond expression.KeyConditionBuilder, opts repository.QueryOptions, resultSlice interface{}) (map[string]types.AttributeValue, error) {
+	r.indexes = append(r.indexes, indexName)
+	items, _ := r.QueryItems(ctx, "USER#u1", "CONTACT#")
+	for len(items) > 0 && opts.StartKey != nil && items[0]["SK"].(*types.AttributeValueMemberS).Value <= opts.StartKey["SK"].(*types.AttributeValueMemberS).Value {
+		items = items[1:]
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+	projected := map[string]types.AttributeValue{"PK": items[0]["PK"], "SK": items[0]["SK"], "ID": items[0]["ID"], "Name": items[0]["Name"]}
+	var next map[string]types.AttributeValue
+	if len(items) > 1 {
+		next = map[string]types.AttributeValue{"PK": projected["PK"], "SK": projected["SK"]}
+	}
+	return next, attributevalue.UnmarshalListOfMaps([]map[string]types.AttributeValue{projected}, resultSlice)
+}
+
+func TestListUserContacts_ListIndex(t *testing.T) {
+	ctx := context.Background()
+	repo := &listIndexRepo{fakeRepo: newFakeRepo()}
+	for _, id := range []string{"c1", "c2"} {
+		if err := repo.put(models.NewContact(id, "u1", "Name "+id, id+"@example.com", "", "Acme", "", "", "", false)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cache := newFakeCache()
+	svc := NewAppServiceWithCache(repo, cache)
+	svc.SetContactListIndex("ContactListIndex")
+
+	contacts, err := svc.ListUserContacts(ctx, "u1")
+	if err != nil {
+		t.Fatalf("ListUserContacts: %v", err)
+	}
+	if len(contacts) != 2 || contacts[0].Name != "Name c1" || contacts[1].ID != "c2" {
+		t.Fatalf("contacts = %+v, want both pages of the index", contacts)
+	}
+	if contacts[0].Company != "" {
+		t.Errorf("Company = %q, want only the projected attributes", contacts[0].Company)
+	}
+	if want := []string{"ContactListIndex", "ContactListIndex"}; !slices.Equal(repo.indexes, want) {
+		t.Errorf("queried %v, want %v", repo.indexes, want)
+	}
+
+	// Projected contacts are cached apart from the full list
+	if _, ok := cache.values[userContactListKey(contactViewProjected, "u1")]; !ok {
+		t.Error("projected list not cached")
+	}
+	if _, ok := cache.values[userContactListKey(contactViewAll, "u1")]; ok {
+		t.Error("projected contacts cached as the full list")
+	}
+	svc.SetContactListIndex("")
+	full, err := svc.ListUserContacts(ctx, "u1")
+	if err != nil {
+		t.Fatalf("ListUserContacts without the index: %v", err)
+	}
+	if len(full) != 2 || full[0].Company != "Acme" {
+		t.Errorf("contacts = %+v, want full items once the index is off", full)
+	}
+}
+
+func TestSetCacheTTLs(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	cache := newFakeCache()
+	svc := NewAppServiceWithCache(repo, cache)
+	svc.SetCacheTTLs(CacheTTLs{User: time.Minute, List: 10 * time.Minute}) // Dashboard keeps its default
+
+	if err := repo.put(models.NewUser("u1", "db@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.GetUser(ctx, "u1"); err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if _, err := svc.ListUserContacts(ctx, "u1"); err != nil {
+		t.Fatalf("ListUserContacts: %v", err)
+	}
+	if _, err := svc.GetUserDashboard(ctx, "u1"); err != nil {
+		t.Fatalf("GetUserDashboard: %v", err)
+	}
+
+	for key, want := range map[string]time.Duration{
+		"user:u1":              time.Minute,
+		"contacts:all:user:u1": 10 * time.Minute,
+		"dashboard:u1":         DefaultCacheTTLs.Dashboard,
+	} {
+		if got, ok := cache.ttls[key]; !ok {
+			t.Errorf("%s was not cached", key)
+		} else if got != want {
+			t.Errorf("TTL of %s = %s, want %s", key, got, want)
+		}
+	}
+}
+
+func TestSlidingExpiry(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	cache := newFakeCache()
+	svc := NewAppServiceWithCache(repo, cache)
+	svc.SetCacheTTLs(CacheTTLs{User: time.Minute})
+
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.GetUser(ctx, "u1"); err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+
+	// Simulate the entry being part-way through its TTL
+	cache.ttls["user:u1"] = time.Second
+	if _, err := svc.GetUser(ctx, "u1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := cache.ttls["user:u1"]; got != time.Second {
+		t.Errorf("TTL after hit with sliding expiry off = %s, want it untouched", got)
+	}
+
+	svc.SetSlidingExpiry(true)
+	if _, err := svc.GetUser(ctx, "u1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := cache.ttls["user:u1"]; got != time.Minute {
+		t.Errorf("TTL after hit = %s, want it reset to %s", got, time.Minute)
+	}
+	if repo.gets != 1 {
+		t.Errorf("repo gets = %d, want only the first miss to read DynamoDB", repo.gets)
+	}
+
+	if err := svc.TouchCache(ctx, "user:missing"); err != nil {
+		t.Errorf("TouchCache on a missing key: %v", err)
+	}
+	if _, ok := cache.values["user:missing"]; ok {
+		t.Error("TouchCache created a missing key")
+	}
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	ctx := context.Background()
+	fixed := clock.NewFixed(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	t.Cleanup(clock.Set(fixed))
+
+	repo := newFakeRepo()
+	cache := newFakeCache()
+	svc := NewAppServiceWithCache(repo, cache)
+	svc.SetCacheTTLs(CacheTTLs{User: time.Minute, List: time.Minute})
+	svc.SetStaleWhileRevalidate(10 * time.Minute)
+
+	if err := repo.put(models.NewUser("u1", "old@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.GetUser(ctx, "u1"); err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if got := cache.ttls["user:u1"]; got != 11*time.Minute {
+		t.Errorf("hard TTL of user:u1 = %s, want soft TTL + stale window", got)
+	}
+
+	// Within the soft TTL: a plain hit, no refresh
+	fixed.Advance(30 * time.Second)
+	if _, err := svc.GetUser(ctx, "u1"); err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if repo.gets != 1 {
+		t.Fatalf("repo Get called %d times before the soft TTL, want 1", repo.gets)
+	}
+
+	// Past the soft TTL: the stale copy is served and refreshed behind the caller
+	updated := models.NewUser("u1", "new@example.com", "Ada", "Lovelace")
+	if err := repo.put(updated); err != nil {
+		t.Fatal(err)
+	}
+	fixed.Advance(time.Minute)
+	user, err := svc.GetUser(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if user.Email != "old@example.com" {
+		t.Errorf("Email = %q, want the stale cached value", user.Email)
+	}
+
+	// Joins the in-flight refresh (or runs a no-op if it already finished)
+	<-svc.refreshes.DoChan("user:u1", func() (interface{}, error) { return nil, nil })
+	if repo.gets != 2 {
+		t.Errorf("repo Get called %d times after a stale hit, want 2", repo.gets)
+	}
+
+	user, err = svc.GetUser(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if user.Email != "new@example.com" {
+		t.Errorf("Email = %q, want the refreshed value", user.Email)
+	}
+	if repo.gets != 2 {
+		t.Errorf("repo Get called %d times after the refresh, want 2", repo.gets)
+	}
+}
+
+func TestGetUser_NotFound(t *testing.T) {
+	ctx := context.Background()
+	cache := newFakeCache()
+	svc := NewAppServiceWithCache(newFakeRepo(), cache)
+
+	if _, err := svc.GetUser(ctx, "missing"); err == nil {
+		t.Fatal("GetUser on a missing user returned no error")
+	}
+	if len(cache.values) != 0 {
+		t.Errorf("cache has %d entries after a not-found read, want 0", len(cache.values))
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	cache := newFakeCache()
+	svc := NewAppServiceWithCache(repo, cache)
+
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	cache.values["user:u1"] = []byte(`{"id":"u1"}`)
+	cache.values["users:list"] = []byte(`[{"id":"u1"}]`)
+
+	if err := svc.DeleteUser(ctx, "u1"); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+
+	if _, ok := repo.items["USER#u1"]["METADATA"]; ok {
+		t.Error("user still in repo after delete")
+	}
+	for _, key := range []string{"user:u1", "users:list"} {
+		if _, ok := cache.values[key]; ok {
+			t.Errorf("cache key %q not invalidated", key)
+		}
+	}
+}
+
+func TestDeleteUser_BlockedWhileContactsExist(t *testing.T) {
+	ctx := context.Background()
+	repo := &pagedRepo{newFakeRepo()}
+	svc := NewAppServiceWithCache(repo, newFakeCache())
+
+	user, err := svc.CreateUser(ctx, "ada@example.com", "Ada", "Lovelace")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	for _, name := range []string{"Charles", "Mary"} {
+		if _, err := svc.CreateContact(ctx, CreateContactInput{UserID: user.ID, ContactInput: ContactInput{Name: name}}); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+	}
+
+	err = svc.DeleteUser(ctx, user.ID)
+
+	var hasContacts *UserHasContactsError
+	if !errors.As(err, &hasContacts) {
+		t.Fatalf("DeleteUser error = %v, want *UserHasContactsError", err)
+	}
+	if hasContacts.ContactCount != 2 {
+		t.Errorf("ContactCount = %d, want 2", hasContacts.ContactCount)
+	}
+
+	pk := "USER#" + user.ID
+	if _, ok := repo.items[pk]["METADATA"]; !ok {
+		t.Error("user METADATA was deleted despite remaining contacts")
+	}
+	if got := len(repo.items[pk]) - 1; got != 2 {
+		t.Errorf("contacts remaining = %d, want 2", got)
+	}
+}
+
+func TestDeleteUser_NoContacts(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+
+	user, err := svc.CreateUser(ctx, "grace@example.com", "Grace", "Hopper")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	if err := svc.DeleteUser(ctx, user.ID); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if _, ok := repo.items["USER#"+user.ID]["METADATA"]; ok {
+		t.Error("user METADATA still present after delete")
+	}
+}
+
+func TestInvalidateUserContactCaches_ClearsEveryView(t *testing.T) {
+	ctx := context.Background()
+	cache := newFakeCache()
+	svc := NewAppServiceWithCache(newFakeRepo(), cache)
+
+	cache.values[userContactListKey(contactViewAll, "u1")] = []byte(`[]`)
+	cache.values[userContactListKey(contactViewFavorites, "u1")] = []byte(`[]`)
+	cache.values[userContactListKey("by-tag", "u1")] = []byte(`[]`)
+	cache.values[userContactListKey(contactViewAll, "u2")] = []byte(`[]`)
+	cache.values["contact:u1:c1"] = []byte(`{}`)
+
+	if err := svc.invalidateUserContactCaches(ctx, "u1"); err != nil {
+		t.Fatalf("invalidateUserContactCaches: %v", err)
+	}
+
+	for _, view := range []string{contactViewAll, contactViewFavorites, "by-tag"} {
+		if _, ok := cache.values[userContactListKey(view, "u1")]; ok {
+			t.Errorf("%s view for u1 still cached", view)
+		}
+	}
+	if _, ok := cache.values[userContactListKey(contactViewAll, "u2")]; !ok {
+		t.Error("another user's list was invalidated")
+	}
+	if _, ok := cache.values["contact:u1:c1"]; !ok {
+		t.Error("individual contact cache was invalidated")
+	}
+}
+
+func TestRepairUserListCache(t *testing.T) {
+	ctx := context.Background()
+	listed := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		fetched      time.Time
+		wantListKept bool
+	}{
+		{"list is stale", listed.Add(time.Minute), false},
+		{"list is current", listed, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newFakeCache()
+			svc := NewAppServiceWithCache(newFakeRepo(), cache)
+
+			entry := models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")
+			entry.UpdatedAt = listed
+			data, err := json.Marshal([]*models.UserEntity{entry})
+			if err != nil {
+				t.Fatal(err)
+			}
+			cache.values["users:list"] = data
+
+			fetched := *entry
+			fetched.UpdatedAt = tt.fetched
+			if err := svc.repairUserListCache(ctx, &fetched); err != nil {
+				t.Fatalf("repairUserListCache: %v", err)
+			}
+
+			if _, ok := cache.values["users:list"]; ok != tt.wantListKept {
+				t.Errorf("users:list cached = %v, want %v", ok, tt.wantListKept)
+			}
+		})
+	}
+}
+
+func TestCreateContactWithID_Idempotent(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	const id = "0b5c7a52-8f0e-4f6e-9c1a-3d2b8e4f6a10"
+
+	// No user yet: nothing is written
+	_, _, err := svc.CreateContactWithID(ctx, id, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Charles"}})
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("create for a missing user: err = %v, want ErrNotFound", err)
+	}
+	if len(repo.items["USER#u1"]) != 0 {
+		t.Fatalf("create for a missing user wrote %d items", len(repo.items["USER#u1"]))
+	}
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+
+	first, created, err := svc.CreateContactWithID(ctx, id, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Charles", Email: "charles@example.com", IsFavorite: true}})
+	if err != nil {
+		t.Fatalf("first create: %v", err)
+	}
+	if !created || first.ID != id {
+		t.Fatalf("first create = (%s, created=%v), want (%s, true)", first.ID, created, id)
+	}
+
+	// A webhook retry with the same ID (and even different fields) gets the stored contact back
+	delete(repo.items["USER#u1"], "FAV#"+id) // as if the first attempt died before indexing
+	again, created, err := svc.CreateContactWithID(ctx, id, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Charlie", Email: "charles@example.com", IsFavorite: true}})
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if created {
+		t.Error("retry reported created=true")
+	}
+	if again.Name != "Charles" {
+		t.Errorf("retry returned name %q, want the stored %q", again.Name, "Charles")
+	}
+
+	contacts, _ := repo.QueryItems(ctx, "USER#u1", "CONTACT#")
+	if len(contacts) != 1 {
+		t.Errorf("%d contacts stored, want 1", len(contacts))
+	}
+	if _, ok := repo.items["USER#u1"]["FAV#"+id]; !ok {
+		t.Error("retry did not repair the missing FAV# index item")
+	}
+	var user models.UserEntity
+	if err := attributevalue.UnmarshalMap(repo.items["USER#u1"]["METADATA"], &user); err != nil {
+		t.Fatal(err)
+	}
+	if user.ContactCount != 1 {
+		t.Errorf("ContactCount = %d, want 1 (counted once, not for the retry)", user.ContactCount)
+	}
+}
+
+func TestListUserContactsPage(t *testing.T) {
+	ctx := context.Background()
+	pagination.SetSecret("test-secret")
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Ada", "Grace", "Linus"} {
+		if _, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: name}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var names []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("pagination did not terminate")
+		}
+		contacts, next, err := svc.ListUserContactsPage(ctx, "u1", false, 2, cursor)
+		if err != nil {
+			t.Fatalf("page %d: %v", pages, err)
+		}
+		if len(contacts) > 2 {
+			t.Fatalf("page %d has %d contacts, want at most 2", pages, len(contacts))
+		}
+		for _, c := range contacts {
+			names = append(names, c.Name)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if len(names) != 3 {
+		t.Errorf("paged through %v, want all 3 contacts", names)
+	}
+
+	// A cursor from u1's listing can't be replayed against another user
+	_, next, _ := svc.ListUserContactsPage(ctx, "u1", false, 1, "")
+	if _, _, err := svc.ListUserContactsPage(ctx, "u2", false, 1, next); !errors.Is(err, pagination.ErrInvalidCursor) {
+		t.Errorf("foreign cursor: err = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestDeleteContact_TrashAndRestore(t *testing.T) {
+	ctx := context.Background()
+	fixed := clock.NewFixed(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	t.Cleanup(clock.Set(fixed))
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+
+	contact, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Ada", IsFavorite: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteContact(ctx, "u1", contact.ID); err != nil {
+		t.Fatalf("DeleteContact: %v", err)
+	}
+	for _, sk := range []string{"CONTACT#", "FAV#"} {
+		if _, ok := repo.items["USER#u1"][sk+contact.ID]; ok {
+			t.Errorf("%s item still present after delete", sk)
+		}
+	}
+
+	trash, err := svc.ListDeletedContacts(ctx, "u1")
+	if err != nil {
+		t.Fatalf("ListDeletedContacts: %v", err)
+	}
+	if len(trash) != 1 || trash[0].ID != contact.ID || trash[0].DeletedAt == nil || !trash[0].DeletedAt.Equal(fixed.Now()) {
+		t.Fatalf("trash = %+v, want the deleted contact stamped with DeletedAt", trash)
+	}
+
+	restored, err := svc.RestoreContact(ctx, "u1", contact.ID)
+	if err != nil {
+		t.Fatalf("RestoreContact: %v", err)
+	}
+	if restored.DeletedAt != nil || restored.Name != "Ada" {
+		t.Errorf("restored = %+v", restored)
+	}
+	for _, sk := range []string{"CONTACT#", "FAV#"} {
+		if _, ok := repo.items["USER#u1"][sk+contact.ID]; !ok {
+			t.Errorf("%s item missing after restore", sk)
+		}
+	}
+	if _, ok := repo.items["USER#u1"]["TRASH#"+contact.ID]; ok {
+		t.Error("trash item left behind after restore")
+	}
+
+	// Past the retention the contact can only be purged
+	if err := svc.DeleteContact(ctx, "u1", contact.ID); err != nil {
+		t.Fatal(err)
+	}
+	fixed.Advance(DefaultTrashRetention + time.Hour)
+	if _, err := svc.RestoreContact(ctx, "u1", contact.ID); !errors.Is(err, ErrRetentionExpired) {
+		t.Errorf("restore after retention: err = %v, want ErrRetentionExpired", err)
+	}
+}
+
+// fakeLocker grants the lock unless held is set, and counts releases
+type fakeLocker struct {
+	held     bool
+	released int
+}
+
+func (l *fakeLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, bool, error) {
+	if l.held {
+		return nil, false, nil
+	}
+	return func(context.Context) error { l.released++; return nil }, true, nil
+}
+
+func TestContactCount_TransactionalWrites(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+
+	count := func() int {
+		var user models.UserEntity
+		if err := attributevalue.UnmarshalMap(repo.items["USER#u1"]["METADATA"], &user); err != nil {
+			t.Fatal(err)
+		}
+		return user.ContactCount
+	}
+
+	contact, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Charles", IsFavorite: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Grace"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := count(); got != 2 {
+		t.Errorf("after two creates ContactCount = %d, want 2", got)
+	}
+	if err := svc.DeleteContact(ctx, "u1", contact.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := count(); got != 1 {
+		t.Errorf("after delete ContactCount = %d, want 1", got)
+	}
+	if _, err := svc.RestoreContact(ctx, "u1", contact.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := count(); got != 2 {
+		t.Errorf("after restore ContactCount = %d, want 2", got)
+	}
+
+	// The counter condition fails for a missing user, so the contact isn't written either
+	if _, err := svc.CreateContact(ctx, CreateContactInput{UserID: "ghost", ContactInput: ContactInput{Name: "Alan", IsFavorite: true}}); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("CreateContact for missing user err = %v, want ErrNotFound", err)
+	}
+	if n := len(repo.items["USER#ghost"]); n != 0 {
+		t.Errorf("missing user's partition has %d items, want the transaction rolled back", n)
+	}
+}
+
+func TestContactEmailDomainAllowlist(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	svc.SetContactEmailDomains([]string{"example.com"})
+
+	restricted := models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")
+	restricted.AllowedEmailDomains = []string{"acme.io"}
+	for _, user := range []*models.UserEntity{restricted, models.NewUser("u2", "grace@example.com", "Grace", "Hopper")} {
+		if err := repo.put(user); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		userID  string
+		email   string
+		wantErr bool
+	}{
+		{"user list allows", "u1", "charles@ACME.io", false},
+		{"user list overrides global", "u1", "charles@example.com", true},
+		{"global fallback allows", "u2", "alan@example.com", false},
+		{"global fallback rejects", "u2", "alan@acme.io", true},
+		{"no email", "u1", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateContact(ctx, CreateContactInput{UserID: tt.userID, ContactInput: ContactInput{Name: "Charles", Email: tt.email}})
+			if tt.wantErr != errors.Is(err, validation.ErrValidation) {
+				t.Errorf("CreateContact(%q) err = %v, want validation error: %t", tt.email, err, tt.wantErr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("CreateContact: %v", err)
+			}
+		})
+	}
+
+	contact, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u2", ContactInput: ContactInput{Name: "Alan", Email: "alan@example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.UpdateContact(ctx, "u2", contact.ID, map[string]interface{}{"Email": "alan@elsewhere.org"}); !errors.Is(err, validation.ErrValidation) {
+		t.Errorf("UpdateContact to a disallowed domain err = %v, want validation error", err)
+	}
+
+	result, err := svc.ImportContacts(ctx, "u1", []ContactInput{{Name: "Ok", Email: "ok@acme.io"}, {Name: "No", Email: "no@example.com"}}, true, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Imported) != 1 || len(result.Rejected) != 1 || result.Rejected[0].Index != 1 {
+		t.Errorf("import imported %d, rejected %+v; want row 1 rejected", len(result.Imported), result.Rejected)
+	}
+}
+
+func TestImportContacts_OnConflict(t *testing.T) {
+	ctx := context.Background()
+	seed := func(t *testing.T) (*AppServiceWithCache, *fakeRepo, *models.ContactEntity) {
+		repo := newFakeRepo()
+		svc := newTestService(repo)
+		if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+			t.Fatal(err)
+		}
+		existing, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{
+			Name: "Ada", Email: "ada@example.com", Company: "Analytical Engines", Tags: []string{"math"}, IsFavorite: true,
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return svc, repo, existing
+	}
+	rows := []ContactInput{
+		{Name: "Ada Lovelace", Email: "ada@example.com", Phone: "555-0100", Tags: []string{"math", "poetry"}},
+		{Name: "Grace", Email: "grace@example.com"},
+		{Name: "Ada again", Email: "ada@example.com"},
+	}
+	stored := func(t *testing.T, repo *fakeRepo, id string) *models.ContactEntity {
+		contact := &models.ContactEntity{}
+		if err := repo.Get(ctx, "USER#u1", "CONTACT#"+id, contact); err != nil {
+			t.Fatal(err)
+		}
+		return contact
+	}
+	actions := func(result *ImportResult) []string {
+		var got []string
+		for _, item := range result.Items {
+			got = append(got, item.Action)
+		}
+		return got
+	}
+
+	t.Run("skip by default", func(t *testing.T) {
+		svc, repo, existing := seed(t)
+		result, err := svc.ImportContacts(ctx, "u1", rows, false, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := actions(result); !slices.Equal(got, []string{"skipped", "created", "rejected"}) {
+			t.Errorf("actions = %v", got)
+		}
+		if result.Items[0].ContactID != existing.ID || len(result.Rejected) != 2 {
+			t.Errorf("items = %+v, rejected = %+v; want row 0 matched to %s and rows 0, 2 rejected", result.Items, result.Rejected, existing.ID)
+		}
+		if got := stored(t, repo, existing.ID); got.Name != "Ada" || got.Phone != "" {
+			t.Errorf("skipped contact was changed: %+v", got)
+		}
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		svc, repo, existing := seed(t)
+		result, err := svc.ImportContacts(ctx, "u1", rows, false, ImportConflictOverwrite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := actions(result); !slices.Equal(got, []string{"overwritten", "created", "rejected"}) {
+			t.Errorf("actions = %v", got)
+		}
+		got := stored(t, repo, existing.ID)
+		if got.Name != "Ada Lovelace" || got.Company != "" || got.IsFavorite || !got.CreatedAt.Equal(existing.CreatedAt) {
+			t.Errorf("overwritten contact = %+v, want the row's fields and the original CreatedAt", got)
+		}
+		if _, ok := repo.items["USER#u1"]["FAV#"+existing.ID]; ok {
+			t.Error("favorite index kept for a contact overwritten as not favorite")
+		}
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		svc, repo, existing := seed(t)
+		result, err := svc.ImportContacts(ctx, "u1", rows[:1], false, ImportConflictMerge)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := actions(result); !slices.Equal(got, []string{"merged"}) {
+			t.Errorf("actions = %v", got)
+		}
+		got := stored(t, repo, existing.ID)
+		if got.Name != "Ada" || got.Phone != "555-0100" || got.Company != "Analytical Engines" ||
+			!slices.Equal(got.Tags, []string{"math", "poetry"}) || !got.IsFavorite {
+			t.Errorf("merged contact = %+v, want existing fields kept and blanks filled", got)
+		}
+	})
+
+	t.Run("edited during the import", func(t *testing.T) {
+		_, repo, existing := seed(t)
+		racing := &editAfterQuery{fakeRepo: repo, edit: func() {
+			repo.items["USER#u1"]["CONTACT#"+existing.ID]["UpdatedAt"] = &types.AttributeValueMemberS{Value: "2099-01-01T00:00:00Z"}
+		}}
+		svc := NewAppServiceWithCache(racing, newFakeCache())
+
+		result, err := svc.ImportContacts(ctx, "u1", rows[:2], false, ImportConflictOverwrite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := actions(result); !slices.Equal(got, []string{"rejected", "created"}) {
+			t.Errorf("actions = %v", got)
+		}
+		if len(result.Imported) != 1 || len(result.Rejected) != 1 || result.Rejected[0].Error != ErrContactModified.Error() {
+			t.Errorf("imported = %d, rejected = %+v; want the edited row rejected as modified", len(result.Imported), result.Rejected)
+		}
+		if got := stored(t, repo, existing.ID); got.Name != "Ada" || !got.IsFavorite {
+			t.Errorf("edited contact was overwritten: %+v", got)
+		}
+	})
+
+	t.Run("matches ignoring case on every page", func(t *testing.T) {
+		_, repo, ada := seed(t)
+		paged := &pagedRepo{repo} // Each contact on its own page
+		svc := NewAppServiceWithCache(paged, newFakeCache())
+		grace, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Grace", Email: "grace@example.com"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := svc.ImportContacts(ctx, "u1", []ContactInput{
+			{Name: "Ada", Email: "ADA@example.com"},
+			{Name: "Grace", Email: "Grace@Example.com"},
+			{Name: "Linus", Email: "linus@example.com"},
+			{Name: "Linus again", Email: "LINUS@example.com"},
+		}, false, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := actions(result); !slices.Equal(got, []string{"skipped", "skipped", "created", "rejected"}) {
+			t.Errorf("actions = %v", got)
+		}
+		if result.Items[0].ContactID != ada.ID || result.Items[1].ContactID != grace.ID {
+			t.Errorf("items = %+v, want rows 0 and 1 matched to %s and %s", result.Items, ada.ID, grace.ID)
+		}
+	})
+
+	t.Run("notifies webhooks", func(t *testing.T) {
+		svc, _, existing := seed(t)
+		events := recordWebhooks(t, svc)
+		result, err := svc.ImportContacts(ctx, "u1", rows, false, ImportConflictOverwrite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{webhook.ContactCreated + " " + result.Items[1].ContactID, webhook.ContactUpdated + " " + existing.ID}
+		slices.Sort(want)
+		if got := events(); !slices.Equal(got, want) {
+			t.Errorf("events = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("unknown strategy", func(t *testing.T) {
+		svc, _, _ := seed(t)
+		if _, err := svc.ImportContacts(ctx, "u1", rows, false, "replace"); !errors.Is(err, ErrInvalidConflictStrategy) {
+			t.Errorf("err = %v, want ErrInvalidConflictStrategy", err)
+		}
+	})
+}
+
+// recordWebhooks sends svc's webhooks to a test endpoint. The returned func waits for
+// every delivery and returns them as sorted "<type> <contact ID>" strings.
+func recordWebhooks(t *testing.T, svc *AppServiceWithCache) func() []string {
+	t.Helper()
+	var mu sync.Mutex
+	var events []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhook.Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err == nil {
+			mu.Lock()
+			events = append(events, event.Type+" "+event.ContactID)
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	dispatcher := webhook.NewDispatcher([]string{srv.URL}, "test-secret")
+	svc.SetWebhooks(dispatcher)
+
+	return func() []string {
+		dispatcher.Wait()
+		mu.Lock()
+		defer mu.Unlock()
+		got := slices.Clone(events)
+		slices.Sort(got)
+		return got
+	}
+}
+
+// editAfterQuery stands in for a concurrent edit: it runs edit right after each
+// QueryWithStats
+type editAfterQuery struct {
+	*fakeRepo
+	edit func()
+}
+
+func (r *editAfterQuery) QueryWithStats(ctx context.Context, pk string, skPrefix string, filter *expression.ConditionBuilder) ([]map[string]types.AttributeValue, repository.QueryStats, error) {
+	items, stats, err := r.fakeRepo.QueryWithStats(ctx, pk, skPrefix, filter)
+	for i, item := range items {
+		items[i] = maps.Clone(item) // What was read, unaffected by the edit
+	}
+	r.edit()
+	return items, stats, err
+}
+
+func TestPurgeExpiredTrash(t *testing.T) {
+	ctx := context.Background()
+	fixed := clock.NewFixed(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	t.Cleanup(clock.Set(fixed))
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	svc.SetTrashRetention(24 * time.Hour)
+
+	// Old is deleted 48h before the purge, Recent 12h before
+	var ids []string
+	for _, step := range []time.Duration{36 * time.Hour, 12 * time.Hour} {
+		contact, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Ada"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.DeleteContact(ctx, "u1", contact.ID); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, contact.ID)
+		fixed.Advance(step)
+	}
+
+	cutoff := fixed.Now().Add(-24 * time.Hour).Format(time.RFC3339Nano)
+	repo.scanMatch = func(item map[string]types.AttributeValue) bool {
+		entityType, _ := item["EntityType"].(*types.AttributeValueMemberS)
+		deletedAt, _ := item["DeletedAt"].(*types.AttributeValueMemberS)
+		return entityType != nil && entityType.Value == "CONTACT_TRASH" && deletedAt != nil && deletedAt.Value < cutoff
+	}
+
+	// Another replica holds the lock: nothing happens
+	locker := &fakeLocker{held: true}
+	svc.purgeTrashOnce(ctx, locker, time.Minute)
+	if _, ok := repo.items["USER#u1"]["TRASH#"+ids[0]]; !ok {
+		t.Fatal("purged without holding the lock")
+	}
+
+	locker.held = false
+	svc.purgeTrashOnce(ctx, locker, time.Minute)
+	if _, ok := repo.items["USER#u1"]["TRASH#"+ids[0]]; ok {
+		t.Error("expired trash item was not purged")
+	}
+	if _, ok := repo.items["USER#u1"]["TRASH#"+ids[1]]; !ok {
+		t.Error("trash item inside the retention was purged")
+	}
+	if locker.released != 1 {
+		t.Errorf("lock released %d times, want 1", locker.released)
+	}
+}
+
+func TestReindex(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+
+	// Legacy items: keys only, no index attributes
+	legacy := func(pk, sk string) {
+		if repo.items[pk] == nil {
+			repo.items[pk] = make(map[string]map[string]types.AttributeValue)
+		}
+		repo.items[pk][sk] = map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: pk},
+			"SK": &types.AttributeValueMemberS{Value: sk},
+		}
+	}
+	legacy("USER#u1", "METADATA")
+	legacy("USER#u1", "CONTACT#c1")
+	legacy("ORDER#o1", "METADATA")
+	if err := repo.put(models.NewUser("u2", "grace@example.com", "Grace", "Hopper")); err != nil {
+		t.Fatal(err)
+	}
+	// Legacy users have no ContactCount, so deletes drive it negative
+	repo.items["USER#u2"]["METADATA"]["ContactCount"] = &types.AttributeValueMemberN{Value: "-2"}
+	// Favorited before the FAV# index existed
+	if err := repo.put(models.NewContact("c2", "u2", "Ada", "ada@example.com", "", "", "", "", "", true)); err != nil {
+		t.Fatal(err)
+	}
+	// Written before user sort keys carried the creation time
+	old := models.NewUser("u3", "alan@example.com", "Alan", "Turing")
+	old.CreatedAt = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	old.GSI1SK = "USER#u3"
+	if err := repo.put(old); err != nil {
+		t.Fatal(err)
+	}
+	// Legacy users have no EMAIL# sentinel; u3's address was since taken by u9
+	if err := repo.put(models.NewUserEmailIndex("Alan@example.com", "u9")); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := svc.Reindex(ctx)
+	if err != nil {
+		t.Fatalf("Reindex: %v", err)
+	}
+	if *result != (ReindexResult{Scanned: 4, Fixed: 3, Skipped: 1, Backfilled: 5}) {
+		t.Errorf("result = %+v, want 4 scanned, 3 fixed, 1 skipped, 5 backfilled", *result)
+	}
+	if _, ok := repo.items["USER#u2"]["FAV#c2"]; !ok {
+		t.Error("favorites index item not backfilled")
+	}
+	for pk, want := range map[string]string{"EMAIL#grace@example.com": "u2", "EMAIL#alan@example.com": "u9"} {
+		var sentinel models.UserEmailIndex
+		if err := attributevalue.UnmarshalMap(repo.items[pk]["METADATA"], &sentinel); err != nil {
+			t.Fatal(err)
+		}
+		if sentinel.UserID != want {
+			t.Errorf("%s held by %q, want %q", pk, sentinel.UserID, want)
+		}
+	}
+	for pk, want := range map[string]int{"USER#u1": 1, "USER#u2": 1, "USER#u3": 0} {
+		var counted models.UserEntity
+		if err := attributevalue.UnmarshalMap(repo.items[pk]["METADATA"], &counted); err != nil {
+			t.Fatal(err)
+		}
+		if counted.ContactCount != want {
+			t.Errorf("%s ContactCount = %d, want %d", pk, counted.ContactCount, want)
+		}
+	}
+
+	var user models.UserEntity
+	if err := attributevalue.UnmarshalMap(repo.items["USER#u1"]["METADATA"], &user); err != nil {
+		t.Fatal(err)
+	}
+	if user.EntityType != "USER" || user.GSI1PK != "USER" || user.GSI1SK != models.UserGSI1SK("u1", time.Time{}) {
+		t.Errorf("user index keys = %s/%s/%s", user.EntityType, user.GSI1PK, user.GSI1SK)
+	}
+	var rekeyed models.UserEntity
+	if err := attributevalue.UnmarshalMap(repo.items["USER#u3"]["METADATA"], &rekeyed); err != nil {
+		t.Fatal(err)
+	}
+	if want := "2024-03-01T12:00:00.000000000Z#USER#u3"; rekeyed.GSI1SK != want {
+		t.Errorf("legacy user GSI1SK = %s, want %s", rekeyed.GSI1SK, want)
+	}
+	var contact models.ContactEntity
+	if err := attributevalue.UnmarshalMap(repo.items["USER#u1"]["CONTACT#c1"], &contact); err != nil {
+		t.Fatal(err)
+	}
+	if contact.EntityType != "CONTACT" || contact.GSI1PK != "CONTACT" || contact.GSI1SK != "CONTACT#c1" {
+		t.Errorf("contact index keys = %s/%s/%s", contact.EntityType, contact.GSI1PK, contact.GSI1SK)
+	}
+
+	// Idempotent: only the unknown item is left to look at
+	again, err := svc.Reindex(ctx)
+	if err != nil {
+		t.Fatalf("second Reindex: %v", err)
+	}
+	if again.Fixed != 0 || again.Skipped != 1 || again.Backfilled != 0 {
+		t.Errorf("second run = %+v, want nothing fixed or backfilled", *again)
+	}
+}
+
+func TestListUsersByCreatedDate(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+
+	t0 := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	fixed := clock.NewFixed(t0)
+	defer clock.Set(fixed)()
+
+	// One user a day: u0 on Mar 1 ... u4 on Mar 5
+	for i := 0; i < 5; i++ {
+		if err := repo.put(models.NewUser(fmt.Sprintf("u%d", i), fmt.Sprintf("u%d@example.com", i), "U", "Ser")); err != nil {
+			t.Fatal(err)
+		}
+		fixed.Advance(24 * time.Hour)
+	}
+
+	// Both ends inclusive, oldest first
+	users, err := svc.ListUsersByCreatedDate(ctx, t0.Add(24*time.Hour), t0.Add(3*24*time.Hour))
+	if err != nil {
+		t.Fatalf("ListUsersByCreatedDate: %v", err)
+	}
+	var ids []string
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	if got := strings.Join(ids, ","); got != "u1,u2,u3" {
+		t.Errorf("users = %s, want u1,u2,u3", got)
+	}
+
+	if _, err := svc.ListUsersByCreatedDate(WithCacheOnly(ctx), t0, t0); !errors.Is(err, ErrNotCached) {
+		t.Errorf("cache-only err = %v, want ErrNotCached", err)
+	}
+}
+
+func TestGetContactsByIDs(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, name := range []string{"Charles", "Grace"} {
+		contact, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: name}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, contact.ID)
+	}
+
+	got, err := svc.GetContactsByIDs(ctx, "u1", []string{ids[0], "missing", ids[1], ids[0]})
+	if err != nil {
+		t.Fatalf("GetContactsByIDs: %v", err)
+	}
+	want := []string{ids[0], "", ids[1], ids[0]}
+	if len(got) != len(want) {
+		t.Fatalf("got %d contacts, want %d", len(got), len(want))
+	}
+	for i, id := range want {
+		switch {
+		case id == "" && got[i] != nil:
+			t.Errorf("contacts[%d] = %s, want nil for a missing ID", i, got[i].ID)
+		case id != "" && (got[i] == nil || got[i].ID != id):
+			t.Errorf("contacts[%d] = %v, want %s", i, got[i], id)
+		}
+	}
+
+	// Another user's contact IDs don't resolve under u2
+	if got, err := svc.GetContactsByIDs(ctx, "u2", ids); err != nil || got[0] != nil || got[1] != nil {
+		t.Errorf("GetContactsByIDs for another user = %v, %v; want only nils", got, err)
+	}
+
+	// Cached contacts are served without DynamoDB; only misses are read and then cached
+	cache := svc.cache.(*fakeCache)
+	if _, err := svc.GetContactsByIDs(WithCacheOnly(ctx), "u1", ids); err != nil {
+		t.Errorf("cache-only with every contact cached: %v", err)
+	}
+	missKey := fmt.Sprintf("contact:u1:%s", ids[1])
+	delete(cache.values, missKey)
+	if _, err := svc.GetContactsByIDs(WithCacheOnly(ctx), "u1", ids); !errors.Is(err, ErrNotCached) {
+		t.Errorf("cache-only with a miss: err = %v, want ErrNotCached", err)
+	}
+	if got, err := svc.GetContactsByIDs(ctx, "u1", ids); err != nil || got[1] == nil || got[1].ID != ids[1] {
+		t.Fatalf("after a cache miss = %v, %v; want the contact from DynamoDB", got, err)
+	}
+	if _, ok := cache.values[missKey]; !ok {
+		t.Error("the missed contact wasn't cached")
+	}
+
+	tooMany := make([]string, MaxContactsByIDs+1)
+	if _, err := svc.GetContactsByIDs(ctx, "u1", tooMany); !errors.Is(err, ErrTooManyIDs) {
+		t.Errorf("err = %v, want ErrTooManyIDs", err)
+	}
+}
+
+func TestCloneContact(t *testing.T) {
+	ctx := context.Background()
+	fixed := clock.NewFixed(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	t.Cleanup(clock.Set(fixed))
+
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	source, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{
+		Name: "Charles", Email: "charles@example.com", JobTitle: "Engineer", Tags: []string{"vip", "lead"}, IsFavorite: true,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixed.Advance(time.Hour)
+	clone, err := svc.CloneContact(ctx, "u1", source.ID)
+	if err != nil {
+		t.Fatalf("CloneContact: %v", err)
+	}
+	if clone.ID == source.ID {
+		t.Error("clone reused the source's ID")
+	}
+	if clone.Name != "Charles (copy)" || clone.Email != source.Email || clone.JobTitle != source.JobTitle {
+		t.Errorf("clone = %q <%s> %q, want a copy of the source with a marked name", clone.Name, clone.Email, clone.JobTitle)
+	}
+	if !slices.Equal(clone.Tags, source.Tags) {
+		t.Errorf("clone tags = %v, want the source's %v", clone.Tags, source.Tags)
+	}
+	if clone.IsFavorite {
+		t.Error("clone kept the favorite flag by default")
+	}
+	if !clone.CreatedAt.Equal(fixed.Now()) {
+		t.Errorf("clone CreatedAt = %s, want its own (%s), not the source's", clone.CreatedAt, fixed.Now())
+	}
+
+	svc.SetCloneKeepsFavorite(true)
+	if clone, err := svc.CloneContact(ctx, "u1", source.ID); err != nil || !clone.IsFavorite {
+		t.Errorf("CloneContact with favorites kept = %v, %v; want a favorite", clone, err)
+	}
+
+	if _, err := svc.CloneContact(ctx, "u1", "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCreateUsers(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	cache := newFakeCache()
+	svc := NewAppServiceWithCache(repo, cache)
+	if err := repo.put(models.NewUser("u1", "Ada@Example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	cache.values["users:list"] = []byte("[]")
+	repo.failBatchPut = func(item repository.BaseModel) bool {
+		user, ok := item.(*models.UserEntity)
+		return ok && user.FirstName == "Throttled"
+	}
+
+	results, err := svc.CreateUsers(ctx, []UserInput{
+		{Email: " Grace@Example.com ", FirstName: "Grace", LastName: "Hopper"},
+		{Email: "ada@example.com", FirstName: "Ada", LastName: "King"},
+		{Email: "grace@example.com", FirstName: "Grace", LastName: "Again"},
+		{Email: "not-an-email", FirstName: "Bad", LastName: "Email"},
+		{Email: "alan@example.com", FirstName: "Throttled", LastName: "Turing"},
+	})
+	if err != nil {
+		t.Fatalf("CreateUsers: %v", err)
+	}
+
+	wantStatus := []string{BulkUserCreated, BulkUserConflict, BulkUserError, BulkUserError, BulkUserError}
+	for i, want := range wantStatus {
+		if results[i].Index != i || results[i].Status != want {
+			t.Errorf("result %d = %+v, want status %s", i, results[i], want)
+		}
+	}
+	if !strings.Contains(results[2].Error, "index 0") {
+		t.Errorf("duplicate error = %q, want it to point at index 0", results[2].Error)
+	}
+
+	created := results[0].User
+	if created == nil || created.Email != "grace@example.com" {
+		t.Fatalf("created user = %+v, want the normalized email", created)
+	}
+	stored := &models.UserEntity{}
+	if err := repo.Get(ctx, created.PK, created.SK, stored); err != nil || stored.UpdatedAt.IsZero() {
+		t.Errorf("stored user = %+v, %v; want it written with timestamps", stored, err)
+	}
+	var users []*models.UserEntity
+	if err := repo.QueryByEntityType(ctx, "USER", &users); err != nil || len(users) != 2 {
+		t.Errorf("users in table = %d, %v; want the existing one and Grace", len(users), err)
+	}
+	if _, ok := cache.values["users:list"]; ok {
+		t.Error("users:list was not invalidated")
+	}
+
+	if _, err := svc.CreateUsers(ctx, make([]UserInput, MaxBulkUsers+1)); !errors.Is(err, ErrTooManyUsers) {
+		t.Errorf("err = %v, want ErrTooManyUsers", err)
+	}
+}
+
+func TestUpdateContactIfUnmodifiedSince(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2024, 3, 1, 12, 0, 0, 250_000_000, time.UTC)
+	fixed := clock.NewFixed(created)
+	t.Cleanup(clock.Set(fixed))
+
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	contact, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Charles"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// HTTP dates drop the fraction, so the header for the same second must still pass
+	updated, err := svc.UpdateContactIfUnmodifiedSince(ctx, "u1", contact.ID, map[string]interface{}{"Name": "Charles B."}, created.Truncate(time.Second))
+	if err != nil {
+		t.Fatalf("update unmodified since its own second: %v", err)
+	}
+	if updated.Name != "Charles B." {
+		t.Errorf("Name = %q, want the update applied", updated.Name)
+	}
+
+	fixed.Advance(time.Minute)
+	if _, err := svc.UpdateContact(ctx, "u1", contact.ID, map[string]interface{}{"Company": "Acme"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = svc.UpdateContactIfUnmodifiedSince(ctx, "u1", contact.ID, map[string]interface{}{"Name": "Stale"}, created)
+	if !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("err = %v, want ErrPreconditionFailed", err)
+	}
+	got, err := svc.GetContact(ctx, "u1", contact.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "Charles B." {
+		t.Errorf("Name = %q, a refused update must not be written", got.Name)
+	}
+
+	if _, err := svc.UpdateUserIfUnmodifiedSince(ctx, "u1", map[string]interface{}{"FirstName": "Augusta"}, fixed.Now()); err != nil {
+		t.Errorf("UpdateUserIfUnmodifiedSince with a later date: %v", err)
+	}
+}
+
+// fakePresigner records the uploads it presigns
+type fakePresigner struct {
+	keys []string
+}
+
+func (p *fakePresigner) PresignPut(ctx context.Context, key, contentType string, size int64) (*repository.PresignedUpload, error) {
+	p.keys = append(p.keys, key)
+	return &repository.PresignedUpload{UploadURL: "https://upload/" + key, Method: "PUT", ObjectURL: "https://objects/" + key}, nil
+}
+
+func TestPresignContactAvatar(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	contact, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Charles"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.PresignContactAvatar(ctx, "u1", contact.ID, "image/png", 100); !errors.Is(err, ErrAvatarUploadsDisabled) {
+		t.Fatalf("err = %v, want ErrAvatarUploadsDisabled", err)
+	}
+
+	presigner := &fakePresigner{}
+	svc.SetAvatarUploads(presigner, 1000)
+	upload, err := svc.PresignContactAvatar(ctx, "u1", contact.ID, "image/png", 1000)
+	if err != nil {
+		t.Fatalf("PresignContactAvatar: %v", err)
+	}
+	if key := presigner.keys[0]; !strings.HasPrefix(key, "avatars/u1/"+contact.ID+"/") || !strings.HasSuffix(key, ".png") {
+		t.Errorf("object key = %s, want it under the contact with a .png extension", key)
+	}
+	got, err := svc.GetContact(ctx, "u1", contact.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AvatarURL != upload.ObjectURL {
+		t.Errorf("AvatarURL = %q, want %q", got.AvatarURL, upload.ObjectURL)
+	}
+
+	for _, tt := range []struct {
+		contentType string
+		size        int64
+	}{
+		{"image/svg+xml", 100}, // scriptable, not accepted
+		{"image/png", 0},
+		{"image/png", 1001},
+	} {
+		if _, err := svc.PresignContactAvatar(ctx, "u1", contact.ID, tt.contentType, tt.size); !errors.Is(err, validation.ErrValidation) {
+			t.Errorf("%s of %d bytes: err = %v, want a validation error", tt.contentType, tt.size, err)
+		}
+	}
+	if _, err := svc.PresignContactAvatar(ctx, "u1", "missing", "image/png", 100); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMoveContact(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+
+	from, err := svc.CreateUser(ctx, "ada@example.com", "Ada", "Lovelace")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	to, err := svc.CreateUser(ctx, "grace@example.com", "Grace", "Hopper")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	contact, err := svc.CreateContact(ctx, CreateContactInput{UserID: from.ID, ContactInput: ContactInput{
+		Name:       "Charles",
+		Email:      "charles@example.com",
+		JobTitle:   "Engineer",
+		Address:    "1 Analytical Way",
+		Notes:      "Met at the Royal Society",
+		IsFavorite: true,
+	}})
+	if err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	moved, err := svc.MoveContact(ctx, from.ID, contact.ID, to.ID, "support-agent")
+	if err != nil {
+		t.Fatalf("MoveContact: %v", err)
+	}
+	if moved.ID != contact.ID || moved.UserID != to.ID {
+		t.Errorf("moved contact = %s owned by %s, want %s owned by %s", moved.ID, moved.UserID, contact.ID, to.ID)
+	}
+
+	for _, sk := range []string{"CONTACT#" + contact.ID, "FAV#" + contact.ID} {
+		if _, ok := repo.items["USER#"+from.ID][sk]; ok {
+			t.Errorf("%s still under old owner", sk)
+		}
+		if _, ok := repo.items["USER#"+to.ID][sk]; !ok {
+			t.Errorf("%s missing under new owner", sk)
+		}
+	}
+
+	// Every stored field moves with the contact
+	var stored models.ContactEntity
+	if err := attributevalue.UnmarshalMap(repo.items["USER#"+to.ID]["CONTACT#"+contact.ID], &stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored.JobTitle != "Engineer" || stored.Address != "1 Analytical Way" || stored.Notes != "Met at the Royal Society" {
+		t.Errorf("moved contact details = %q/%q/%q, want the originals", stored.JobTitle, stored.Address, stored.Notes)
+	}
+
+	entries, err := svc.ListAuditEntries(ctx, contact.ID)
+	if err != nil {
+		t.Fatalf("ListAuditEntries: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("audit entries = %d, want 1", len(entries))
+	}
+	if e := entries[0]; e.OldOwnerID != from.ID || e.NewOwnerID != to.ID || e.Actor != "support-agent" {
+		t.Errorf("audit entry = %+v, want %s -> %s by support-agent", e, from.ID, to.ID)
+	}
+}
+
+func TestMoveContact_TargetUserMissing(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+
+	from, err := svc.CreateUser(ctx, "ada@example.com", "Ada", "Lovelace")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	contact, err := svc.CreateContact(ctx, CreateContactInput{UserID: from.ID, ContactInput: ContactInput{Name: "Charles", Email: "charles@example.com"}})
+	if err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	_, err = svc.MoveContact(ctx, from.ID, contact.ID, "no-such-user", "support-agent")
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+	if _, ok := repo.items["USER#"+from.ID]["CONTACT#"+contact.ID]; !ok {
+		t.Error("contact removed from old owner despite failed move")
+	}
+}
+
+func TestCreateContacts_PartialFailure(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+
+	// "Throttled" never gets written; "Halfway" loses only its favorites index item
+	repo.failBatchPut = func(item repository.BaseModel) bool {
+		contact, ok := item.(*models.ContactEntity)
+		if !ok {
+			return false
+		}
+		return contact.Name == "Throttled" ||
+			(contact.Name == "Halfway" && strings.HasPrefix(contact.SK, "FAV#"))
+	}
+
+	result, err := svc.CreateContacts(ctx, []BatchContactInput{
+		{UserID: "u1", ContactInput: ContactInput{Name: "Ada", Email: "ada@example.com"}},
+		{UserID: "u1", ContactInput: ContactInput{Name: "", Email: "nameless@example.com"}},
+		{UserID: "u1", ContactInput: ContactInput{Name: "Throttled", Email: "t@example.com"}},
+		{UserID: "u2", ContactInput: ContactInput{Name: "Halfway", Email: "h@example.com", IsFavorite: true}},
+	})
+	if err != nil {
+		t.Fatalf("CreateContacts: %v", err)
+	}
+
+	if len(result.Contacts) != 2 {
+		t.Fatalf("created %d contacts, want 2", len(result.Contacts))
+	}
+	for _, contact := range result.Contacts {
+		if _, ok := repo.items["USER#"+contact.UserID]["CONTACT#"+contact.ID]; !ok {
+			t.Errorf("reported contact %s (%s) was not written", contact.ID, contact.Name)
+		}
+	}
+
+	halfway := result.Contacts[1]
+	if _, ok := repo.items["USER#u2"]["FAV#"+halfway.ID]; !ok {
+		t.Error("favorites index not repaired after its batch write failed")
+	}
+
+	want := []BatchItemError{
+		{Index: 1, Code: BatchErrValidation},
+		{Index: 2, Code: BatchErrWriteFailed},
+	}
+	if len(result.Errors) != len(want) {
+		t.Fatalf("errors = %+v, want %d entries", result.Errors, len(want))
+	}
+	for i, w := range want {
+		if got := result.Errors[i]; got.Index != w.Index || got.Code != w.Code {
+			t.Errorf("errors[%d] = %+v, want index %d code %s", i, got, w.Index, w.Code)
+		}
+	}
+}
+
+func TestFeatureFlags(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	svc.SetCacheStrategy(EntityContact, WriteThrough)
+	flags := featureflag.New(map[string]bool{FlagWriteThrough: false, FlagFavoritesIndex: false})
+	svc.SetFlags(flags)
+
+	if got := svc.cacheStrategy(EntityContact); got != CacheAside {
+		t.Errorf("strategy with write_through off = %v, want CacheAside", got)
+	}
+
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	contact, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Charles", IsFavorite: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// With favorites_index off the list must come from the contacts, not the FAV# items
+	delete(repo.items["USER#u1"], "FAV#"+contact.ID)
+	favorites, err := svc.ListFavoriteContacts(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(favorites) != 1 || favorites[0].ID != contact.ID {
+		t.Errorf("favorites = %v, want the favorite contact", favorites)
+	}
+}
+
+func TestUserEmailSentinel(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+
+	ada, err := svc.CreateUser(ctx, "ada@example.com", "Ada", "Lovelace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateUser(ctx, " ADA@Example.com", "Ada", "Again"); !errors.Is(err, ErrEmailTaken) {
+		t.Fatalf("duplicate email err = %v, want ErrEmailTaken", err)
+	}
+	grace, err := svc.CreateUser(ctx, "grace@example.com", "Grace", "Hopper")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := svc.GetUserByEmail(ctx, "Ada@Example.COM")
+	if err != nil || found.ID != ada.ID {
+		t.Fatalf("GetUserByEmail = %v, %v; want %s", found, err, ada.ID)
+	}
+
+	// Taking another user's email is refused and leaves both sentinels alone
+	if _, err := svc.UpdateUser(ctx, grace.ID, map[string]interface{}{"Email": "ADA@example.com"}); !errors.Is(err, ErrEmailTaken) {
+		t.Fatalf("update to a taken email err = %v, want ErrEmailTaken", err)
+	}
+
+	// A real change moves the sentinel, freeing the old email
+	if _, err := svc.UpdateUser(ctx, ada.ID, map[string]interface{}{"Email": "countess@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if found, err := svc.GetUserByEmail(ctx, "countess@example.com"); err != nil || found.ID != ada.ID {
+		t.Errorf("GetUserByEmail(new email) = %v, %v; want %s", found, err, ada.ID)
+	}
+	if _, err := svc.GetUserByEmail(ctx, "ada@example.com"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("old email err = %v, want ErrUserNotFound", err)
+	}
+	if _, err := svc.CreateUser(ctx, "ada@example.com", "Ada", "Byron"); err != nil {
+		t.Errorf("old email not released: %v", err)
+	}
+
+	// Deleting the user releases its email
+	if err := svc.DeleteUser(ctx, grace.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := repo.items[models.UserEmailPK("grace@example.com")]["METADATA"]; ok {
+		t.Error("email sentinel left behind by DeleteUser")
+	}
+}
+
+func TestSearchContacts(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	favorite, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Charles", Company: "Acme", Tags: []string{"vip"}, IsFavorite: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Grace"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	yes := true
+	result, err := svc.SearchContacts(ctx, "u1", ContactSearch{Favorite: &yes, Tag: "vip"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Access != SearchFavoritesIndex || result.Count != 1 || result.Contacts[0].ID != favorite.ID {
+		t.Errorf("favorite search = %+v, want the favorite read from the index", result)
+	}
+	if result.Filters.Tag != "vip" || result.Filters.Favorite == nil {
+		t.Errorf("filters = %+v, want every requested filter echoed", result.Filters)
+	}
+	if got := result.Contacts[0].Tags; len(got) != 1 || got[0] != "vip" {
+		t.Errorf("Tags = %v, want [vip]", got)
+	}
+
+	// Anything else filters the contacts themselves, with one condition per filter
+	search := ContactSearch{Company: "Acme", Tag: "vip", Query: "Char"}
+	result, err = svc.SearchContacts(ctx, "u1", search)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Access != SearchFilter || result.Count != 2 {
+		t.Errorf("company search access = %s, count = %d; want a filtered read of both contacts (the fake ignores filters)", result.Access, result.Count)
+	}
+	cond, ok := search.condition()
+	if !ok {
+		t.Fatal("no condition for a search with filters")
+	}
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]bool{}
+	for _, name := range expr.Names() {
+		names[name] = true
+	}
+	for _, want := range []string{"Company", "Tags", "Name", "Email"} {
+		if !names[want] {
+			t.Errorf("filter %s doesn't reference %s", *expr.Condition(), want)
+		}
+	}
+	if _, ok := (ContactSearch{}).condition(); ok {
+		t.Error("empty search built a condition")
+	}
+}
+
+func TestListAllContacts(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	cache := svc.cache.(*fakeCache)
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Charles", "Grace", "Alan"} {
+		if _, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: name}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first, err := svc.ListAllContacts(ctx, ContactListOptions{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Contacts) != 2 || first.NextCursor == "" {
+		t.Fatalf("first page = %d contacts, cursor %q; want 2 and a cursor", len(first.Contacts), first.NextCursor)
+	}
+	second, err := svc.ListAllContacts(ctx, ContactListOptions{Limit: 2, Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.Contacts) != 1 || second.NextCursor != "" {
+		t.Fatalf("second page = %d contacts, cursor %q; want the last contact", len(second.Contacts), second.NextCursor)
+	}
+	if second.Contacts[0].ID <= first.Contacts[1].ID {
+		t.Error("pages overlap or are out of contact ID order")
+	}
+
+	// Each page (and filter set) has its own cache entry
+	pages := 0
+	for key := range cache.values {
+		if strings.HasPrefix(key, "contacts:list:") {
+			pages++
+		}
+	}
+	if pages != 2 {
+		t.Errorf("cached %d pages, want one entry per distinct page", pages)
+	}
+	yes := true
+	if _, err := svc.ListAllContacts(ctx, ContactListOptions{Limit: 2, Filter: ContactSearch{Favorite: &yes}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(cache.values) == 0 {
+		t.Fatal("nothing cached")
+	}
+	keyA, _ := contactListKey(ContactListOptions{Limit: 2, EntityType: "CONTACT"})
+	keyB, _ := contactListKey(ContactListOptions{Limit: 2, EntityType: "CONTACT", Filter: ContactSearch{Favorite: &yes}})
+	if keyA == keyB {
+		t.Error("filtered and unfiltered pages share a cache key")
+	}
+
+	// A cursor only resumes the listing it came from
+	if _, err := svc.ListAllContacts(ctx, ContactListOptions{EntityType: "CONTACT_TRASH", Cursor: first.NextCursor}); !errors.Is(err, pagination.ErrInvalidCursor) {
+		t.Errorf("cursor from another entity type: err = %v, want ErrInvalidCursor", err)
+	}
+	if _, err := svc.ListAllContacts(ctx, ContactListOptions{EntityType: "USER"}); !errors.Is(err, validation.ErrValidation) {
+		t.Errorf("entity USER: err = %v, want a validation error", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	for _, user := range []*models.UserEntity{
+		models.NewUser("u1", "ada@example.com", "Ada", "Lovelace"),
+		models.NewUser("u2", "grace@example.com", "Grace", "Hopper"),
+	} {
+		if err := repo.put(user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"Charles", "Alan", "Edsger"} {
+		if _, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: name}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The fake ignores the contact filter, so every contact comes back after the users
+	first, err := svc.Search(ctx, "Lovelace", 2, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Users) != 1 || first.Users[0].ID != "u1" || len(first.Contacts) != 1 || first.NextCursor == "" {
+		t.Fatalf("first page = %d users, %d contacts, cursor %q; want u1, one contact and a cursor", len(first.Users), len(first.Contacts), first.NextCursor)
+	}
+	second, err := svc.Search(ctx, "Lovelace", 2, first.NextCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.Users) != 0 || len(second.Contacts) != 2 || second.NextCursor != "" {
+		t.Fatalf("second page = %d users, %d contacts, cursor %q; want the other two contacts", len(second.Users), len(second.Contacts), second.NextCursor)
+	}
+	for _, contact := range second.Contacts {
+		if contact.ID == first.Contacts[0].ID {
+			t.Error("contact repeated across pages")
+		}
+	}
+
+	// A page filled by users alone resumes with the next user
+	users, err := svc.Search(ctx, "@example.com", 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := svc.Search(ctx, "@example.com", 1, users.NextCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users.Users) != 1 || len(next.Users) != 1 || users.Users[0].ID == next.Users[0].ID {
+		t.Errorf("user pages = %v then %v, want one different user each", users.Users, next.Users)
+	}
+
+	if _, err := svc.Search(ctx, "  ", 10, ""); !errors.Is(err, validation.ErrValidation) {
+		t.Errorf("blank q: err = %v, want a validation error", err)
+	}
+	if _, err := svc.Search(ctx, "Ada", 10, "garbage"); !errors.Is(err, pagination.ErrInvalidCursor) {
+		t.Errorf("bad cursor: err = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestListContactChanges(t *testing.T) {
+	ctx := context.Background()
+	fixed := clock.NewFixed(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	t.Cleanup(clock.Set(fixed))
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+
+	old, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Old"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gone, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Gone"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sync point half a second in, so whole-second filtering alone would be wrong
+	fixed.Advance(500 * time.Millisecond)
+	since := fixed.Now()
+	fixed.Advance(100 * time.Millisecond)
+	added, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "New"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixed.Advance(time.Second)
+	if err := svc.DeleteContact(ctx, "u1", gone.ID); err != nil {
+		t.Fatal(err)
+	}
+	fixed.Advance(time.Second)
+
+	changes, err := svc.ListContactChanges(ctx, "u1", since)
+	if err != nil {
+		t.Fatalf("ListContactChanges: %v", err)
+	}
+	if changes.Count != 2 || changes.Contacts[0].ID != added.ID || changes.Contacts[0].Deleted {
+		t.Fatalf("changes = %+v, want the new contact then the deletion", changes.Contacts)
+	}
+	if deleted := changes.Contacts[1]; deleted.ID != gone.ID || !deleted.Deleted {
+		t.Errorf("second change = %+v, want %s marked deleted", deleted, gone.ID)
+	}
+	for _, change := range changes.Contacts {
+		if change.ID == old.ID {
+			t.Error("a contact unchanged since the sync point was returned")
+		}
+	}
+	if !changes.NextSince.Equal(fixed.Now()) {
+		t.Errorf("NextSince = %v, want the server time %v", changes.NextSince, fixed.Now())
+	}
+
+	// Polling from NextSince returns nothing until something changes
+	if changes, err = svc.ListContactChanges(ctx, "u1", changes.NextSince); err != nil || changes.Count != 0 {
+		t.Errorf("poll from next_since = %+v, %v; want no changes", changes, err)
+	}
+}
+
+// pagedRepo answers the single-page reads (Query, QueryWithFilter) with the first item
+// only, as if each item filled a 1 MB page. QueryWithStats and Count still read every
+// page, so a service reading through them sees everything.
+type pagedRepo struct {
+	*fakeRepo
+}
+
+func (r *pagedRepo) Query(ctx context.Context, pk string, skPrefix string, resultSlice interface{}) error {
+	items, _ := r.QueryItems(ctx, pk, skPrefix)
+	return attributevalue.UnmarshalListOfMaps(items[:min(len(items), 1)], resultSlice)
+}
+
+func (r *pagedRepo) QueryWithFilter(ctx context.Context, pk string, skPrefix string, filter expression.ConditionBuilder, resultSlice interface{}) error {
+	return r.Query(ctx, pk, skPrefix, resultSlice)
+}
+
+func (r *pagedRepo) QueryWithStats(ctx context.Context, pk string, skPrefix string, filter *expression.ConditionBuilder) ([]map[string]types.AttributeValue, repository.QueryStats, error) {
+	items, stats, err := r.fakeRepo.QueryWithStats(ctx, pk, skPrefix, filter)
+	stats.Pages = len(items)
+	return items, stats, err
+}
+
+func TestListContactChanges_EveryPage(t *testing.T) {
+	ctx := context.Background()
+	repo := &pagedRepo{newFakeRepo()}
+	svc := NewAppServiceWithCache(repo, newFakeCache())
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	since := clock.Now()
+	var ids []string
+	for _, name := range []string{"A", "B", "C"} {
+		contact, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: name}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, contact.ID)
+	}
+	for _, id := range ids[:2] {
+		if err := svc.DeleteContact(ctx, "u1", id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	changes, err := svc.ListContactChanges(ctx, "u1", since)
+	if err != nil {
+		t.Fatalf("ListContactChanges: %v", err)
+	}
+	deleted := 0
+	for _, change := range changes.Contacts {
+		if change.Deleted {
+			deleted++
+		}
+	}
+	if changes.Count != 3 || deleted != 2 {
+		t.Errorf("changes = %+v, want the live contact and both deletions from every page", changes.Contacts)
+	}
+}
+
+func TestListDeletedContacts_EveryPage(t *testing.T) {
+	ctx := context.Background()
+	repo := &pagedRepo{newFakeRepo()}
+	svc := NewAppServiceWithCache(repo, newFakeCache())
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"A", "B"} {
+		contact, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: name}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.DeleteContact(ctx, "u1", contact.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	trash, err := svc.ListDeletedContacts(ctx, "u1")
+	if err != nil {
+		t.Fatalf("ListDeletedContacts: %v", err)
+	}
+	if len(trash) != 2 {
+		t.Errorf("trash = %+v, want both deleted contacts from every page", trash)
+	}
+}
+
+// TestContactCreates_QueueEnrichment checks every way of creating a contact queues it
+// for enrichment
+func TestContactCreates_QueueEnrichment(t *testing.T) {
+	ctx := context.Background()
+	for name, create := range map[string]func(svc *AppServiceWithCache, in ContactInput) error{
+		"client-supplied ID": func(svc *AppServiceWithCache, in ContactInput) error {
+			_, _, err := svc.CreateContactWithID(ctx, uuid.New().String(), CreateContactInput{UserID: "u1", ContactInput: in})
+			return err
+		},
+		"batch": func(svc *AppServiceWithCache, in ContactInput) error {
+			_, err := svc.CreateContacts(ctx, []BatchContactInput{{UserID: "u1", ContactInput: in}})
+			return err
+		},
+		"import": func(svc *AppServiceWithCache, in ContactInput) error {
+			_, err := svc.ImportContacts(ctx, "u1", []ContactInput{in}, false, "")
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo := newFakeRepo()
+			svc := newTestService(repo)
+			if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+				t.Fatal(err)
+			}
+			enricher := &fakeEnricher{result: &enrichment.Result{}, called: make(chan string, 1)}
+			svc.SetEnricher(enricher, 1, 100)
+			t.Cleanup(svc.StopEnrichment)
+
+			if err := create(svc, ContactInput{Name: "Grace", Email: "grace@example.com"}); err != nil {
+				t.Fatal(err)
+			}
+			select {
+			case email := <-enricher.called:
+				if email != "grace@example.com" {
+					t.Errorf("looked up %q, want the contact's email", email)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("contact was not queued for enrichment")
+			}
+		})
+	}
+}
+
+// fakeEnricher answers every lookup with result and reports the email looked up
+type fakeEnricher struct {
+	result *enrichment.Result
+	called chan string
+}
+
+func (e *fakeEnricher) Enrich(ctx context.Context, email string) (*enrichment.Result, error) {
+	e.called <- email
+	return e.result, nil
+}
+
+// TestCreateContact_IgnoresMaxTransactionItems checks a small TX_MAX_ITEMS doesn't split
+// a contact from its favorites index item and its owner's count
+func TestCreateContact_IgnoresMaxTransactionItems(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	svc.SetMaxTransactionItems(2)
+
+	// No user: the count update's condition fails, which must cancel the puts too
+	_, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Grace", IsFavorite: true}})
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+	if len(repo.items["USER#u1"]) != 0 {
+		t.Errorf("left %d items behind for a missing user", len(repo.items["USER#u1"]))
+	}
+
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	contact, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Grace", IsFavorite: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := repo.items["USER#u1"]["FAV#"+contact.ID]; !ok {
+		t.Error("favorites index item not written")
+	}
+	user := &models.UserEntity{}
+	if err := repo.Get(ctx, "USER#u1", "METADATA", user); err != nil {
+		t.Fatal(err)
+	}
+	if user.ContactCount != 1 {
+		t.Errorf("ContactCount = %d, want 1", user.ContactCount)
+	}
+}
+
+func TestCreateContact_RejectDuplicatesIgnoresCase(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Grace", Email: "Grace@Example.com"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Grace", Email: "grace@example.com"}, RejectDuplicates: true})
+	if !errors.Is(err, ErrContactExists) {
+		t.Errorf("err = %v, want ErrContactExists for the same email in other case", err)
+	}
+	if _, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Alan", Email: "alan@example.com"}, RejectDuplicates: true}); err != nil {
+		t.Errorf("new email rejected: %v", err)
+	}
+}
+
+func TestCreateContact_Enrichment(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := newTestService(repo)
+	if err := repo.put(models.NewUser("u1", "ada@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+	enricher := &fakeEnricher{result: &enrichment.Result{Company: "Acme", JobTitle: "CTO"}, called: make(chan string, 1)}
+	svc.SetEnricher(enricher, 1, 100)
+
+	contact, err := svc.CreateContact(ctx, CreateContactInput{UserID: "u1", ContactInput: ContactInput{Name: "Grace", Email: "grace@example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case email := <-enricher.called:
+		if email != "grace@example.com" {
+			t.Errorf("looked up %q, want the contact's email", email)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("contact was not enriched")
+	}
+	svc.StopEnrichment() // waits for the lookup in flight to be applied
+
+	stored := &models.ContactEntity{}
+	if err := repo.Get(ctx, "USER#u1", "CONTACT#"+contact.ID, stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored.Company != "Acme" || stored.JobTitle != "CTO" {
+		t.Errorf("company, job title = %q, %q; want the enrichment", stored.Company, stored.JobTitle)
+	}
+}
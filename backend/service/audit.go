@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/requestid"
+	"hub-control-plane/backend/tracing"
+)
+
+// ============================================================================
+// AUDIT LOG
+// ============================================================================
+
+// appendAudit writes an audit entry. It is best effort: the change it describes has
+// already happened, so a failed write is logged rather than returned.
+func (s *AppServiceWithCache) appendAudit(ctx context.Context, entry *models.AuditEntry) {
+	entry.RequestID = requestid.FromContext(ctx)
+	if err := s.repo.Put(ctx, entry); err != nil {
+		requestid.Logf(ctx, "Warning: failed to write audit entry %s for %s %s: %v", entry.Action, entry.TargetType, entry.TargetID, err)
+	}
+}
+
+// newAuditEntry builds an audit entry with a fresh ID
+func newAuditEntry(targetType, targetID, action, actor string) *models.AuditEntry {
+	return models.NewAuditEntry(uuid.New().String(), targetType, targetID, action, actor)
+}
+
+// ListAuditEntries returns the audit history of one entity, oldest first.
+// Not cached: it's a support tool and must reflect every write.
+func (s *AppServiceWithCache) ListAuditEntries(ctx context.Context, entityID string) ([]*models.AuditEntry, error) {
+	ctx, span := tracing.Start(ctx, "AppService.ListAuditEntries")
+	defer span.End()
+
+	pk := fmt.Sprintf("AUDIT#%s", entityID)
+	items, err := s.repo.QueryItems(ctx, pk, "AUDIT#")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	entries, err := repository.UnmarshalItems[*models.AuditEntry](items)
+	if err != nil {
+		requestid.Logf(ctx, "Warning: skipped unreadable audit entries for %s: %v", entityID, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/validation"
+)
+
+// DefaultAvatarMaxBytes is the largest avatar upload presigned until SetAvatarUploads says otherwise
+const DefaultAvatarMaxBytes = 5 << 20 // 5 MB
+
+// avatarExtensions are the image types accepted as avatars, with the extension their objects get
+var avatarExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/webp": "webp",
+	"image/gif":  "gif",
+}
+
+// ErrAvatarUploadsDisabled is returned by PresignContactAvatar when no bucket is configured
+var ErrAvatarUploadsDisabled = errors.New("avatar uploads are not configured")
+
+// SetAvatarUploads enables contact avatar uploads through presigner, capped at maxBytes
+// per image (zero keeps DefaultAvatarMaxBytes)
+func (s *AppServiceWithCache) SetAvatarUploads(presigner repository.UploadPresigner, maxBytes int64) {
+	s.avatars = presigner
+	if maxBytes > 0 {
+		s.avatarMaxBytes = maxBytes
+	}
+}
+
+// PresignContactAvatar returns a URL the client uploads the contact's avatar to directly,
+// and points the contact's AvatarURL at the object. The signature pins contentType and
+// size, so S3 refuses any other upload. Each call uses a new object key, so a cached copy
+// of an older avatar is never served for the new one; until the upload happens the URL
+// points at nothing.
+// Flow: Validate type + size → Presign PUT → Set AvatarURL (caches refreshed, webhook sent)
+func (s *AppServiceWithCache) PresignContactAvatar(ctx context.Context, userID, contactID, contentType string, size int64) (*repository.PresignedUpload, error) {
+	if s.avatars == nil {
+		return nil, ErrAvatarUploadsDisabled
+	}
+
+	// 1. Validate what the client says it will upload
+	ext, ok := avatarExtensions[contentType]
+	if !ok {
+		return nil, &validation.FieldError{Field: "content_type", Message: "must be image/jpeg, image/png, image/webp or image/gif"}
+	}
+	if size < 1 || size > s.avatarMaxBytes {
+		return nil, &validation.FieldError{Field: "size", Message: fmt.Sprintf("must be between 1 and %d bytes", s.avatarMaxBytes)}
+	}
+
+	// 2. Presign the upload
+	key := fmt.Sprintf("avatars/%s/%s/%s.%s", userID, contactID, uuid.New().String(), ext)
+	upload, err := s.avatars.PresignPut(ctx, key, contentType, size)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Record where the avatar will live
+	if _, err := s.UpdateContact(ctx, userID, contactID, map[string]interface{}{"AvatarURL": upload.ObjectURL}); err != nil {
+		return nil, err
+	}
+
+	return upload, nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/google/uuid"
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/requestid"
+	"hub-control-plane/backend/tracing"
+	"hub-control-plane/backend/validation"
+)
+
+// MaxBulkUsers caps how many users one CreateUsers call may create
+const MaxBulkUsers = 1000
+
+// ErrTooManyUsers is returned by CreateUsers for more than MaxBulkUsers inputs
+var ErrTooManyUsers = fmt.Errorf("at most %d users per request", MaxBulkUsers)
+
+// UserInput is one user to create in a bulk request
+type UserInput struct {
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// Bulk user statuses
+const (
+	BulkUserCreated  = "created"  // Written; User is set
+	BulkUserConflict = "conflict" // A user with this email already exists
+	BulkUserError    = "error"    // Invalid, duplicated within the batch, or not written
+)
+
+// BulkUserResult is the outcome for the input at Index
+type BulkUserResult struct {
+	Index  int                `json:"index"`
+	Status string             `json:"status"`
+	User   *models.UserEntity `json:"user,omitempty"`
+	Error  string             `json:"error,omitempty"`
+}
+
+// normalizeEmail is the form emails are compared and stored in by CreateUsers
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// CreateUsers creates many users in one BatchWrite and returns one result per input, in
+// input order. Emails are normalized; an email that an existing user already has is a
+// conflict, and a repeat of an earlier email in the same batch is an error. New users get
+// fresh IDs, so the unconditional batch put can't overwrite anyone. Each user's EMAIL#
+// sentinel goes in the same batch; unlike CreateUser's transaction that isn't atomic, so
+// the up-front email check is what keeps the batch from taking an email.
+// Flow: Load existing emails (every page) → Validate + dedupe rows → BatchWrite users + sentinels → Invalidate user list once
+func (s *AppServiceWithCache) CreateUsers(ctx context.Context, inputs []UserInput) ([]BulkUserResult, error) {
+	ctx, span := tracing.Start(ctx, "AppService.CreateUsers")
+	defer span.End()
+
+	if len(inputs) > MaxBulkUsers {
+		return nil, ErrTooManyUsers
+	}
+
+	// 1. Load existing emails once instead of querying per row (DB, not the list cache, so the check is exact)
+	taken, err := s.userEmails(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load existing users: %w", err)
+	}
+
+	// 2. Validate and dedupe each row
+	results := make([]BulkUserResult, len(inputs))
+	firstIndex := make(map[string]int, len(inputs)) // email -> first input using it
+	owner := make(map[string]int, len(inputs))      // PK of each item -> input index
+	items := make([]repository.BaseModel, 0, 2*len(inputs))
+	for i, in := range inputs {
+		results[i].Index = i
+		email := normalizeEmail(in.Email)
+
+		if err := validation.CreateUser(email, in.FirstName, in.LastName); err != nil {
+			results[i].Status, results[i].Error = BulkUserError, err.Error()
+			continue
+		}
+		if taken[email] {
+			results[i].Status, results[i].Error = BulkUserConflict, "user with this email already exists"
+			continue
+		}
+		if first, ok := firstIndex[email]; ok {
+			results[i].Status, results[i].Error = BulkUserError, fmt.Sprintf("duplicate email, already used at index %d", first)
+			continue
+		}
+		firstIndex[email] = i
+
+		user := models.NewUser(uuid.New().String(), email, in.FirstName, in.LastName)
+		user.SetTimestamps() // BatchWrite doesn't stamp items the way Put does
+		results[i].Status, results[i].User = BulkUserCreated, user
+		items = append(items, user, models.NewUserEmailIndex(email, user.ID))
+		owner[user.PK] = i
+	}
+	if len(items) == 0 {
+		return results, nil
+	}
+
+	// 3. Persist; only items still unwritten after retries come back as failed
+	if err := s.repo.BatchWrite(ctx, items, nil); err != nil {
+		var batchErr *repository.BatchWriteError
+		if !errors.As(err, &batchErr) {
+			return nil, fmt.Errorf("failed to create users: %w", err)
+		}
+		requestid.Logf(ctx, "Warning: bulk user create partially failed: %v", err)
+		for _, key := range batchErr.Keys {
+			if i, ok := owner[key["PK"]]; ok {
+				results[i] = BulkUserResult{Index: i, Status: BulkUserError, Error: "user was not written"}
+			}
+		}
+	}
+
+	// 4. One list invalidation for the whole batch
+	if err := s.invalidateUserListCache(ctx); err != nil {
+		requestid.Logf(ctx, "Warning: failed to invalidate user list cache: %v", err)
+	}
+
+	created := 0
+	for _, result := range results {
+		if result.Status == BulkUserCreated {
+			created++
+		}
+	}
+	requestid.Logf(ctx, "Bulk created %d of %d users", created, len(inputs))
+	return results, nil
+}
+
+// userEmails returns the normalized email of every user, reading every page of GSI1
+// with only Email projected
+func (s *AppServiceWithCache) userEmails(ctx context.Context) (map[string]bool, error) {
+	keyCond := expression.Key("GSI1PK").Equal(expression.Value("USER"))
+	opts := repository.QueryOptions{Projection: []string{"Email"}}
+
+	emails := make(map[string]bool)
+	for {
+		var page []*models.UserEntity
+		next, err := s.repo.QueryIndex(ctx, "GSI1", keyCond, opts, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range page {
+			emails[normalizeEmail(user.Email)] = true
+		}
+		if next == nil {
+			return emails, nil
+		}
+		opts.StartKey = next
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/ugorji/go/codec"
+)
+
+// CacheCodec serializes the values the service caches (users, contacts, list pages,
+// dashboards)
+type CacheCodec interface {
+	Name() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// Cache codecs by name, as configured with CACHE_CODEC
+var (
+	JSONCodec    CacheCodec = jsonCodec{}
+	MsgpackCodec CacheCodec = msgpackCodec{}
+)
+
+// ParseCacheCodec returns the codec called name ("json" or "msgpack")
+func ParseCacheCodec(name string) (CacheCodec, error) {
+	for _, c := range []CacheCodec{JSONCodec, MsgpackCodec} {
+		if c.Name() == name {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown cache codec %q (want json or msgpack)", name)
+}
+
+// SetCacheCodec sets the format new cache entries are written in (JSONCodec until set).
+// Entries are read in whichever format they were written, so switching codecs, or
+// replicas disagreeing during a rollout, never turns existing entries into misses.
+func (s *AppServiceWithCache) SetCacheCodec(c CacheCodec) {
+	s.cacheCodec = c
+}
+
+// encodeCached serializes v with the configured codec
+func (s *AppServiceWithCache) encodeCached(v interface{}) ([]byte, error) {
+	if s.cacheCodec == nil {
+		return JSONCodec.Marshal(v)
+	}
+	return s.cacheCodec.Marshal(v)
+}
+
+// decodeCached deserializes a cached value with the codec it was written with
+func decodeCached(data []byte, v interface{}) error {
+	if len(data) > 0 && data[0] == msgpackMarker {
+		return MsgpackCodec.Unmarshal(data, v)
+	}
+	return JSONCodec.Unmarshal(data, v)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string                               { return "json" }
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// msgpackMarker starts every msgpack entry. 0xc1 is the one byte msgpack never uses and
+// can't begin a JSON document, so it tells the formats apart.
+const msgpackMarker = 0xc1
+
+// msgpackHandle follows the json struct tags, so both codecs cache the same fields
+var msgpackHandle = func() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{WriteExt: true}
+	h.RawToString = true
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	return h
+}()
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string { return "msgpack" }
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(msgpackMarker)
+	if err := codec.NewEncoder(&buf, msgpackHandle).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	if len(data) == 0 || data[0] != msgpackMarker {
+		return fmt.Errorf("not a msgpack cache entry")
+	}
+	return codec.NewDecoderBytes(data[1:], msgpackHandle).Decode(v)
+}
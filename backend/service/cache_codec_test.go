@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"hub-control-plane/backend/models"
+)
+
+func TestCacheCodec_ReadsEitherFormat(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	cache := newFakeCache()
+	if err := repo.put(models.NewUser("u1", "db@example.com", "Ada", "Lovelace")); err != nil {
+		t.Fatal(err)
+	}
+
+	writer := NewAppServiceWithCache(repo, cache)
+	writer.SetCacheCodec(MsgpackCodec)
+	if _, err := writer.GetUser(ctx, "u1"); err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if data := cache.values["user:u1"]; len(data) == 0 || data[0] != msgpackMarker {
+		t.Fatalf("user:u1 = %q, want a msgpack entry", data)
+	}
+
+	// A replica still writing JSON reads the msgpack entry as a hit
+	reader := NewAppServiceWithCache(repo, cache)
+	user, err := reader.GetUser(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if user.Email != "db@example.com" || user.FirstName != "Ada" || user.CreatedAt.IsZero() {
+		t.Errorf("decoded user = %+v, want the stored user", user)
+	}
+	if repo.gets != 1 {
+		t.Errorf("repo Get called %d times, want 1 (second read from cache)", repo.gets)
+	}
+}
+
+func TestParseCacheCodec(t *testing.T) {
+	for _, name := range []string{"json", "msgpack"} {
+		if c, err := ParseCacheCodec(name); err != nil || c.Name() != name {
+			t.Errorf("ParseCacheCodec(%q) = %v, %v", name, c, err)
+		}
+	}
+	if _, err := ParseCacheCodec("gob"); err == nil {
+		t.Error("ParseCacheCodec(gob): want an error")
+	}
+}
+
+// BenchmarkCacheCodec compares the codecs on a 1000-contact list page
+func BenchmarkCacheCodec(b *testing.B) {
+	contacts := make([]*models.ContactEntity, 1000)
+	for i := range contacts {
+		contacts[i] = models.NewContact(fmt.Sprintf("c%d", i), "u1", "Grace Hopper", "grace@example.com",
+			"+1 555 0100", "Navy", "Rear Admiral", "Arlington, VA", "Invented the first compiler", i%10 == 0)
+		contacts[i].Tags = []string{"vip", "engineering"}
+	}
+
+	for _, c := range []CacheCodec{JSONCodec, MsgpackCodec} {
+		data, err := c.Marshal(contacts)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(c.Name()+"/encode", func(b *testing.B) {
+			b.ReportMetric(float64(len(data)), "bytes/entry")
+			for b.Loop() {
+				if _, err := c.Marshal(contacts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(c.Name()+"/decode", func(b *testing.B) {
+			for b.Loop() {
+				var decoded []*models.ContactEntity
+				if err := decodeCached(data, &decoded); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
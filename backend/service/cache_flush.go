@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"hub-control-plane/backend/requestid"
+	"hub-control-plane/backend/tracing"
+)
+
+// Cache flush scopes accepted by FlushCache
+const (
+	FlushScopeUser     = "user"
+	FlushScopeContacts = "contacts"
+	FlushScopeAll      = "all"
+)
+
+// ErrInvalidFlushScope is returned by FlushCache for an unknown scope
+var ErrInvalidFlushScope = errors.New("invalid flush scope")
+
+// FlushCache force-evicts cache entries during incidents.
+// With an ID only that user's entries are removed; without one the whole scope is flushed.
+//
+//	user:     user:<id>, dashboard:<id> (no ID: every user entry and users:list)
+//	contacts: contact:<id>:*, contacts:*:user:<id> (no ID: every contact entry and list)
+//	all:      both of the above
+//
+// Flow: Resolve scope → Build key patterns → SCAN/delete each → Return count
+func (s *AppServiceWithCache) FlushCache(ctx context.Context, scope, id string) (int, error) {
+	ctx, span := tracing.Start(ctx, "AppService.FlushCache")
+	defer span.End()
+
+	// 1. Work out which key patterns the scope covers
+	var patterns []string
+	switch scope {
+	case FlushScopeUser:
+		patterns = userFlushPatterns(id)
+	case FlushScopeContacts:
+		patterns = contactFlushPatterns(id)
+	case FlushScopeAll:
+		patterns = append(userFlushPatterns(id), contactFlushPatterns(id)...)
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrInvalidFlushScope, scope)
+	}
+
+	// 2. Delete everything matching; the count so far is kept on error
+	deleted := 0
+	for _, pattern := range patterns {
+		n, err := s.cache.DelPattern(ctx, pattern)
+		deleted += n
+		if err != nil {
+			return deleted, fmt.Errorf("failed to flush %s: %w", pattern, err)
+		}
+	}
+
+	requestid.Logf(ctx, "Cache flush: scope=%s id=%q deleted=%d", scope, id, deleted)
+	return deleted, nil
+}
+
+// InvalidateUserCache evicts everything cached for one user: the user entry, dashboard,
+// contacts and contact lists. The next reads repopulate them from DynamoDB.
+func (s *AppServiceWithCache) InvalidateUserCache(ctx context.Context, userID string) (int, error) {
+	if userID == "" {
+		return 0, fmt.Errorf("%w: user ID is required", ErrInvalidFlushScope)
+	}
+	return s.FlushCache(ctx, FlushScopeAll, userID)
+}
+
+func userFlushPatterns(id string) []string {
+	if id == "" {
+		return []string{"user:*", "users:*", "dashboard:*"}
+	}
+	id = escapeKeyPattern(id)
+	return []string{"user:" + id, "dashboard:" + id}
+}
+
+func contactFlushPatterns(id string) []string {
+	if id == "" {
+		return []string{"contact:*", "contacts:*"}
+	}
+	id = escapeKeyPattern(id)
+	return []string{fmt.Sprintf("contact:%s:*", id), userContactListKey("*", id)}
+}
+
+// keyPatternEscaper backslash-escapes the glob metacharacters Redis MATCH understands
+var keyPatternEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// escapeKeyPattern makes an ID safe to embed in a SCAN pattern, so an ID like "*"
+// can't widen a per-user flush to every user
+func escapeKeyPattern(id string) string {
+	return keyPatternEscaper.Replace(id)
+}
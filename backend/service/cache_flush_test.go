@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFlushCache(t *testing.T) {
+	seed := func() *fakeCache {
+		cache := newFakeCache()
+		for _, key := range []string{
+			"user:u1", "dashboard:u1", "user:u2", "users:list",
+			"contact:u1:c1", userContactListKey(contactViewAll, "u1"),
+			"contact:u2:c2", "contacts:list",
+			"session:abc",
+		} {
+			cache.values[key] = []byte(`{}`)
+		}
+		return cache
+	}
+
+	tests := []struct {
+		name      string
+		scope, id string
+		want      int
+	}{
+		{"one user", FlushScopeUser, "u1", 2},
+		{"all users", FlushScopeUser, "", 4},
+		{"one user's contacts", FlushScopeContacts, "u1", 2},
+		{"all contacts", FlushScopeContacts, "", 4},
+		{"everything for one user", FlushScopeAll, "u1", 4},
+		{"everything", FlushScopeAll, "", 8},
+		{"glob in id matches nothing", FlushScopeUser, "*", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := seed()
+			svc := NewAppServiceWithCache(newFakeRepo(), cache)
+
+			deleted, err := svc.FlushCache(context.Background(), tt.scope, tt.id)
+			if err != nil {
+				t.Fatalf("FlushCache: %v", err)
+			}
+			if deleted != tt.want {
+				t.Errorf("deleted = %d, want %d", deleted, tt.want)
+			}
+			if _, ok := cache.values["session:abc"]; !ok {
+				t.Error("flush removed a key outside the app's namespaces")
+			}
+		})
+	}
+}
+
+func TestFlushCache_InvalidScope(t *testing.T) {
+	svc := NewAppServiceWithCache(newFakeRepo(), newFakeCache())
+
+	_, err := svc.FlushCache(context.Background(), "sessions", "")
+	if !errors.Is(err, ErrInvalidFlushScope) {
+		t.Fatalf("err = %v, want ErrInvalidFlushScope", err)
+	}
+}
+
+func TestInvalidateUserCache(t *testing.T) {
+	cache := newFakeCache()
+	for _, key := range []string{"user:u1", "dashboard:u1", "contact:u1:c1", userContactListKey(contactViewAll, "u1"), "user:u2"} {
+		cache.values[key] = []byte(`{}`)
+	}
+	svc := NewAppServiceWithCache(newFakeRepo(), cache)
+
+	deleted, err := svc.InvalidateUserCache(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("InvalidateUserCache: %v", err)
+	}
+	if deleted != 4 {
+		t.Errorf("deleted = %d, want 4", deleted)
+	}
+	if _, ok := cache.values["user:u2"]; !ok {
+		t.Error("another user's entry was evicted")
+	}
+
+	// An empty ID must not turn into a flush of every user
+	if _, err := svc.InvalidateUserCache(context.Background(), ""); !errors.Is(err, ErrInvalidFlushScope) {
+		t.Errorf("err = %v, want ErrInvalidFlushScope", err)
+	}
+	if _, ok := cache.values["user:u2"]; !ok {
+		t.Error("empty ID flushed other users")
+	}
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotCached is returned by reads in cache-only mode when the cache has no copy
+var ErrNotCached = errors.New("not available in cache")
+
+type cacheOnlyKey struct{}
+
+// WithCacheOnly marks ctx so cached reads never fall through to DynamoDB: a cache miss
+// (or an unreachable cache) returns ErrNotCached instead of querying the table.
+// Reads that are never cached (contact pages, favorite counts) refuse outright.
+// Meant for reporting traffic that can accept stale data but must not load the table.
+// Writes are unaffected.
+func WithCacheOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheOnlyKey{}, true)
+}
+
+// IsCacheOnly reports whether ctx was marked with WithCacheOnly
+func IsCacheOnly(ctx context.Context) bool {
+	only, _ := ctx.Value(cacheOnlyKey{}).(bool)
+	return only
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/requestid"
+	"hub-control-plane/backend/tenant"
+)
+
+// CacheStrategy controls what happens to list caches after a write
+type CacheStrategy int
+
+const (
+	// CacheAside deletes the affected list caches; the next read repopulates them.
+	// Cheapest on writes and never serves a list the DB hasn't confirmed, but the
+	// first read after every write is a miss - fine for read-heavy entities.
+	CacheAside CacheStrategy = iota
+
+	// WriteThrough patches the written item into cached lists in place. Avoids the
+	// miss spike after writes on write-heavy entities, at the cost of a Redis
+	// read-modify-write per write. Lists that aren't cached are left for the next read.
+	WriteThrough
+)
+
+// Entity names accepted by SetCacheStrategy
+const (
+	EntityUser    = "USER"
+	EntityContact = "CONTACT"
+)
+
+// ParseCacheStrategy parses "cache-aside" or "write-through"
+func ParseCacheStrategy(value string) (CacheStrategy, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "cache-aside":
+		return CacheAside, nil
+	case "write-through":
+		return WriteThrough, nil
+	}
+	return CacheAside, fmt.Errorf("unknown cache strategy %q (want cache-aside or write-through)", value)
+}
+
+// SetCacheStrategy sets the list cache strategy for an entity type (EntityUser,
+// EntityContact). Entities default to CacheAside.
+func (s *AppServiceWithCache) SetCacheStrategy(entity string, strategy CacheStrategy) {
+	if s.cacheStrategies == nil {
+		s.cacheStrategies = make(map[string]CacheStrategy)
+	}
+	s.cacheStrategies[entity] = strategy
+}
+
+// cacheStrategy returns the configured strategy for an entity type. WriteThrough falls
+// back to CacheAside while the write_through flag is off.
+func (s *AppServiceWithCache) cacheStrategy(entity string) CacheStrategy {
+	strategy := s.cacheStrategies[entity]
+	if strategy == WriteThrough && !s.flagEnabled(FlagWriteThrough) {
+		return CacheAside
+	}
+	return strategy
+}
+
+// refreshUserListCache updates the user list cache after a user was written
+func (s *AppServiceWithCache) refreshUserListCache(ctx context.Context, user *models.UserEntity) error {
+	if s.cacheStrategy(EntityUser) == WriteThrough {
+		return patchCachedList(ctx, s, "users:list", user, func(u *models.UserEntity) bool {
+			return u.ID == user.ID
+		})
+	}
+	return s.invalidateUserListCache(ctx)
+}
+
+// refreshUserContactCaches updates a user's contact list caches after a contact was written.
+// The favorites list is always invalidated since membership depends on IsFavorite.
+func (s *AppServiceWithCache) refreshUserContactCaches(ctx context.Context, contact *models.ContactEntity) error {
+	if s.cacheStrategy(EntityContact) != WriteThrough {
+		return s.invalidateUserContactCaches(ctx, contact.UserID)
+	}
+
+	key := userContactListKey(contactViewAll, contact.UserID)
+	if err := patchCachedList(ctx, s, key, contact, func(c *models.ContactEntity) bool {
+		return c.ID == contact.ID
+	}); err != nil {
+		return err
+	}
+	// The projected list is dropped rather than patched with a full contact
+	return s.cache.Del(ctx, userContactListKey(contactViewFavorites, contact.UserID),
+		userContactListKey(contactViewProjected, contact.UserID))
+}
+
+// patchCachedList replaces the matching entry in a cached list (or appends the item)
+// and writes the list back. A missing or unreadable list is dropped rather than built
+// from scratch, leaving the next read to populate it from DynamoDB.
+func patchCachedList[T any](ctx context.Context, s *AppServiceWithCache, key string, item T, matches func(T) bool) error {
+	cached, _, err := s.cacheGet(ctx, key)
+	if errors.Is(err, repository.ErrCacheMiss) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var list []T
+	if err := decodeCached(cached, &list); err != nil {
+		return s.cache.Del(ctx, key)
+	}
+
+	replaced := false
+	for i, existing := range list {
+		if matches(existing) {
+			list[i] = item
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		list = append(list, item)
+	}
+
+	s.cacheList(ctx, key, len(list), list)
+	return nil
+}
+
+// ============================================================================
+// READ REPAIR
+// ============================================================================
+
+// SetReadRepair enables invalidating users:list when GetUser sees a user newer
+// than its entry in the cached list. Off by default: it costs an extra cache read
+// (off the request path) for every GetUser.
+func (s *AppServiceWithCache) SetReadRepair(enabled bool) {
+	s.readRepair = enabled
+}
+
+// scheduleUserListRepair runs repairUserListCache in the background when read repair is on
+func (s *AppServiceWithCache) scheduleUserListRepair(ctx context.Context, user *models.UserEntity) {
+	if !s.readRepair {
+		return
+	}
+
+	repairCtx := detach(ctx)
+	go func() {
+		repairCtx, cancel := context.WithTimeout(repairCtx, 5*time.Second)
+		defer cancel()
+
+		if err := s.repairUserListCache(repairCtx, user); err != nil {
+			requestid.Logf(repairCtx, "Warning: read repair of users:list failed: %v", err)
+		}
+	}()
+}
+
+// detach returns a context for background work that outlives the request: it keeps the
+// request ID for logs and the tenant so the work hits the right table and cache namespace
+func detach(ctx context.Context) context.Context {
+	detached := requestid.WithID(context.Background(), requestid.FromContext(ctx))
+	return tenant.WithID(detached, tenant.FromContext(ctx))
+}
+
+// repairUserListCache invalidates users:list if its entry for user has an older UpdatedAt.
+// A missing list, or a list that doesn't contain the user, is left alone.
+func (s *AppServiceWithCache) repairUserListCache(ctx context.Context, user *models.UserEntity) error {
+	cached, err := s.cache.Get(ctx, "users:list")
+	if errors.Is(err, repository.ErrCacheMiss) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var users []*models.UserEntity
+	if err := decodeCached(cached, &users); err != nil {
+		return s.invalidateUserListCache(ctx)
+	}
+
+	for _, listed := range users {
+		if listed.ID != user.ID {
+			continue
+		}
+		if listed.UpdatedAt.Before(user.UpdatedAt) {
+			requestid.Logf(ctx, "Read repair: users:list has stale copy of user %s, invalidating", user.ID)
+			return s.invalidateUserListCache(ctx)
+		}
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"hub-control-plane/backend/requestid"
+)
+
+// SetSlidingExpiry makes GetUser and GetContact reset the entry's TTL on every cache
+// hit, so popular records that never change stay cached instead of being re-read from
+// DynamoDB each time their TTL lapses. Writes still replace or drop the entry as usual.
+func (s *AppServiceWithCache) SetSlidingExpiry(enabled bool) {
+	s.slidingExpiry = enabled
+}
+
+// TouchCache resets key's TTL to the full TTL for its kind of entry (see CacheTTLs)
+// without re-fetching or rewriting the value. Touching a missing key does nothing.
+// Keys served stale-while-revalidate are left alone: their soft expiry lives in the
+// value, and a plain TTL reset would cut their stale window short.
+func (s *AppServiceWithCache) TouchCache(ctx context.Context, key string) error {
+	if s.staleWhileRevalidate() && staleWhileRevalidateKey(key) {
+		return nil
+	}
+	return s.cache.Expire(ctx, key, s.CacheTTLs().forKey(key))
+}
+
+// forKey returns the TTL entries under key are written with
+func (t CacheTTLs) forKey(key string) time.Duration {
+	switch {
+	case strings.HasPrefix(key, "user:"), strings.HasPrefix(key, "contact:"):
+		return t.User
+	case strings.HasPrefix(key, "dashboard:"):
+		return t.Dashboard
+	}
+	return t.List
+}
+
+// slideExpiry is TouchCache on a cache hit when sliding expiry is on. A failure only
+// means the entry expires on schedule, so it is logged and ignored.
+func (s *AppServiceWithCache) slideExpiry(ctx context.Context, key string) {
+	if !s.slidingExpiry {
+		return
+	}
+	if err := s.TouchCache(ctx, key); err != nil {
+		requestid.Logf(ctx, "Warning: failed to extend cache TTL of %s: %v", key, err)
+	}
+}
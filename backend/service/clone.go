@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/tracing"
+)
+
+// cloneNameSuffix marks a cloned contact's name so it isn't mistaken for the original
+const cloneNameSuffix = " (copy)"
+
+// SetCloneKeepsFavorite makes CloneContact keep the source's favorite flag.
+// Off by default: a clone is a template, not a second favorite.
+func (s *AppServiceWithCache) SetCloneKeepsFavorite(keep bool) {
+	s.cloneKeepsFavorite = keep
+}
+
+// CloneContact creates a new contact for the user from one of their existing contacts,
+// tags included, as a starting point to edit. The clone gets a new ID, " (copy)"
+// appended to its name, its own timestamps, and (unless SetCloneKeepsFavorite) no
+// favorite flag.
+// Flow: Load source from DB → Copy fields → CreateContact (count, caches, webhook)
+func (s *AppServiceWithCache) CloneContact(ctx context.Context, userID, contactID string) (*models.ContactEntity, error) {
+	ctx, span := tracing.Start(ctx, "AppService.CloneContact")
+	defer span.End()
+
+	// 1. Load the source straight from DynamoDB so the copy isn't of a stale cached version
+	source := &models.ContactEntity{}
+	if err := s.repo.Get(ctx, fmt.Sprintf("USER#%s", userID), fmt.Sprintf("CONTACT#%s", contactID), source); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("contact not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get contact: %w", err)
+	}
+
+	// 2. Create it like any new contact: fresh ID and timestamps, ContactCount bumped,
+	//    list caches refreshed and a ContactCreated webhook sent
+	return s.CreateContact(ctx, CreateContactInput{
+		UserID: userID,
+		ContactInput: ContactInput{
+			Name:       source.Name + cloneNameSuffix,
+			Email:      source.Email,
+			Phone:      source.Phone,
+			Company:    source.Company,
+			JobTitle:   source.JobTitle,
+			Address:    source.Address,
+			Notes:      source.Notes,
+			Tags:       slices.Clone(source.Tags),
+			IsFavorite: source.IsFavorite && s.cloneKeepsFavorite,
+		},
+	})
+}
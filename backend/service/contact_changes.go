@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"hub-control-plane/backend/clock"
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/tracing"
+)
+
+// ContactChange is a contact that changed since a sync point. Deleted contacts are the
+// trash copy (deleted_at set) with Deleted true, so clients can drop them.
+type ContactChange struct {
+	*models.ContactEntity
+	Deleted bool `json:"deleted"`
+}
+
+// ContactChanges is the answer to one sync poll. NextSince is the server time the reads
+// started at; passing it as the next since can repeat a change but never skips one.
+type ContactChanges struct {
+	Contacts  []ContactChange `json:"contacts"`
+	Count     int             `json:"count"`
+	NextSince time.Time       `json:"next_since"`
+}
+
+// ListContactChanges returns the user's contacts updated at or after since, and the ones
+// soft-deleted at or after it, oldest change first. Contacts removed without going
+// through the trash (moves, purges of expired trash, user cascades) aren't reported, so
+// clients that haven't synced for longer than the trash retention should resync fully.
+// Reads DynamoDB directly: sync clients need changes as soon as they're written.
+// Flow: Note server time → Query every page of CONTACT# with UpdatedAt filter → Query every page of TRASH# with DeletedAt filter → Merge by change time
+func (s *AppServiceWithCache) ListContactChanges(ctx context.Context, userID string, since time.Time) (*ContactChanges, error) {
+	ctx, span := tracing.Start(ctx, "AppService.ListContactChanges")
+	defer span.End()
+
+	if IsCacheOnly(ctx) {
+		return nil, ErrNotCached
+	}
+
+	// 1. Taken before reading, so anything written during the reads is in the next poll
+	result := &ContactChanges{NextSince: clock.Now().UTC()}
+	pk := fmt.Sprintf("USER#%s", userID)
+	bound := expression.Value(changedSinceBound(since))
+
+	// 2. Live contacts edited (or created or restored) since
+	filter := expression.Name("UpdatedAt").GreaterThanEqual(bound)
+	updated, err := s.queryAllContacts(ctx, pk, "CONTACT#", &filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed contacts: %w", err)
+	}
+	for _, contact := range updated {
+		if !contact.UpdatedAt.Before(since) {
+			result.Contacts = append(result.Contacts, ContactChange{ContactEntity: contact})
+		}
+	}
+
+	// 3. Contacts moved to the trash since
+	filter = expression.Name("DeletedAt").GreaterThanEqual(bound)
+	deleted, err := s.queryAllContacts(ctx, pk, "TRASH#", &filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted contacts: %w", err)
+	}
+	for _, contact := range deleted {
+		if contact.DeletedAt != nil && !contact.DeletedAt.Before(since) {
+			result.Contacts = append(result.Contacts, ContactChange{ContactEntity: contact, Deleted: true})
+		}
+	}
+
+	sort.SliceStable(result.Contacts, func(i, j int) bool {
+		return result.Contacts[i].changedAt().Before(result.Contacts[j].changedAt())
+	})
+	result.Count = len(result.Contacts)
+	return result, nil
+}
+
+// changedAt is when the change happened: the deletion for trash, else the last update
+func (c ContactChange) changedAt() time.Time {
+	if c.Deleted && c.DeletedAt != nil {
+		return *c.DeletedAt
+	}
+	return c.UpdatedAt
+}
+
+// changedSinceBound is a lower bound for the stored timestamp strings of times at or
+// after since. They are RFC3339Nano with trailing zeros trimmed, which doesn't sort
+// reliably within a second ("...05Z" > "...05.1Z"), so the filter only cuts at whole
+// seconds and ListContactChanges compares the decoded times exactly.
+func changedSinceBound(since time.Time) string {
+	return since.UTC().Format("2006-01-02T15:04:05")
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/requestid"
+)
+
+// contactCountUpdate adjusts a user's ContactCount inside a contact write transaction.
+// The existence condition keeps it from creating a stub METADATA item for a user that
+// doesn't exist; if the user is missing the whole transaction is cancelled.
+func contactCountUpdate(userID string, delta int64) repository.TxUpdate {
+	exists := expression.AttributeExists(expression.Name("PK"))
+	return repository.TxUpdate{
+		PK:        fmt.Sprintf("USER#%s", userID),
+		SK:        "METADATA",
+		Update:    expression.Add(expression.Name("ContactCount"), expression.Value(delta)),
+		Condition: &exists,
+	}
+}
+
+// adjustContactCount is contactCountUpdate for batch writes, which DynamoDB can't make
+// transactional: the count is bumped after the items land, so a failure here leaves it off
+// by delta and is only logged.
+func (s *AppServiceWithCache) adjustContactCount(ctx context.Context, userID string, delta int64) {
+	if delta == 0 {
+		return
+	}
+	if _, err := s.repo.Increment(ctx, fmt.Sprintf("USER#%s", userID), "METADATA", "ContactCount", delta, nil); err != nil {
+		requestid.Logf(ctx, "Warning: failed to adjust contact count for user %s by %d: %v", userID, delta, err)
+	}
+	s.dropCachedUser(ctx, userID)
+}
+
+// dropCachedUser removes a user's cache entry after its ContactCount changed. The cached
+// user list is left alone (it would churn on every contact write) and catches up on its
+// next refresh.
+func (s *AppServiceWithCache) dropCachedUser(ctx context.Context, userID string) {
+	if err := s.cache.Del(ctx, fmt.Sprintf("user:%s", userID)); err != nil {
+		requestid.Logf(ctx, "Warning: failed to delete from cache: %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/repository"
+)
+
+// SetContactListIndex makes ListUserContacts read indexName instead of the table. The
+// index must be keyed like the table (PK partition, SK sort key) and is meant to project
+// only what list views show, so a user with very many contacts costs fewer bytes to list.
+// Contacts come back with the projected attributes only, and are cached that way under a
+// key of their own, apart from the full list; GetContactByEmail matches against the same
+// list, so the projection must include Email.
+// An empty name (the default) reads the table.
+func (s *AppServiceWithCache) SetContactListIndex(indexName string) {
+	s.contactListIndex = indexName
+}
+
+// queryContactListIndex reads every page of a user's contacts from the list index
+func (s *AppServiceWithCache) queryContactListIndex(ctx context.Context, pk string) ([]*models.ContactEntity, error) {
+	keyCond := expression.Key("PK").Equal(expression.Value(pk)).
+		And(expression.Key("SK").BeginsWith("CONTACT#"))
+
+	var contacts []*models.ContactEntity
+	var startKey map[string]types.AttributeValue
+	for {
+		var page []*models.ContactEntity
+		next, err := s.repo.QueryIndex(ctx, s.contactListIndex, keyCond, repository.QueryOptions{StartKey: startKey}, &page)
+		if err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, page...)
+		if next == nil {
+			return contacts, nil
+		}
+		startKey = next
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/requestid"
+	"hub-control-plane/backend/tracing"
+)
+
+// ContactSearch is a set of contact filters; empty fields don't filter. All set fields
+// must match. Matching is exact and case-sensitive, as DynamoDB compares it.
+type ContactSearch struct {
+	Company  string `json:"company,omitempty"`  // Company equals
+	Tag      string `json:"tag,omitempty"`      // Tags contains
+	Favorite *bool  `json:"favorite,omitempty"` // IsFavorite equals
+	Query    string `json:"q,omitempty"`        // Name, Email or Company contains
+}
+
+// How SearchContacts reads the user's contacts
+const (
+	SearchFavoritesIndex = "favorites_index" // Only the FAV# index items are read, other filters apply to those
+	SearchFilter         = "filter"          // Every CONTACT# item is read and filtered by DynamoDB
+)
+
+// ContactSearchResult is the contacts matching Filters, and how they were read.
+// Scanned is the items DynamoDB read to find them: far above Count means the filter
+// did the selecting, at the cost of a read for every scanned item.
+type ContactSearchResult struct {
+	Contacts         []*models.ContactEntity `json:"contacts"`
+	Count            int                     `json:"count"`
+	Filters          ContactSearch           `json:"filters"`
+	Access           string                  `json:"access"`
+	Scanned          int                     `json:"scanned"`
+	ConsumedCapacity float64                 `json:"consumed_capacity"`
+}
+
+// condition combines the set filters into one filter expression. ok is false when
+// none is set.
+func (q ContactSearch) condition() (cond expression.ConditionBuilder, ok bool) {
+	var conds []expression.ConditionBuilder
+	if q.Company != "" {
+		conds = append(conds, expression.Name("Company").Equal(expression.Value(q.Company)))
+	}
+	if q.Tag != "" {
+		conds = append(conds, expression.Contains(expression.Name("Tags"), q.Tag))
+	}
+	if q.Favorite != nil {
+		conds = append(conds, expression.Name("IsFavorite").Equal(expression.Value(*q.Favorite)))
+	}
+	if q.Query != "" {
+		conds = append(conds, expression.Or(
+			expression.Contains(expression.Name("Name"), q.Query),
+			expression.Contains(expression.Name("Email"), q.Query),
+			expression.Contains(expression.Name("Company"), q.Query),
+		))
+	}
+
+	switch len(conds) {
+	case 0:
+		return cond, false
+	case 1:
+		return conds[0], true
+	}
+	return expression.And(conds[0], conds[1], conds[2:]...), true
+}
+
+// SearchContacts returns a user's contacts matching every filter in q, uncached.
+//
+// Only favorite=true is index-backed: the query reads the sparse FAV# index items (full
+// copies of favorite contacts) and filters those. Every other combination reads all of
+// the user's CONTACT# items and filters them server-side, which costs reads for every
+// contact, matching or not. Either way the query stays inside the user's partition.
+// Encrypted fields (see ENCRYPTED_FIELDS) never match, since DynamoDB only sees ciphertext.
+// Flow: Build filter → Query FAV# or CONTACT# with the filter → Return with the applied filters
+func (s *AppServiceWithCache) SearchContacts(ctx context.Context, userID string, q ContactSearch) (*ContactSearchResult, error) {
+	ctx, span := tracing.Start(ctx, "AppService.SearchContacts")
+	defer span.End()
+
+	if IsCacheOnly(ctx) {
+		return nil, ErrNotCached
+	}
+
+	pk := fmt.Sprintf("USER#%s", userID)
+	skPrefix, access := "CONTACT#", SearchFilter
+	if q.Favorite != nil && *q.Favorite && s.flagEnabled(FlagFavoritesIndex) {
+		skPrefix, access = "FAV#", SearchFavoritesIndex
+	}
+
+	// The FAV# items are all favorites already, so that condition would only cost CPU
+	filters := q
+	if access == SearchFavoritesIndex {
+		filters.Favorite = nil
+	}
+
+	var filter *expression.ConditionBuilder
+	if cond, ok := filters.condition(); ok {
+		filter = &cond
+	}
+	items, stats, err := s.repo.QueryWithStats(ctx, pk, skPrefix, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search contacts: %w", err)
+	}
+	contacts, err := repository.UnmarshalItems[*models.ContactEntity](items)
+	if err != nil {
+		requestid.Logf(ctx, "Warning: skipped unreadable contacts in search for user %s: %v", userID, err)
+	}
+
+	return &ContactSearchResult{
+		Contacts:         contacts,
+		Count:            len(contacts),
+		Filters:          q,
+		Access:           access,
+		Scanned:          stats.ScannedCount,
+		ConsumedCapacity: stats.ConsumedCapacity,
+	}, nil
+}
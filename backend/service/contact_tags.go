@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"hub-control-plane/backend/clock"
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/requestid"
+	"hub-control-plane/backend/tracing"
+	"hub-control-plane/backend/webhook"
+)
+
+// ErrInvalidTag is returned by RenameTag and RemoveTag for an empty tag, or a rename
+// to the same tag
+var ErrInvalidTag = errors.New("invalid tag")
+
+// TagChangeResult reports how many of a user's contacts a tag change rewrote. Contacts
+// in a transaction that failed (usually because one was edited at the same time) are
+// counted in Failed and left as they were; repeating the change picks them up.
+type TagChangeResult struct {
+	Modified int `json:"modified"`
+	Failed   int `json:"failed"`
+}
+
+// RenameTag replaces tag from with to on every contact of the user that has it. A
+// contact that already has to just loses from.
+func (s *AppServiceWithCache) RenameTag(ctx context.Context, userID, from, to string) (*TagChangeResult, error) {
+	ctx, span := tracing.Start(ctx, "AppService.RenameTag")
+	defer span.End()
+
+	if from == "" || to == "" {
+		return nil, fmt.Errorf("%w: from and to are required", ErrInvalidTag)
+	}
+	if from == to {
+		return nil, fmt.Errorf("%w: %q renamed to itself", ErrInvalidTag, from)
+	}
+
+	return s.changeTag(ctx, userID, from, func(tags []string) []string {
+		renamed := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			if tag == from {
+				tag = to
+			}
+			if !slices.Contains(renamed, tag) {
+				renamed = append(renamed, tag)
+			}
+		}
+		return renamed
+	})
+}
+
+// RemoveTag removes tag from every contact of the user that has it
+func (s *AppServiceWithCache) RemoveTag(ctx context.Context, userID, tag string) (*TagChangeResult, error) {
+	ctx, span := tracing.Start(ctx, "AppService.RemoveTag")
+	defer span.End()
+
+	if tag == "" {
+		return nil, fmt.Errorf("%w: tag is required", ErrInvalidTag)
+	}
+
+	return s.changeTag(ctx, userID, tag, func(tags []string) []string {
+		return slices.DeleteFunc(slices.Clone(tags), func(t string) bool { return t == tag })
+	})
+}
+
+// changeTag rewrites the tags of every contact tagged tag with change.
+// Flow: Query every page of contacts from DB → Chunk into transactions (see SetMaxTransactionItems) → Update tags unless modified since the read, with favorites index copies → Drop contact caches → Invalidate list caches once
+func (s *AppServiceWithCache) changeTag(ctx context.Context, userID, tag string, change func(tags []string) []string) (*TagChangeResult, error) {
+	// 1. Find the affected contacts in DynamoDB, every page; cached lists may be stale
+	pk := fmt.Sprintf("USER#%s", userID)
+	tagged := expression.Contains(expression.Name("Tags"), tag)
+	contacts, err := s.queryAllContacts(ctx, pk, "CONTACT#", &tagged)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contacts: %w", err)
+	}
+
+	result := &TagChangeResult{}
+	var changed []*models.ContactEntity
+
+	// 2. Update in chunks of whole contacts: a favorite takes two items, the contact
+	//    and its FAV# copy, which must land in the same transaction
+	var tx repository.TxItems
+	var chunk []*models.ContactEntity
+	commit := func() {
+		if len(chunk) == 0 {
+			return
+		}
+		if err := s.repo.TransactWrite(ctx, tx); err != nil {
+			requestid.Logf(ctx, "Warning: tag change failed for %d contacts of user %s: %v", len(chunk), userID, err)
+			result.Failed += len(chunk)
+		} else {
+			result.Modified += len(chunk)
+			changed = append(changed, chunk...)
+		}
+		tx, chunk = repository.TxItems{}, nil
+	}
+
+	now := clock.Now()
+	for _, contact := range contacts {
+		if !slices.Contains(contact.Tags, tag) {
+			continue
+		}
+		size := 1
+		if contact.IsFavorite {
+			size = 2
+		}
+		if len(tx.Updates)+len(tx.Puts)+size > s.maxTxItems {
+			commit()
+		}
+
+		seen := expression.Name("UpdatedAt").Equal(expression.Value(contact.UpdatedAt))
+		contact.Tags = change(contact.Tags)
+		contact.UpdatedAt = now
+		tx.Updates = append(tx.Updates, repository.TxUpdate{
+			PK: pk,
+			SK: contact.SK,
+			Update: expression.Set(expression.Name("Tags"), expression.Value(contact.Tags)).
+				Set(expression.Name("UpdatedAt"), expression.Value(now)),
+			Condition: &seen,
+		})
+		if contact.IsFavorite {
+			tx.Puts = append(tx.Puts, models.NewFavoriteContactIndex(contact))
+		}
+		chunk = append(chunk, contact)
+	}
+	commit()
+
+	if len(changed) == 0 {
+		return result, nil
+	}
+
+	// 3. Drop the changed contacts from the cache and notify webhooks
+	for _, contact := range changed {
+		if err := s.cache.Del(ctx, fmt.Sprintf("contact:%s:%s", userID, contact.ID)); err != nil {
+			requestid.Logf(ctx, "Warning: failed to delete from cache: %v", err)
+		}
+		s.webhooks.Dispatch(webhook.NewEvent(webhook.ContactUpdated, userID, contact.ID, contact))
+	}
+
+	// 4. Invalidate list caches once for the whole change
+	if err := s.invalidateUserContactCaches(ctx, userID); err != nil {
+		requestid.Logf(ctx, "Warning: failed to invalidate contact caches: %v", err)
+	}
+
+	requestid.Logf(ctx, "Changed tag %q on %d/%d contacts for user: %s", tag, result.Modified, len(contacts), userID)
+	return result, nil
+}
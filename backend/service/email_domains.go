@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"hub-control-plane/backend/validation"
+)
+
+// SetContactEmailDomains sets the contact email domains allowed for users that don't
+// have their own AllowedEmailDomains. Empty allows any domain.
+func (s *AppServiceWithCache) SetContactEmailDomains(domains []string) {
+	s.emailDomains = domains
+}
+
+// emailDomainAllowlist returns the contact email domains userID may store: the user's
+// own list, else the global one. A missing user gets the global list; the write that
+// follows reports the missing user itself.
+func (s *AppServiceWithCache) emailDomainAllowlist(ctx context.Context, userID string) ([]string, error) {
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return s.emailDomains, nil
+		}
+		return nil, fmt.Errorf("failed to load email domain allowlist: %w", err)
+	}
+	if len(user.AllowedEmailDomains) > 0 {
+		return user.AllowedEmailDomains, nil
+	}
+	return s.emailDomains, nil
+}
+
+// checkContactEmailDomain rejects a contact email outside the user's allowlist with a
+// validation error. Contacts without an email skip the user lookup.
+func (s *AppServiceWithCache) checkContactEmailDomain(ctx context.Context, userID, email string) error {
+	if email == "" {
+		return nil
+	}
+	allowed, err := s.emailDomainAllowlist(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return validation.EmailDomain("email", email, allowed)
+}
+
+// checkUpdatedEmailDomain is checkContactEmailDomain for an update map that sets Email
+func (s *AppServiceWithCache) checkUpdatedEmailDomain(ctx context.Context, userID string, updates map[string]interface{}) error {
+	email, _ := updates["Email"].(string)
+	return s.checkContactEmailDomain(ctx, userID, email)
+}
+
+// validateContactInput runs the create rules for one batch or import row, including
+// the owner's email domain allowlist
+func validateContactInput(in ContactInput, allowedDomains []string) error {
+	if err := validation.CreateContact(in.Name, in.Email); err != nil {
+		return err
+	}
+	return validation.EmailDomain("email", in.Email, allowedDomains)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"hub-control-plane/backend/enrichment"
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/requestid"
+)
+
+// DefaultEnrichmentQueueSize is how many contacts can wait for enrichment; contacts
+// created while the queue is full are not enriched
+const DefaultEnrichmentQueueSize = 1000
+
+// enrichmentTask is one contact waiting for enrichment
+type enrichmentTask struct {
+	ctx       context.Context // Detached from the request; carries its ID and tenant
+	userID    string
+	contactID string
+	email     string
+}
+
+// enrichmentPool runs enrichment tasks on a fixed pool of workers that share one rate limit
+type enrichmentPool struct {
+	provider enrichment.Enricher
+	tasks    chan enrichmentTask
+	limit    *time.Ticker
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// SetEnricher enables asynchronous enrichment of contacts created with an email but no
+// company or job title. Creating a contact (one at a time, in a batch or by import) only
+// queues it; a pool of workers calls enricher, at most ratePerSecond times a second
+// between them, and fills in whichever of the two fields are still empty. A contact
+// edited since it was read for enrichment is left alone. Call StopEnrichment on shutdown.
+func (s *AppServiceWithCache) SetEnricher(enricher enrichment.Enricher, workers, ratePerSecond int) {
+	workers, ratePerSecond = max(workers, 1), max(ratePerSecond, 1)
+	e := &enrichmentPool{
+		provider: enricher,
+		tasks:    make(chan enrichmentTask, DefaultEnrichmentQueueSize),
+		limit:    time.NewTicker(time.Second / time.Duration(ratePerSecond)),
+		stop:     make(chan struct{}),
+	}
+	for i := 0; i < workers; i++ {
+		e.wg.Add(1)
+		go func() {
+			defer e.wg.Done()
+			for {
+				var task enrichmentTask
+				select {
+				case <-e.stop:
+					return
+				case task = <-e.tasks:
+				}
+				select {
+				case <-e.stop:
+					return
+				case <-e.limit.C:
+				}
+				s.enrichContact(task)
+			}
+		}()
+	}
+	s.enrichments = e
+}
+
+// StopEnrichment stops the workers, waiting for lookups already in flight. Contacts
+// still queued are not enriched: at the rate limit, draining the queue could outlast
+// any shutdown grace period.
+func (s *AppServiceWithCache) StopEnrichment() {
+	e := s.enrichments
+	if e == nil {
+		return
+	}
+	e.stopOnce.Do(func() { close(e.stop) })
+	e.wg.Wait()
+	e.limit.Stop()
+}
+
+// queueEnrichment hands a newly created contact to the enrichment workers if it only
+// has an email to go on. It never blocks: with the queue full the contact is skipped.
+func (s *AppServiceWithCache) queueEnrichment(ctx context.Context, contact *models.ContactEntity) {
+	e := s.enrichments
+	if e == nil || contact.Email == "" || contact.Company != "" || contact.JobTitle != "" {
+		return
+	}
+
+	select {
+	case e.tasks <- enrichmentTask{ctx: detach(ctx), userID: contact.UserID, contactID: contact.ID, email: contact.Email}:
+	default:
+		requestid.Logf(ctx, "Warning: enrichment queue full, not enriching contact %s", contact.ID)
+	}
+}
+
+// enrichContact looks one contact up and applies what the provider found
+func (s *AppServiceWithCache) enrichContact(task enrichmentTask) {
+	ctx, cancel := context.WithTimeout(task.ctx, 10*time.Second)
+	defer cancel()
+
+	if err := s.applyEnrichment(ctx, task); err != nil {
+		requestid.Logf(ctx, "Warning: failed to enrich contact %s: %v", task.contactID, err)
+	}
+}
+
+// applyEnrichment fills the contact's empty company and job title from the enricher.
+// Flow: Enrich email → Re-read contact → Update empty fields unless modified since the read
+func (s *AppServiceWithCache) applyEnrichment(ctx context.Context, task enrichmentTask) error {
+	result, err := s.enrichments.provider.Enrich(ctx, task.email)
+	if err != nil {
+		return err
+	}
+	if result.Empty() {
+		return nil
+	}
+
+	// The contact may have been edited or deleted while it was queued
+	contact := &models.ContactEntity{}
+	pk, sk := fmt.Sprintf("USER#%s", task.userID), fmt.Sprintf("CONTACT#%s", task.contactID)
+	if err := s.repo.Get(ctx, pk, sk, contact); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get contact: %w", err)
+	}
+	if contact.Email != task.email {
+		return nil
+	}
+
+	updates := make(map[string]interface{})
+	if contact.Company == "" && result.Company != "" {
+		updates["Company"] = result.Company
+	}
+	if contact.JobTitle == "" && result.JobTitle != "" {
+		updates["JobTitle"] = result.JobTitle
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	_, err = s.updateContact(ctx, task.userID, task.contactID, updates, contact.UpdatedAt)
+	if errors.Is(err, ErrPreconditionFailed) {
+		requestid.Logf(ctx, "Contact %s changed during enrichment, not applying it", task.contactID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	requestid.Logf(ctx, "Enriched contact %s with %d fields", task.contactID, len(updates))
+	return nil
+}
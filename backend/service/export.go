@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"hub-control-plane/backend/clock"
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/tracing"
+)
+
+// UserExport is everything stored about one user, for data-export (GDPR access)
+// requests. Contacts and soft-deleted contacts are pagers, so the export is streamed a
+// DynamoDB page at a time and never buffered whole.
+type UserExport struct {
+	ExportedAt      time.Time
+	User            *models.UserEntity
+	Contacts        *repository.QueryPager // Live contacts (CONTACT#)
+	DeletedContacts *repository.QueryPager // Contacts in the trash (TRASH#), until purged
+	Audit           []*models.AuditEntry   // The user's own audit history
+}
+
+// ExportUserData gathers a user's data package. Everything is read straight from
+// DynamoDB: an export must be complete, and the cache may hold a partial or stale view.
+// The favorites index (FAV#) is left out, since it only copies live contacts.
+// Flow: Get user → Open CONTACT# and TRASH# pagers → List audit entries
+func (s *AppServiceWithCache) ExportUserData(ctx context.Context, userID string) (*UserExport, error) {
+	ctx, span := tracing.Start(ctx, "AppService.ExportUserData")
+	defer span.End()
+
+	if IsCacheOnly(ctx) {
+		return nil, ErrNotCached
+	}
+
+	pk := fmt.Sprintf("USER#%s", userID)
+	export := &UserExport{ExportedAt: clock.Now().UTC(), User: &models.UserEntity{}}
+	if err := s.repo.Get(ctx, pk, "METADATA", export.User); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to export user: %w", err)
+	}
+
+	var err error
+	if export.Contacts, err = s.repo.QueryPages(ctx, pk, "CONTACT#"); err != nil {
+		return nil, fmt.Errorf("failed to export contacts: %w", err)
+	}
+	if export.DeletedContacts, err = s.repo.QueryPages(ctx, pk, "TRASH#"); err != nil {
+		return nil, fmt.Errorf("failed to export deleted contacts: %w", err)
+	}
+	if export.Audit, err = s.ListAuditEntries(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
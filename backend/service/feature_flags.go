@@ -0,0 +1,32 @@
+package service
+
+// Optional behaviours that can be switched per environment without a redeploy
+const (
+	FlagStaleWhileRevalidate = "stale_while_revalidate" // serve stale entries while refreshing (see SetStaleWhileRevalidate)
+	FlagWriteThrough         = "write_through"          // honour WriteThrough list cache strategies
+	FlagFavoritesIndex       = "favorites_index"        // read favorites from the FAV# index items
+)
+
+// DefaultFlags are the flag defaults before FEATURE_FLAGS and runtime overrides. All
+// are on, matching the behaviour before the flags existed.
+var DefaultFlags = map[string]bool{
+	FlagStaleWhileRevalidate: true,
+	FlagWriteThrough:         true,
+	FlagFavoritesIndex:       true,
+}
+
+// FlagChecker reports whether a feature flag is on (see featureflag.Flags)
+type FlagChecker interface {
+	IsEnabled(flag string) bool
+}
+
+// SetFlags sets where optional behaviours check their feature flags. Without one every
+// flag counts as on, so each behaviour is governed only by its own setting.
+func (s *AppServiceWithCache) SetFlags(flags FlagChecker) {
+	s.flags = flags
+}
+
+// flagEnabled reports whether flag is on
+func (s *AppServiceWithCache) flagEnabled(flag string) bool {
+	return s.flags == nil || s.flags.IsEnabled(flag)
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/requestid"
+	"hub-control-plane/backend/tracing"
+)
+
+// reindexBatchSize is how many items each Reindex scan page reads
+const reindexBatchSize = 100
+
+// ReindexResult reports what a Reindex run did
+type ReindexResult struct {
+	Scanned int `json:"scanned"` // Items missing at least one index attribute
+	Fixed   int `json:"fixed"`   // Items that had the missing attributes written
+	Skipped int `json:"skipped"` // Items whose keys match no known entity pattern
+
+	Backfilled int `json:"backfilled"` // Counts and index items users were given (see backfillUser)
+}
+
+// Reindex backfills GSI1PK/GSI1SK/EntityType on items that lack them (e.g. written by the
+// legacy non-generic repository), so they show up in QueryByEntityType. Values are derived
+// from the PK/SK pattern and only written where missing, so a rerun fixes nothing twice.
+// Users still carrying the old USER#<id> sort key are rekeyed to <created>#USER#<id> so
+// ListUsersByCreatedDate finds them (this goes through Update, which bumps UpdatedAt).
+// Every user is then checked by backfillUser.
+// Flow: Scan (filtered, in batches) → Derive keys per item → SetIfMissing / rekey → Scan users → Backfill → Report counts
+func (s *AppServiceWithCache) Reindex(ctx context.Context) (*ReindexResult, error) {
+	ctx, span := tracing.Start(ctx, "AppService.Reindex")
+	defer span.End()
+
+	missing := expression.AttributeNotExists(expression.Name("GSI1PK")).
+		Or(expression.AttributeNotExists(expression.Name("GSI1SK"))).
+		Or(expression.AttributeNotExists(expression.Name("EntityType"))).
+		Or(expression.Name("EntityType").Equal(expression.Value("USER")).
+			And(expression.Name("GSI1SK").BeginsWith("USER#")))
+
+	result := &ReindexResult{}
+	batch := 0
+	err := s.repo.Scan(ctx, missing, reindexBatchSize, func(items []map[string]types.AttributeValue) error {
+		batch++
+		for _, item := range items {
+			result.Scanned++
+			pk, sk := stringAttr(item, "PK"), stringAttr(item, "SK")
+
+			// 1. Work out what the item should be indexed as
+			keys, ok := models.IndexKeysFor(pk, sk, timeAttr(item, "CreatedAt"))
+			if !ok {
+				requestid.Logf(ctx, "Reindex: skipping %s/%s, unknown key pattern", pk, sk)
+				result.Skipped++
+				continue
+			}
+
+			// 2. Fill in only what's missing, or replace a pre-date-range user sort key
+			var err error
+			if strings.HasPrefix(stringAttr(item, "GSI1SK"), "USER#") {
+				err = s.repo.Update(ctx, pk, sk, map[string]interface{}{"GSI1SK": keys.GSI1SK})
+			} else {
+				err = s.repo.SetIfMissing(ctx, pk, sk, map[string]interface{}{
+					"EntityType": keys.EntityType,
+					"GSI1PK":     keys.GSI1PK,
+					"GSI1SK":     keys.GSI1SK,
+				})
+			}
+			if errors.Is(err, repository.ErrNotFound) {
+				continue // deleted since the scan read it
+			}
+			if err != nil {
+				return fmt.Errorf("failed to reindex %s/%s: %w", pk, sk, err)
+			}
+			result.Fixed++
+		}
+		requestid.Logf(ctx, "Reindex: batch %d done (%d fixed so far)", batch, result.Fixed)
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// 3. Backfill what users written before later features lack
+	users := expression.Name("EntityType").Equal(expression.Value("USER"))
+	err = s.repo.Scan(ctx, users, reindexBatchSize, func(items []map[string]types.AttributeValue) error {
+		for _, item := range items {
+			if err := s.backfillUser(ctx, item, result); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// 4. Newly indexed users may belong in the cached user list
+	if result.Fixed > 0 {
+		if err := s.invalidateUserListCache(ctx); err != nil {
+			requestid.Logf(ctx, "Warning: failed to invalidate user list cache: %v", err)
+		}
+	}
+
+	requestid.Logf(ctx, "Reindex complete: scanned=%d fixed=%d skipped=%d backfilled=%d", result.Scanned, result.Fixed, result.Skipped, result.Backfilled)
+	return result, nil
+}
+
+// backfillUser gives a user what it predates:
+//   - ContactCount, recounted when missing (written before contact writes kept it) or
+//     driven below zero by deletes since. The count is set outright (bumping UpdatedAt),
+//     so a contact written while it is taken can leave it off until the next run.
+//   - The FAV# index item of each favorite contact that lacks one (favorited before the
+//     index existed, or whose index write failed). Existing items are left alone.
+//   - The EMAIL# sentinel of its email, if none exists, so GetUserByEmail finds it and
+//     no new user can take the address. A sentinel held by another user is logged and
+//     left alone; the two users share an email and one of them has to change it.
+func (s *AppServiceWithCache) backfillUser(ctx context.Context, item map[string]types.AttributeValue, result *ReindexResult) error {
+	pk, sk := stringAttr(item, "PK"), stringAttr(item, "SK")
+
+	if count, ok := intAttr(item, "ContactCount"); !ok || count < 0 {
+		contacts, err := s.repo.Count(ctx, pk, "CONTACT#")
+		if err != nil {
+			return fmt.Errorf("failed to count contacts of %s: %w", pk, err)
+		}
+		err = s.repo.Update(ctx, pk, sk, map[string]interface{}{"ContactCount": contacts})
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil // deleted since the scan read it
+		}
+		if err != nil {
+			return fmt.Errorf("failed to backfill ContactCount of %s: %w", pk, err)
+		}
+		s.dropCachedUser(ctx, strings.TrimPrefix(pk, "USER#"))
+		result.Backfilled++
+	}
+
+	if email := stringAttr(item, "Email"); email != "" {
+		userID := strings.TrimPrefix(pk, "USER#")
+		holder := &models.UserEmailIndex{}
+		created, err := s.repo.PutIfAbsentOrGet(ctx, models.NewUserEmailIndex(email, userID), holder)
+		if err != nil {
+			return fmt.Errorf("failed to backfill email sentinel of %s: %w", pk, err)
+		}
+		if created {
+			result.Backfilled++
+		} else if holder.UserID != userID {
+			requestid.Logf(ctx, "Warning: email of %s is already held by user %s", pk, holder.UserID)
+		}
+	}
+
+	favorite := expression.Name("IsFavorite").Equal(expression.Value(true))
+	items, _, err := s.repo.QueryWithStats(ctx, pk, "CONTACT#", &favorite)
+	if err != nil {
+		return fmt.Errorf("failed to query favorites of %s: %w", pk, err)
+	}
+	contacts, err := repository.UnmarshalItems[*models.ContactEntity](items)
+	if err != nil {
+		requestid.Logf(ctx, "Warning: skipped unreadable contacts of %s: %v", pk, err)
+	}
+	indexed := 0
+	for _, contact := range contacts {
+		if !contact.IsFavorite {
+			continue
+		}
+		created, err := s.repo.PutIfAbsentOrGet(ctx, models.NewFavoriteContactIndex(contact), &models.ContactEntity{})
+		if err != nil {
+			return fmt.Errorf("failed to backfill favorites index of %s/%s: %w", pk, contact.SK, err)
+		}
+		if created {
+			indexed++
+		}
+	}
+	if indexed > 0 {
+		if err := s.invalidateUserContactCaches(ctx, strings.TrimPrefix(pk, "USER#")); err != nil {
+			requestid.Logf(ctx, "Warning: failed to invalidate contact caches: %v", err)
+		}
+		result.Backfilled += indexed
+	}
+	return nil
+}
+
+// stringAttr returns a string attribute of a raw item, or "" if absent or not a string
+func stringAttr(item map[string]types.AttributeValue, name string) string {
+	if s, ok := item[name].(*types.AttributeValueMemberS); ok {
+		return s.Value
+	}
+	return ""
+}
+
+// timeAttr parses a timestamp attribute of a raw item, or returns the zero time
+func timeAttr(item map[string]types.AttributeValue, name string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, stringAttr(item, name))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// intAttr parses a number attribute of a raw item; ok is false if absent or not a number
+func intAttr(item map[string]types.AttributeValue, name string) (n int64, ok bool) {
+	attr, isNumber := item[name].(*types.AttributeValueMemberN)
+	if !isNumber {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(attr.Value, 10, 64)
+	return n, err == nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/pagination"
+	"hub-control-plane/backend/tracing"
+	"hub-control-plane/backend/validation"
+)
+
+// SearchPage is one page of Search: the matching users come before the matching contacts,
+// so a page can hold users, contacts or (at the switch) both. NextCursor is empty on the
+// last page.
+type SearchPage struct {
+	Users      []*models.UserEntity    `json:"users"`
+	Contacts   []*models.ContactEntity `json:"contacts"`
+	NextCursor string                  `json:"next_cursor"`
+}
+
+// Where a Search cursor resumes
+const (
+	searchPhaseUsers    = "users"
+	searchPhaseContacts = "contacts"
+)
+
+// Search finds users whose email, first or last name contains q, then contacts of any
+// user whose name, email or company contains q. Matching is case-sensitive, like the
+// contact q filter DynamoDB applies. Users are matched in memory against the cached user
+// list; contacts page through GSI1 with ListAllContacts, so they cost a read of every
+// contact scanned, matching or not.
+// Flow: Validate q → Resume users (offset into the user list) → Fill the rest of the page from contacts
+func (s *AppServiceWithCache) Search(ctx context.Context, q string, limit int, cursor string) (*SearchPage, error) {
+	ctx, span := tracing.Start(ctx, "AppService.Search")
+	defer span.End()
+
+	if strings.TrimSpace(q) == "" {
+		return nil, &validation.FieldError{Field: "q", Message: "must not be empty"}
+	}
+	limit = pagination.ClampLimit(limit)
+	phase, offset, contactCursor, err := decodeSearchCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &SearchPage{}
+
+	// 1. Users, resumed by offset into the (ordered) user list
+	if phase == searchPhaseUsers {
+		users, err := s.ListAllUsers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var matched []*models.UserEntity
+		for _, user := range users {
+			if strings.Contains(user.Email, q) || strings.Contains(user.FirstName, q) || strings.Contains(user.LastName, q) {
+				matched = append(matched, user)
+			}
+		}
+		if offset > len(matched) {
+			offset = len(matched)
+		}
+		end := min(offset+limit, len(matched))
+		page.Users = matched[offset:end]
+		if end < len(matched) {
+			page.NextCursor = encodeSearchCursor(searchPhaseUsers, end, "")
+			return page, nil
+		}
+	}
+
+	// 2. Contacts fill the rest of the page. The q filter applies after each read, so
+	// short pages are followed until the page is full or the index is exhausted.
+	for remaining := limit - len(page.Users); remaining > 0; remaining = limit - len(page.Users) - len(page.Contacts) {
+		contacts, err := s.ListAllContacts(ctx, ContactListOptions{
+			Limit:  remaining,
+			Cursor: contactCursor,
+			Filter: ContactSearch{Query: q},
+		})
+		if err != nil {
+			return nil, err
+		}
+		page.Contacts = append(page.Contacts, contacts.Contacts...)
+		contactCursor = contacts.NextCursor
+		if contactCursor == "" {
+			return page, nil
+		}
+	}
+	page.NextCursor = encodeSearchCursor(searchPhaseContacts, 0, contactCursor)
+	return page, nil
+}
+
+// encodeSearchCursor signs a Search resume point with the list cursor machinery
+func encodeSearchCursor(phase string, offset int, contactCursor string) string {
+	return pagination.EncodeCursor(map[string]types.AttributeValue{
+		"Phase":    &types.AttributeValueMemberS{Value: phase},
+		"Offset":   &types.AttributeValueMemberN{Value: strconv.Itoa(offset)},
+		"Contacts": &types.AttributeValueMemberS{Value: contactCursor},
+	})
+}
+
+// decodeSearchCursor reverses encodeSearchCursor; an empty cursor starts at the first user
+func decodeSearchCursor(cursor string) (phase string, offset int, contactCursor string, err error) {
+	key, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return "", 0, "", err
+	}
+	if key == nil {
+		return searchPhaseUsers, 0, "", nil
+	}
+
+	p, ok1 := key["Phase"].(*types.AttributeValueMemberS)
+	o, ok2 := key["Offset"].(*types.AttributeValueMemberN)
+	c, ok3 := key["Contacts"].(*types.AttributeValueMemberS)
+	if !ok1 || !ok2 || !ok3 || (p.Value != searchPhaseUsers && p.Value != searchPhaseContacts) {
+		return "", 0, "", pagination.ErrInvalidCursor
+	}
+	offset, err = strconv.Atoi(o.Value)
+	if err != nil || offset < 0 {
+		return "", 0, "", pagination.ErrInvalidCursor
+	}
+	return p.Value, offset, c.Value, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"hub-control-plane/backend/clock"
+	"hub-control-plane/backend/requestid"
+)
+
+// staleEntry is how keys under stale-while-revalidate are stored: the cached value plus
+// the soft expiry after which reads still serve it but trigger a background refresh.
+// Redis holds the entry for soft TTL + stale window (the hard TTL). The entry is written
+// with the same codec as its value, so a JSON value stays embedded as JSON.
+type staleEntry struct {
+	SoftExpiresAt time.Time       `json:"soft_expires_at"`
+	Value         json.RawMessage `json:"value"`
+}
+
+// SetStaleWhileRevalidate keeps user:<id> and contacts:all:user:<id> entries for window
+// past their normal TTL. In that window GetUser and ListUserContacts still answer from
+// the cache but refresh the entry from DynamoDB in the background (one refresh per key
+// at a time), so hot keys never fall into a synchronous miss. Zero turns it off.
+func (s *AppServiceWithCache) SetStaleWhileRevalidate(window time.Duration) {
+	s.staleWindow = window
+}
+
+// staleWhileRevalidate reports whether new entries are written with a stale window.
+// Entries already written that way keep being unwrapped on read when the flag goes off.
+func (s *AppServiceWithCache) staleWhileRevalidate() bool {
+	return s.staleWindow > 0 && s.flagEnabled(FlagStaleWhileRevalidate)
+}
+
+// staleWhileRevalidateKey reports whether key is one of the entries kept past its TTL
+func staleWhileRevalidateKey(key string) bool {
+	return strings.HasPrefix(key, "user:") || strings.HasPrefix(key, userContactListKey(contactViewAll, "")) ||
+		strings.HasPrefix(key, userContactListKey(contactViewProjected, ""))
+}
+
+// cacheSet stores data under key for ttl, wrapped in a staleEntry when key is served
+// stale-while-revalidate
+func (s *AppServiceWithCache) cacheSet(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	if !s.staleWhileRevalidate() || !staleWhileRevalidateKey(key) {
+		return s.cache.Set(ctx, key, data, ttl)
+	}
+
+	wrapped, err := s.encodeCached(staleEntry{SoftExpiresAt: clock.Now().Add(ttl), Value: data})
+	if err != nil {
+		return err
+	}
+	return s.cache.Set(ctx, key, wrapped, ttl+s.staleWindow)
+}
+
+// cacheGet is cache.Get that unwraps a staleEntry and reports whether it is past its
+// soft expiry. Plain values (written before the window was turned on) are never stale.
+func (s *AppServiceWithCache) cacheGet(ctx context.Context, key string) (data []byte, stale bool, err error) {
+	data, err = s.cache.Get(ctx, key)
+	if err != nil || !staleWhileRevalidateKey(key) {
+		return data, false, err
+	}
+
+	var entry staleEntry
+	if decodeCached(data, &entry) != nil || entry.SoftExpiresAt.IsZero() || entry.Value == nil {
+		return data, false, nil
+	}
+	return entry.Value, clock.Now().After(entry.SoftExpiresAt), nil
+}
+
+// revalidate refreshes a stale key in the background. Concurrent stale reads of the same
+// key share one refresh instead of stampeding DynamoDB; the caller never waits on it.
+func (s *AppServiceWithCache) revalidate(ctx context.Context, key string, refresh func(ctx context.Context) error) {
+	refreshCtx := detach(ctx)
+	s.refreshes.DoChan(key, func() (interface{}, error) {
+		refreshCtx, cancel := context.WithTimeout(refreshCtx, 5*time.Second)
+		defer cancel()
+
+		requestid.Logf(refreshCtx, "Cache STALE for %s, refreshing", key)
+		if err := refresh(refreshCtx); err != nil {
+			requestid.Logf(refreshCtx, "Warning: background refresh of %s failed: %v", key, err)
+			return nil, err
+		}
+		return nil, nil
+	})
+}
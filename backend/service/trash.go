@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"hub-control-plane/backend/clock"
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/requestid"
+	"hub-control-plane/backend/tracing"
+	"hub-control-plane/backend/webhook"
+)
+
+// DefaultTrashRetention is how long a deleted contact stays restorable by default
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// ErrRetentionExpired is returned when restoring a contact deleted longer ago than the retention
+var ErrRetentionExpired = errors.New("contact was deleted too long ago to restore")
+
+// SetTrashRetention sets how long soft-deleted contacts can be restored (0 keeps the default)
+func (s *AppServiceWithCache) SetTrashRetention(retention time.Duration) {
+	if retention > 0 {
+		s.trashRetention = retention
+	}
+}
+
+// ListDeletedContacts returns a user's soft-deleted contacts (the trash view).
+// Read straight from DynamoDB: support uses it rarely and needs it current.
+// Flow: Query every page of TRASH# items with DeletedAt set → Return
+func (s *AppServiceWithCache) ListDeletedContacts(ctx context.Context, userID string) ([]*models.ContactEntity, error) {
+	ctx, span := tracing.Start(ctx, "AppService.ListDeletedContacts")
+	defer span.End()
+
+	pk := fmt.Sprintf("USER#%s", userID)
+	filter := expression.AttributeExists(expression.Name("DeletedAt"))
+	contacts, err := s.queryAllContacts(ctx, pk, "TRASH#", &filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted contacts: %w", err)
+	}
+
+	return contacts, nil
+}
+
+// RestoreContact brings a soft-deleted contact back within the trash retention.
+// Restoring over a contact that has since been recreated with the same ID replaces it.
+// Flow: Load TRASH# item → Check retention → Transaction (put CONTACT# (+ FAV#), delete TRASH#, bump ContactCount) → Cache → Invalidate list caches
+func (s *AppServiceWithCache) RestoreContact(ctx context.Context, userID, contactID string) (*models.ContactEntity, error) {
+	ctx, span := tracing.Start(ctx, "AppService.RestoreContact")
+	defer span.End()
+
+	pk := fmt.Sprintf("USER#%s", userID)
+	trashSK := fmt.Sprintf("TRASH#%s", contactID)
+
+	// 1. Load the trash item
+	trash := &models.ContactEntity{}
+	if err := s.repo.Get(ctx, pk, trashSK, trash); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("deleted contact not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to restore contact: %w", err)
+	}
+
+	// 2. Past the retention it's waiting to be purged, not restored
+	if trash.DeletedAt != nil && clock.Now().Sub(*trash.DeletedAt) > s.trashRetention {
+		return nil, ErrRetentionExpired
+	}
+
+	// 3. Put the contact (and its favorites index item) back and empty the trash slot
+	contact := models.RestoredContact(trash)
+	contact.UpdatedAt = clock.Now() // so sync clients (ListContactChanges) see it come back
+	puts := []repository.BaseModel{contact}
+	if contact.IsFavorite {
+		puts = append(puts, models.NewFavoriteContactIndex(contact))
+	}
+	tx := repository.TxItems{
+		Puts:    puts,
+		Deletes: []map[string]string{{"PK": pk, "SK": trashSK}},
+		Updates: []repository.TxUpdate{contactCountUpdate(userID, 1)},
+	}
+	if err := s.repo.TransactWrite(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to restore contact: %w", err)
+	}
+	s.dropCachedUser(ctx, userID)
+
+	// 4. Cache it and drop the list caches it's missing from
+	if err := s.cacheContact(ctx, contact); err != nil {
+		requestid.Logf(ctx, "Warning: failed to cache restored contact: %v", err)
+	}
+	if err := s.invalidateUserContactCaches(ctx, userID); err != nil {
+		requestid.Logf(ctx, "Warning: failed to invalidate contact caches: %v", err)
+	}
+
+	// 5. Notify webhooks
+	s.webhooks.Dispatch(webhook.NewEvent(webhook.ContactRestored, userID, contactID, contact))
+
+	requestid.Logf(ctx, "Restored contact: %s for user: %s", contactID, userID)
+	return contact, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"hub-control-plane/backend/clock"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/requestid"
+	"hub-control-plane/backend/tracing"
+)
+
+// trashPurgeLockKey is the Redis key replicas contend on before purging
+const trashPurgeLockKey = "lock:trash-purge"
+
+// trashPurgeBatchSize is the scan page size; each page is deleted with one BatchWrite
+const trashPurgeBatchSize = 100
+
+// PurgeExpiredTrash hard-deletes soft-deleted contacts whose DeletedAt is older than
+// the trash retention and returns how many were removed. DeletedAt is compared as a
+// UTC RFC 3339 string, which orders correctly at the granularity retention works at.
+// Flow: Scan for expired TRASH# items → Batch delete each page → Log the count
+func (s *AppServiceWithCache) PurgeExpiredTrash(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "AppService.PurgeExpiredTrash")
+	defer span.End()
+
+	cutoff := clock.Now().Add(-s.trashRetention).UTC().Format(time.RFC3339Nano)
+	filter := expression.Name("EntityType").Equal(expression.Value("CONTACT_TRASH")).
+		And(expression.Name("DeletedAt").LessThan(expression.Value(cutoff)))
+
+	purged := 0
+	err := s.repo.Scan(ctx, filter, trashPurgeBatchSize, func(items []map[string]types.AttributeValue) error {
+		keys := make([]map[string]string, 0, len(items))
+		for _, item := range items {
+			keys = append(keys, map[string]string{"PK": stringAttr(item, "PK"), "SK": stringAttr(item, "SK")})
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+		if err := s.repo.BatchWrite(ctx, nil, keys); err != nil {
+			return err
+		}
+		purged += len(keys)
+		return nil
+	})
+	if err != nil {
+		return purged, fmt.Errorf("failed to purge trash after %d items: %w", purged, err)
+	}
+
+	requestid.Logf(ctx, "Trash purge: removed %d contacts deleted before %s", purged, cutoff)
+	return purged, nil
+}
+
+// RunTrashPurge calls PurgeExpiredTrash every interval until ctx is cancelled. Each run
+// first takes a distributed lock (held for at most interval), so with several replicas
+// only one purges at a time and the others skip that tick.
+func (s *AppServiceWithCache) RunTrashPurge(ctx context.Context, locker repository.Locker, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.purgeTrashOnce(ctx, locker, interval)
+		}
+	}
+}
+
+// purgeTrashOnce runs one purge if this replica wins the lock
+func (s *AppServiceWithCache) purgeTrashOnce(ctx context.Context, locker repository.Locker, lockTTL time.Duration) {
+	unlock, ok, err := locker.TryLock(ctx, trashPurgeLockKey, lockTTL)
+	if err != nil {
+		log.Printf("Warning: trash purge skipped: %v", err)
+		return
+	}
+	if !ok {
+		return // another replica is purging
+	}
+	defer func() {
+		if err := unlock(context.Background()); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}()
+
+	if _, err := s.PurgeExpiredTrash(ctx); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
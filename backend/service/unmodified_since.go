@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/repository"
+)
+
+// ErrPreconditionFailed is returned by the IfUnmodifiedSince updates when the item
+// changed after the caller's timestamp; nothing was written
+var ErrPreconditionFailed = errors.New("resource was modified since the given time")
+
+// UpdateUserIfUnmodifiedSince is UpdateUser that only applies if the user's UpdatedAt is
+// not after since (compared at one-second resolution, as HTTP dates are). The check and
+// the write are one conditional UpdateItem, so a concurrent update can't slip between them.
+// A zero since updates unconditionally.
+func (s *AppServiceWithCache) UpdateUserIfUnmodifiedSince(ctx context.Context, userID string, updates map[string]interface{}, since time.Time) (*models.UserEntity, error) {
+	return s.updateUser(ctx, userID, updates, since)
+}
+
+// UpdateContactIfUnmodifiedSince is UpdateUserIfUnmodifiedSince for a contact
+func (s *AppServiceWithCache) UpdateContactIfUnmodifiedSince(ctx context.Context, userID, contactID string, updates map[string]interface{}, since time.Time) (*models.ContactEntity, error) {
+	return s.updateContact(ctx, userID, contactID, updates, since)
+}
+
+// applyUpdate writes updates to an existing item, unconditionally when since is zero
+func (s *AppServiceWithCache) applyUpdate(ctx context.Context, pk, sk string, updates map[string]interface{}, since time.Time) error {
+	if since.IsZero() {
+		return s.repo.Update(ctx, pk, sk, updates)
+	}
+	err := s.repo.UpdateIfUnmodifiedSince(ctx, pk, sk, updates, since)
+	if errors.Is(err, repository.ErrConditionFailed) {
+		return ErrPreconditionFailed
+	}
+	return err
+}
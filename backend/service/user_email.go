@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"hub-control-plane/backend/models"
+	"hub-control-plane/backend/repository"
+	"hub-control-plane/backend/requestid"
+	"hub-control-plane/backend/tracing"
+)
+
+// ErrEmailTaken is returned when another user already holds the email (case-insensitive)
+var ErrEmailTaken = errors.New("user with this email already exists")
+
+// GetUserByEmail returns the user holding email (case-insensitive) with a direct Get on
+// its EMAIL# sentinel instead of scanning users. Users written before the sentinels
+// existed are found once Reindex has backfilled theirs.
+// Flow: Get EMAIL#<email> sentinel → GetUser (cached)
+func (s *AppServiceWithCache) GetUserByEmail(ctx context.Context, email string) (*models.UserEntity, error) {
+	ctx, span := tracing.Start(ctx, "AppService.GetUserByEmail")
+	defer span.End()
+
+	index := &models.UserEmailIndex{}
+	if err := s.repo.Get(ctx, models.UserEmailPK(email), "METADATA", index); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to look up user by email: %w", err)
+	}
+	return s.GetUser(ctx, index.UserID)
+}
+
+// userEmailKey returns the key of email's sentinel if it belongs to userID. ok is false
+// when there is none or it reserves the email for someone else, so cleanup never
+// releases another user's email.
+func (s *AppServiceWithCache) userEmailKey(ctx context.Context, email, userID string) (key map[string]string, ok bool, err error) {
+	pk := models.UserEmailPK(email)
+	index := &models.UserEmailIndex{}
+	if err := s.repo.Get(ctx, pk, "METADATA", index); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to load email sentinel: %w", err)
+	}
+	return map[string]string{"PK": pk, "SK": "METADATA"}, index.UserID == userID, nil
+}
+
+// releaseUserEmail deletes email's sentinel if userID holds it. Failures are logged: a
+// leftover sentinel only keeps the email reserved, it never lets a duplicate in.
+func (s *AppServiceWithCache) releaseUserEmail(ctx context.Context, email, userID string) {
+	key, ok, err := s.userEmailKey(ctx, email, userID)
+	if err == nil && ok {
+		_, err = s.repo.DeleteIfExists(ctx, key["PK"], key["SK"])
+	}
+	if err != nil {
+		requestid.Logf(ctx, "Warning: failed to release email sentinel for user %s: %v", userID, err)
+	}
+}
+
+// reserveChangedEmail reserves the email an update sets, if it differs (ignoring case)
+// from the user's current one. The returned done must be called with whether the update
+// was applied: it then releases the old email, otherwise the new one.
+func (s *AppServiceWithCache) reserveChangedEmail(ctx context.Context, userID string, updates map[string]interface{}) (done func(ctx context.Context, applied bool), err error) {
+	noop := func(context.Context, bool) {}
+	email, ok := updates["Email"].(string)
+	if !ok {
+		return noop, nil
+	}
+
+	current := &models.UserEntity{}
+	if err := s.repo.Get(ctx, fmt.Sprintf("USER#%s", userID), "METADATA", current); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	if models.UserEmailPK(email) == models.UserEmailPK(current.Email) {
+		return noop, nil
+	}
+
+	if err := s.repo.PutIfNotExists(ctx, models.NewUserEmailIndex(email, userID)); err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return nil, ErrEmailTaken
+		}
+		return nil, fmt.Errorf("failed to reserve email: %w", err)
+	}
+	return func(ctx context.Context, applied bool) {
+		if applied {
+			s.releaseUserEmail(ctx, current.Email, userID)
+			return
+		}
+		s.releaseUserEmail(ctx, email, userID)
+	}, nil
+}
@@ -0,0 +1,123 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// ErrValidation is wrapped by every validation failure so callers can map it
+// to a 400 (REST) or a VALIDATION-coded error (GraphQL) with errors.Is
+var ErrValidation = errors.New("validation failed")
+
+// FieldError describes a single invalid input field
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// Unwrap lets errors.Is(err, ErrValidation) match any FieldError
+func (e *FieldError) Unwrap() error { return ErrValidation }
+
+// ============================================================================
+// FIELD RULES
+// ============================================================================
+
+// Name checks that a required name-like field is not blank
+func Name(field, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return &FieldError{Field: field, Message: "must not be empty"}
+	}
+	return nil
+}
+
+// Email checks that value is a bare address (no display name, no surrounding space)
+func Email(field, value string) error {
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value {
+		return &FieldError{Field: field, Message: "must be a valid email address"}
+	}
+	return nil
+}
+
+// EmailDomain checks that value's domain is one of allowed (case-insensitive, exact
+// match - list subdomains separately). An empty allowlist or empty value passes.
+func EmailDomain(field, value string, allowed []string) error {
+	if value == "" || len(allowed) == 0 {
+		return nil
+	}
+	at := strings.LastIndex(value, "@")
+	domain := value[at+1:]
+	for _, candidate := range allowed {
+		if strings.EqualFold(domain, strings.TrimSpace(candidate)) {
+			return nil
+		}
+	}
+	return &FieldError{Field: field, Message: fmt.Sprintf("domain %q is not allowed", domain)}
+}
+
+// ============================================================================
+// REQUEST RULES - shared by the REST handlers and GraphQL resolvers
+// ============================================================================
+
+// CreateUser validates the fields required to create a user
+func CreateUser(email, firstName, lastName string) error {
+	if err := Email("email", email); err != nil {
+		return err
+	}
+	if err := Name("first_name", firstName); err != nil {
+		return err
+	}
+	return Name("last_name", lastName)
+}
+
+// CreateContact validates the fields required to create a contact.
+// Email is optional, but must be well-formed when present.
+func CreateContact(name, email string) error {
+	if err := Name("name", name); err != nil {
+		return err
+	}
+	if email != "" {
+		return Email("email", email)
+	}
+	return nil
+}
+
+// UpdateContact validates a contact update built from typed input: it must change at
+// least one field, and an email, when set, must be well-formed (empty clears it).
+func UpdateContact(updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return &FieldError{Field: "input", Message: "must set at least one field"}
+	}
+	if email, ok := updates["Email"].(string); ok && email != "" {
+		return Email("email", email)
+	}
+	return nil
+}
+
+// reservedAttributes are the key, index and bookkeeping attributes the repository
+// and service manage. Update maps come straight from client JSON, so without this
+// check a client could re-key an item, move it to another partition of GSI1, skew a
+// user's ContactCount or forge the timestamps conditional writes and the trash rely on.
+var reservedAttributes = []string{"PK", "SK", "GSI1PK", "GSI1SK", "EntityType", "CreatedAt", "UpdatedAt", "DeletedAt", "ContactCount"}
+
+// UpdateFields rejects update maps that target a reserved attribute. Names are
+// compared case-insensitively so "pk" can't sneak past as a look-alike attribute.
+func UpdateFields(updates map[string]interface{}) error {
+	for field := range updates {
+		if strings.TrimSpace(field) == "" {
+			return &FieldError{Field: field, Message: "attribute name must not be empty"}
+		}
+		for _, reserved := range reservedAttributes {
+			if strings.EqualFold(field, reserved) {
+				return &FieldError{Field: field, Message: "is a reserved attribute and cannot be updated"}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"hub-control-plane/backend/clock"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body, prefixed with "sha256="
+const SignatureHeader = "X-Hub-Signature-256"
+
+// Contact lifecycle event types
+const (
+	ContactCreated  = "contact.created"
+	ContactUpdated  = "contact.updated"
+	ContactDeleted  = "contact.deleted"
+	ContactRestored = "contact.restored"
+)
+
+// Event is the JSON body POSTed to every registered endpoint
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	UserID     string      `json:"user_id"`
+	ContactID  string      `json:"contact_id"`
+	Data       interface{} `json:"data,omitempty"`
+}
+
+// NewEvent builds an event with a fresh ID and timestamp
+func NewEvent(eventType, userID, contactID string, data interface{}) Event {
+	return Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		OccurredAt: clock.Now(),
+		UserID:     userID,
+		ContactID:  contactID,
+		Data:       data,
+	}
+}
+
+// Dispatcher delivers signed events to a fixed set of endpoint URLs.
+// Deliveries run in the background with bounded retries, so Dispatch never
+// blocks the request that triggered it. A nil *Dispatcher is a valid no-op.
+type Dispatcher struct {
+	endpoints   []string
+	secret      []byte
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration
+
+	wg sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher for the given endpoints, signing with secret
+func NewDispatcher(endpoints []string, secret string) *Dispatcher {
+	return &Dispatcher{
+		endpoints:   endpoints,
+		secret:      []byte(secret),
+		client:      &http.Client{Timeout: 5 * time.Second},
+		maxAttempts: 3,
+		backoff:     time.Second,
+	}
+}
+
+// SetRetry overrides how many times a delivery is attempted and the initial
+// backoff between attempts (doubled after each failure)
+func (d *Dispatcher) SetRetry(maxAttempts int, backoff time.Duration) {
+	d.maxAttempts = maxAttempts
+	d.backoff = backoff
+}
+
+// Dispatch queues the event for delivery to every endpoint and returns immediately
+func (d *Dispatcher) Dispatch(event Event) {
+	if d == nil || len(d.endpoints) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Warning: failed to marshal webhook event %s: %v", event.Type, err)
+		return
+	}
+	signature := Sign(d.secret, body)
+
+	for _, endpoint := range d.endpoints {
+		d.wg.Add(1)
+		go func(endpoint string) {
+			defer d.wg.Done()
+			d.deliver(endpoint, event, body, signature)
+		}(endpoint)
+	}
+}
+
+// Wait blocks until all in-flight deliveries have finished (used on shutdown)
+func (d *Dispatcher) Wait() {
+	if d == nil {
+		return
+	}
+	d.wg.Wait()
+}
+
+// deliver POSTs one event to one endpoint, retrying with exponential backoff
+func (d *Dispatcher) deliver(endpoint string, event Event, body []byte, signature string) {
+	backoff := d.backoff
+
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		err := d.post(endpoint, event, body, signature)
+		if err == nil {
+			return
+		}
+
+		if attempt == d.maxAttempts {
+			log.Printf("Warning: webhook %s (%s) to %s failed after %d attempts: %v",
+				event.ID, event.Type, endpoint, attempt, err)
+			return
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// post performs a single delivery attempt; any non-2xx response counts as failure
+func (d *Dispatcher) post(endpoint string, event Event, body []byte, signature string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.client.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", event.Type)
+	req.Header.Set("X-Webhook-ID", event.ID)
+	req.Header.Set(SignatureHeader, signature)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Sign returns the signature header value for body: "sha256=" + hex(HMAC-SHA256(secret, body)).
+// Receivers recompute it over the raw body and compare with hmac.Equal.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDispatchSendsSignedPayload(t *testing.T) {
+	const secret = "test-secret"
+
+	var (
+		gotBody      []byte
+		gotSignature string
+		gotEvent     string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSignature = r.Header.Get(SignatureHeader)
+		gotEvent = r.Header.Get("X-Webhook-Event")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	d := NewDispatcher([]string{server.URL}, secret)
+	d.Dispatch(NewEvent(ContactCreated, "user-1", "contact-1", map[string]string{"name": "Ada"}))
+	d.Wait()
+
+	if want := Sign([]byte(secret), gotBody); gotSignature != want {
+		t.Errorf("signature = %q, want %q", gotSignature, want)
+	}
+	if gotEvent != ContactCreated {
+		t.Errorf("X-Webhook-Event = %q, want %q", gotEvent, ContactCreated)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(gotBody, &payload); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	for _, field := range []string{"id", "type", "occurred_at", "user_id", "contact_id", "data"} {
+		if _, ok := payload[field]; !ok {
+			t.Errorf("payload missing %q: %s", field, gotBody)
+		}
+	}
+	if payload["type"] != ContactCreated || payload["user_id"] != "user-1" || payload["contact_id"] != "contact-1" {
+		t.Errorf("unexpected payload: %s", gotBody)
+	}
+}
+
+func TestDispatchRetriesUntilSuccess(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	d := NewDispatcher([]string{server.URL}, "secret")
+	d.SetRetry(5, time.Millisecond)
+	d.Dispatch(NewEvent(ContactDeleted, "user-1", "contact-1", nil))
+	d.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestNilDispatcherIsNoop(t *testing.T) {
+	var d *Dispatcher
+	d.Dispatch(NewEvent(ContactUpdated, "user-1", "contact-1", nil))
+	d.Wait()
+}